@@ -0,0 +1,419 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+)
+
+type (
+	csvFlags struct {
+		Files        string
+		EnsureExists bool
+		TimeFormat   string
+	}
+)
+
+func (c *csvFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.Files, "files", "",
+		"A comma separated list of csv files to load",
+	)
+	fs.BoolVar(
+		&c.EnsureExists, "ensureExists", false,
+		"Only create entries that do not already exist rather than erroring on duplicates",
+	)
+	fs.StringVar(
+		&c.TimeFormat, "timeFormat", "",
+		"The format of any dates in the csv files, as defined by the time package",
+	)
+}
+
+func (c *csvFlags) opts() *sbcsv.Opts {
+	return &sbcsv.Opts{ReuseRecord: true, TimeFormat: c.TimeFormat}
+}
+
+func (c *csvFlags) fromCSVOpts() *types.FromCSVOpts {
+	return &types.FromCSVOpts{Opts: *c.opts()}
+}
+
+func (c *csvFlags) createFuncType() types.CreateFuncType {
+	if c.EnsureExists {
+		return types.EnsureExists
+	}
+	return types.Create
+}
+
+func runBulkUpload(ctxt context.Context, name string, args []string) error {
+	var csv csvFlags
+	var barPathCalcVersion, barPathTrackerVersion int
+	var skipRejectedRows bool
+	opts := types.BulkUploadDataOpts{
+		Report: &types.BulkUploadReport{},
+		Counts: &types.UploadCounts{},
+	}
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		csv.register(fs)
+		fs.StringVar(&opts.ClientDir, "clientDir", "", "The dir of client csv files")
+		fs.StringVar(&opts.ExerciseDir, "exerciseDir", "", "The dir of exercise csv files")
+		fs.StringVar(&opts.HyperparamsDir, "hyperparamsDir", "", "The dir of hyperparam csv files")
+		fs.StringVar(&opts.WorkoutDir, "workoutDir", "", "The dir of workout csv files")
+		fs.BoolVar(
+			&opts.DryRun, "dryRun", false,
+			"Validate every file and print every problem without changing the database",
+		)
+		fs.BoolVar(
+			&skipRejectedRows, "skipRejectedRows", false,
+			"Skip rows that cannot be uploaded, writing them to <file name>.rejected.csv files, rather than stopping the upload",
+		)
+		fs.BoolVar(
+			&opts.Resume, "resume", false,
+			"Commit each file chunk as it is uploaded and skip the chunks a previous failed upload committed",
+		)
+		fs.IntVar(
+			&barPathCalcVersion, "barPathCalcVersion", -1,
+			"The bar path calc hyperparams version to calculate workout physics data with, the default hyperparams are used if <0",
+		)
+		fs.IntVar(
+			&barPathTrackerVersion, "barPathTrackerVersion", -1,
+			"The bar path tracker hyperparams version to calculate workout physics data with, the default hyperparams are used if <0",
+		)
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	opts.Opts = *csv.opts()
+	opts.ClientCreateType = csv.createFuncType()
+	opts.ExerciseCreateType = csv.createFuncType()
+	opts.HyperparamsCreateType = csv.createFuncType()
+	opts.WorkoutCreateType = csv.createFuncType()
+	if skipRejectedRows {
+		opts.ErrorPolicy = types.SkipRejectedRows
+	}
+	if opts.WorkoutDir != "" {
+		if opts.BarPathCalcHyperparams, err = hyperparamsForVersion[types.BarPathCalcHyperparams](
+			ctxt, barPathCalcVersion,
+		); err != nil {
+			return err
+		}
+		if opts.BarPathTrackerHyperparams, err = hyperparamsForVersion[types.BarPathTrackerHyperparams](
+			ctxt, barPathTrackerVersion,
+		); err != nil {
+			return err
+		}
+	}
+	if err := logic.BulkUploadData(ctxt, &opts); err != nil {
+		return err
+	}
+	if opts.DryRun {
+		return printBulkUploadReport(opts.Report)
+	}
+	if skipRejectedRows {
+		return printJSON(opts.Counts)
+	}
+	return nil
+}
+
+func runClearImportJournal(ctxt context.Context, name string, args []string) error {
+	var files string
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(
+			&files, "files", "",
+			"A comma separated list of files, as supplied to the bulk upload",
+		)
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	return logic.ClearImportJournal(ctxt, splitList(files)...)
+}
+
+// Prints the problems in the supplied report. Errors do not marshal to json so
+// each problem is printed with its error message.
+func printBulkUploadReport(report *types.BulkUploadReport) error {
+	type problem struct {
+		File   string
+		Row    int
+		Column string
+		Err    string
+	}
+	res := make([]problem, len(report.Problems))
+	for i, p := range report.Problems {
+		res[i] = problem{
+			File: p.File, Row: p.Row, Column: p.Column, Err: p.Err.Error(),
+		}
+	}
+	if err := printJSON(res); err != nil {
+		return err
+	}
+	if len(res) > 0 {
+		return fmt.Errorf("Dry run found %d problems", len(res))
+	}
+	return nil
+}
+
+// Returns the hyperparams with the supplied version, or the default hyperparams
+// if the version is <0.
+func hyperparamsForVersion[T types.Hyperparams](
+	ctxt context.Context,
+	version int,
+) (*T, error) {
+	if version < 0 {
+		res, err := logic.ReadDefaultHyperparamsFor[T](ctxt)
+		return &res, err
+	}
+	res, err := logic.ReadHyperparamsByVersionFor[T](ctxt, int32(version))
+	if err != nil {
+		return nil, err
+	}
+	return &res[0], nil
+}
+
+func runClientCreate(ctxt context.Context, name string, args []string) error {
+	var csv csvFlags
+	ctxt, cleanup, err := setup(ctxt, name, args, csv.register)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	if csv.EnsureExists {
+		return logic.EnsureClientsExistFromCSV(
+			ctxt, csv.fromCSVOpts(), splitList(csv.Files)...,
+		)
+	}
+	return logic.CreateClientsFromCSV(ctxt, csv.fromCSVOpts(), splitList(csv.Files)...)
+}
+
+func runClientRead(ctxt context.Context, name string, args []string) error {
+	var emails string
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(&emails, "emails", "", "A comma separated list of client emails")
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	res, err := logic.ReadClientsByEmail(ctxt, splitList(emails)...)
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+func runClientDelete(ctxt context.Context, name string, args []string) error {
+	var emails string
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(&emails, "emails", "", "A comma separated list of client emails")
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	return logic.DeleteClients(ctxt, splitList(emails)...)
+}
+
+func runExerciseCreate(ctxt context.Context, name string, args []string) error {
+	var csv csvFlags
+	ctxt, cleanup, err := setup(ctxt, name, args, csv.register)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	if csv.EnsureExists {
+		return logic.EnsureExercisesExistFromCSV(
+			ctxt, csv.fromCSVOpts(), splitList(csv.Files)...,
+		)
+	}
+	return logic.CreateExercisesFromCSV(ctxt, csv.fromCSVOpts(), splitList(csv.Files)...)
+}
+
+func runExerciseRead(ctxt context.Context, name string, args []string) error {
+	var names string
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(&names, "names", "", "A comma separated list of exercise names")
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	res, err := logic.ReadExercisesByName(ctxt, splitList(names)...)
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+func runExerciseDelete(ctxt context.Context, name string, args []string) error {
+	var names string
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(&names, "names", "", "A comma separated list of exercise names")
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	return logic.DeleteExercises(ctxt, splitList(names)...)
+}
+
+type (
+	hyperparamsFlags struct {
+		Type     string
+		Versions string
+	}
+)
+
+func hyperparamsTypeFlag(fs *flag.FlagSet, v *string) {
+	fs.StringVar(
+		v, "type", "",
+		fmt.Sprintf(
+			"The type of hyperparams, one of: %s, %s, %s",
+			types.BarPathCalcFileExt,
+			types.BarPathTrackerFileExt,
+			types.FitnessFatigueFileExt,
+		),
+	)
+}
+
+func (h *hyperparamsFlags) register(fs *flag.FlagSet) {
+	hyperparamsTypeFlag(fs, &h.Type)
+	fs.StringVar(
+		&h.Versions, "versions", "",
+		"A comma separated list of hyperparam versions",
+	)
+}
+
+func (h *hyperparamsFlags) versions() ([]int32, error) {
+	res := []int32{}
+	for _, v := range splitList(h.Versions) {
+		version, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return res, fmt.Errorf("Invalid hyperparams version '%s': %w", v, err)
+		}
+		res = append(res, int32(version))
+	}
+	return res, nil
+}
+
+func unknownHyperparamsType(t string) error {
+	return fmt.Errorf(
+		"Unknown hyperparams type '%s', must be one of: %s, %s, %s",
+		t,
+		types.BarPathCalcFileExt,
+		types.BarPathTrackerFileExt,
+		types.FitnessFatigueFileExt,
+	)
+}
+
+func runHyperparamsCreate(ctxt context.Context, name string, args []string) error {
+	var csv csvFlags
+	var params hyperparamsFlags
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		csv.register(fs)
+		hyperparamsTypeFlag(fs, &params.Type)
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	switch params.Type {
+	case types.BarPathCalcFileExt:
+		return createHyperparamsFromCSV[types.BarPathCalcHyperparams](ctxt, &csv)
+	case types.BarPathTrackerFileExt:
+		return createHyperparamsFromCSV[types.BarPathTrackerHyperparams](ctxt, &csv)
+	case types.FitnessFatigueFileExt:
+		return createHyperparamsFromCSV[types.FitnessFatigueHyperparams](ctxt, &csv)
+	default:
+		return unknownHyperparamsType(params.Type)
+	}
+}
+
+func createHyperparamsFromCSV[T types.Hyperparams](
+	ctxt context.Context,
+	csv *csvFlags,
+) error {
+	if csv.EnsureExists {
+		return logic.EnsureHyperparamsExistFromCSV[T](
+			ctxt, csv.fromCSVOpts(), splitList(csv.Files)...,
+		)
+	}
+	return logic.CreateHyperparamsFromCSV[T](
+		ctxt, csv.fromCSVOpts(), splitList(csv.Files)...,
+	)
+}
+
+func runHyperparamsRead(ctxt context.Context, name string, args []string) error {
+	var params hyperparamsFlags
+	ctxt, cleanup, err := setup(ctxt, name, args, params.register)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	versions, err := params.versions()
+	if err != nil {
+		return err
+	}
+
+	switch params.Type {
+	case types.BarPathCalcFileExt:
+		return readHyperparams[types.BarPathCalcHyperparams](ctxt, versions)
+	case types.BarPathTrackerFileExt:
+		return readHyperparams[types.BarPathTrackerHyperparams](ctxt, versions)
+	case types.FitnessFatigueFileExt:
+		return readHyperparams[types.FitnessFatigueHyperparams](ctxt, versions)
+	default:
+		return unknownHyperparamsType(params.Type)
+	}
+}
+
+// Prints the hyperparams with the supplied versions, or the default
+// hyperparams if no versions were supplied.
+func readHyperparams[T types.Hyperparams](
+	ctxt context.Context,
+	versions []int32,
+) error {
+	if len(versions) == 0 {
+		res, err := logic.ReadDefaultHyperparamsFor[T](ctxt)
+		if err != nil {
+			return err
+		}
+		return printJSON(res)
+	}
+	res, err := logic.ReadHyperparamsByVersionFor[T](ctxt, versions...)
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+func runHyperparamsDelete(ctxt context.Context, name string, args []string) error {
+	var params hyperparamsFlags
+	ctxt, cleanup, err := setup(ctxt, name, args, params.register)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	versions, err := params.versions()
+	if err != nil {
+		return err
+	}
+
+	switch params.Type {
+	case types.BarPathCalcFileExt:
+		return logic.DeleteHyperparams[types.BarPathCalcHyperparams](ctxt, versions...)
+	case types.BarPathTrackerFileExt:
+		return logic.DeleteHyperparams[types.BarPathTrackerHyperparams](ctxt, versions...)
+	case types.FitnessFatigueFileExt:
+		return logic.DeleteHyperparams[types.FitnessFatigueHyperparams](ctxt, versions...)
+	default:
+		return unknownHyperparamsType(params.Type)
+	}
+}
@@ -0,0 +1,251 @@
+// Providentia is a command line interface to the providentia library. It allows
+// data to be loaded into and inspected from a providentia database without
+// writing any Go code.
+//
+// Usage:
+//
+//	providentia <command> [sub command] [flags]
+//
+// Every command accepts the flags defined by [logic.ConfParser] to configure
+// the database connection, logging, job queues, etc. A toml config file can be
+// supplied with the -conf flag. Run a command with -h to see all of its flags.
+//
+// Data read from the database is printed to stdout as json.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbargp "code.barbellmath.net/barbell-math/smoothbrain-argparse"
+	sbjobqueue "code.barbellmath.net/barbell-math/smoothbrain-jobQueue"
+)
+
+type (
+	command struct {
+		Desc string
+		Run  func(ctxt context.Context, name string, args []string) error
+	}
+)
+
+const (
+	// The format that all dates supplied on the cmd line must follow.
+	dateFormat = time.DateOnly
+)
+
+var (
+	// Returned when a date flag does not match [dateFormat].
+	invalidDateErr = errors.New("Invalid date")
+
+	commands = map[string]command{
+		"migrate": {
+			Desc: "Runs all database migrations",
+			Run:  runMigrate,
+		},
+		"bulk-upload": {
+			Desc: "Uploads clients, exercises, hyperparams, and workouts from data dirs",
+			Run:  runBulkUpload,
+		},
+		"bulk-upload clear-journal": {
+			Desc: "Clears the import journal of files so a resumed bulk upload starts them over",
+			Run:  runClearImportJournal,
+		},
+		"client create": {
+			Desc: "Creates clients from csv files",
+			Run:  runClientCreate,
+		},
+		"client read": {
+			Desc: "Reads clients by email",
+			Run:  runClientRead,
+		},
+		"client delete": {
+			Desc: "Deletes clients by email",
+			Run:  runClientDelete,
+		},
+		"exercise create": {
+			Desc: "Creates exercises from csv files",
+			Run:  runExerciseCreate,
+		},
+		"exercise read": {
+			Desc: "Reads exercises by name",
+			Run:  runExerciseRead,
+		},
+		"exercise delete": {
+			Desc: "Deletes exercises by name",
+			Run:  runExerciseDelete,
+		},
+		"hyperparams create": {
+			Desc: "Creates hyperparams of a single type from csv files",
+			Run:  runHyperparamsCreate,
+		},
+		"hyperparams read": {
+			Desc: "Reads hyperparams of a single type by version",
+			Run:  runHyperparamsRead,
+		},
+		"hyperparams delete": {
+			Desc: "Deletes hyperparams of a single type by version",
+			Run:  runHyperparamsDelete,
+		},
+		"workout create": {
+			Desc: "Creates workouts from json files and prints the personal records they set",
+			Run:  runWorkoutCreate,
+		},
+		"workout read": {
+			Desc: "Reads a clients workouts by id or date range",
+			Run:  runWorkoutRead,
+		},
+		"workout delete": {
+			Desc: "Deletes a clients workouts by id or date range",
+			Run:  runWorkoutDelete,
+		},
+		"workout watch": {
+			Desc: "Watches a dir and uploads workout files as they are added to it",
+			Run:  runWorkoutWatch,
+		},
+		"serve": {
+			Desc: "Serves the http json api",
+			Run:  runServe,
+		},
+		"physics calc": {
+			Desc: "Calculates the physics data for a single time series csv file",
+			Run:  runPhysicsCalc,
+		},
+	}
+)
+
+func main() {
+	if err := run(context.Background(), os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctxt context.Context, args []string) error {
+	name, cmd, ok := lookupCommand(args)
+	if !ok {
+		usage()
+		return fmt.Errorf("Unknown command: %s", strings.Join(args, " "))
+	}
+	return cmd.Run(ctxt, name, args[len(strings.Fields(name)):])
+}
+
+// Finds the command named by the leading args, preferring commands with a sub
+// command.
+func lookupCommand(args []string) (string, command, bool) {
+	if len(args) >= 2 {
+		name := args[0] + " " + args[1]
+		if cmd, ok := commands[name]; ok {
+			return name, cmd, true
+		}
+	}
+	if len(args) >= 1 {
+		if cmd, ok := commands[args[0]]; ok {
+			return args[0], cmd, true
+		}
+	}
+	return "", command{}, false
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: providentia <command> [sub command] [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", name, commands[name].Desc)
+	}
+}
+
+// Parses the supplied args using [logic.ConfParser] along with any command
+// specific flags and returns a context holding the resulting [types.State].
+// The returned cleanup function must be called once the command is done.
+func setup(
+	ctxt context.Context,
+	name string,
+	args []string,
+	cmdFlags func(fs *flag.FlagSet),
+) (context.Context, func(), error) {
+	var conf types.Conf
+	if err := sbargp.Parse(&conf, args, sbargp.ParserOpts[types.Conf]{
+		ProgName:     "providentia " + name,
+		RequiredArgs: logic.ConfDefaultRequiredArgs(),
+		ArgDefsSetter: func(conf *types.Conf, fs *flag.FlagSet) error {
+			logic.ConfParser(fs, conf, "", logic.ConfDefaults())
+			if cmdFlags != nil {
+				cmdFlags(fs)
+			}
+			return nil
+		},
+	}); err != nil {
+		return ctxt, func() {}, err
+	}
+
+	appLifetime, appCancel := context.WithCancel(ctxt)
+	state, err := logic.ConfToState(appLifetime, &conf)
+	if err != nil {
+		appCancel()
+		logic.CleanupState(state)
+		return ctxt, func() {}, err
+	}
+	go sbjobqueue.Poll(
+		appLifetime,
+		state.PhysicsJobQueue, state.VideoJobQueue, state.CSVLoaderJobQueue,
+	)
+
+	return logic.WithStateValue(appLifetime, state), func() {
+		appCancel()
+		logic.CleanupState(state)
+	}, nil
+}
+
+// Prints the supplied value to stdout as indented json.
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// Splits a comma separated list, ignoring empty values.
+func splitList(s string) []string {
+	res := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Parses a date that was supplied on the cmd line.
+func parseDate(flagName string, s string) (time.Time, error) {
+	res, err := time.Parse(dateFormat, s)
+	if err != nil {
+		return res, fmt.Errorf(
+			"%w: -%s must be a date with the format %s: %w",
+			invalidDateErr, flagName, dateFormat, err,
+		)
+	}
+	return res, nil
+}
+
+func runMigrate(ctxt context.Context, name string, args []string) error {
+	ctxt, cleanup, err := setup(ctxt, name, args, nil)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+	return logic.RunMigrations(ctxt)
+}
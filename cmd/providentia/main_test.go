@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestLookupCommand(t *testing.T) {
+	name, _, ok := lookupCommand([]string{"migrate", "-conf", "conf.toml"})
+	sbtest.True(t, ok)
+	sbtest.Eq(t, "migrate", name)
+
+	name, _, ok = lookupCommand([]string{"workout", "read", "-email", "a@b.com"})
+	sbtest.True(t, ok)
+	sbtest.Eq(t, "workout read", name)
+
+	name, _, ok = lookupCommand([]string{"physics", "calc"})
+	sbtest.True(t, ok)
+	sbtest.Eq(t, "physics calc", name)
+
+	_, _, ok = lookupCommand([]string{"workout"})
+	sbtest.False(t, ok)
+	_, _, ok = lookupCommand([]string{"workout", "update"})
+	sbtest.False(t, ok)
+	_, _, ok = lookupCommand([]string{})
+	sbtest.False(t, ok)
+}
+
+func TestSplitList(t *testing.T) {
+	sbtest.SlicesMatch(t, []string{}, splitList(""))
+	sbtest.SlicesMatch(t, []string{"a"}, splitList("a"))
+	sbtest.SlicesMatch(t, []string{"a", "b"}, splitList(" a, ,b,"))
+}
+
+func TestParseDate(t *testing.T) {
+	res, err := parseDate("date", "2025-01-02")
+	sbtest.Nil(t, err)
+	sbtest.True(t, res.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	_, err = parseDate("date", "1/2/2025")
+	sbtest.ContainsError(
+		t, invalidDateErr, err, `-date must be a date with the format 2006-01-02`,
+	)
+	var parseErr *time.ParseError
+	sbtest.True(t, errors.As(err, &parseErr))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/api"
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+)
+
+func runServe(ctxt context.Context, name string, args []string) error {
+	var addr string
+	var shutdownTimeout time.Duration
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(&addr, "addr", ":8080", "The address to listen on")
+		fs.DurationVar(
+			&shutdownTimeout, "shutdownTimeout", 10*time.Second,
+			"How long to wait for in flight requests when shutting down",
+		)
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	state, _ := logic.StateFromContext(ctxt)
+	handler, err := api.NewHandler(state)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:        addr,
+		Handler:     handler,
+		BaseContext: func(_ net.Listener) context.Context { return ctxt },
+	}
+
+	sigCtxt, stop := signal.NotifyContext(ctxt, os.Interrupt)
+	defer stop()
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-sigCtxt.Done()
+		shutdownCtxt, cancel := context.WithTimeout(
+			context.Background(), shutdownTimeout,
+		)
+		defer cancel()
+		shutdownErr <- server.Shutdown(shutdownCtxt)
+	}()
+
+	state.Log.Info("Serving api", "Addr", addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	// In flight requests must finish before the state is cleaned up.
+	return <-shutdownErr
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+)
+
+type (
+	workoutFlags struct {
+		Email   string
+		Session uint
+		Date    string
+		Start   string
+		End     string
+	}
+)
+
+func (w *workoutFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&w.Email, "email", "", "The email of the client")
+	fs.UintVar(&w.Session, "session", 1, "The session of the workout")
+	fs.StringVar(
+		&w.Date, "date", "",
+		fmt.Sprintf("The date of the workout (%s)", dateFormat),
+	)
+	fs.StringVar(
+		&w.Start, "start", "",
+		fmt.Sprintf(
+			"The inclusive start of a date range (%s), overrides -date and -session",
+			dateFormat,
+		),
+	)
+	fs.StringVar(
+		&w.End, "end", "",
+		fmt.Sprintf("The exclusive end of a date range (%s)", dateFormat),
+	)
+}
+
+func (w *workoutFlags) isRange() bool {
+	return w.Start != ""
+}
+
+func (w *workoutFlags) id() (types.WorkoutId, error) {
+	date, err := parseDate("date", w.Date)
+	return types.WorkoutId{
+		ClientEmail:   w.Email,
+		Session:       uint16(w.Session),
+		DatePerformed: date,
+	}, err
+}
+
+func (w *workoutFlags) dateRange() (start time.Time, end time.Time, err error) {
+	if start, err = parseDate("start", w.Start); err != nil {
+		return
+	}
+	end, err = parseDate("end", w.End)
+	return
+}
+
+func runWorkoutCreate(ctxt context.Context, name string, args []string) error {
+	var files string
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(
+			&files, "files", "",
+			"A comma separated list of json files, each holding a list of workouts in the same format that `workout read` prints",
+		)
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	workouts := []types.Workout{}
+	for _, file := range splitList(files) {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		iterWorkouts := []types.Workout{}
+		if err := json.Unmarshal(data, &iterWorkouts); err != nil {
+			return fmt.Errorf("Could not parse '%s': %w", file, err)
+		}
+		workouts = append(workouts, iterWorkouts...)
+	}
+	prs, err := logic.CreateWorkouts(ctxt, workouts...)
+	if err != nil {
+		return err
+	}
+	return printJSON(prs)
+}
+
+func runWorkoutRead(ctxt context.Context, name string, args []string) error {
+	var w workoutFlags
+	ctxt, cleanup, err := setup(ctxt, name, args, w.register)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	var res []types.Workout
+	if w.isRange() {
+		start, end, err := w.dateRange()
+		if err != nil {
+			return err
+		}
+		res, err = logic.FindWorkoutsInDateRange(
+			ctxt, w.Email, start, end,
+		)
+		if err != nil {
+			return err
+		}
+	} else {
+		id, err := w.id()
+		if err != nil {
+			return err
+		}
+		if res, err = logic.ReadWorkoutsById(ctxt, id); err != nil {
+			return err
+		}
+	}
+	return printJSON(res)
+}
+
+func runWorkoutDelete(ctxt context.Context, name string, args []string) error {
+	var w workoutFlags
+	ctxt, cleanup, err := setup(ctxt, name, args, w.register)
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	if w.isRange() {
+		start, end, err := w.dateRange()
+		if err != nil {
+			return err
+		}
+		n, err := logic.DeleteWorkoutsInDateRange(
+			ctxt, w.Email, start, end,
+		)
+		if err != nil {
+			return err
+		}
+		return printJSON(map[string]int64{"NumDeleted": n})
+	}
+	id, err := w.id()
+	if err != nil {
+		return err
+	}
+	return logic.DeleteWorkouts(ctxt, id)
+}
+
+func runWorkoutWatch(ctxt context.Context, name string, args []string) error {
+	var timeFormat string
+	var barPathCalcVersion, barPathTrackerVersion int
+	opts := types.WatchWorkoutDirOpts{}
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(&opts.Dir, "dir", "", "The dir to watch for workout files")
+		fs.DurationVar(
+			&opts.PollInterval, "pollInterval", 5*time.Second,
+			"How often the dir is checked for new workout files",
+		)
+		fs.DurationVar(
+			&opts.StableFor, "stableFor", 10*time.Second,
+			"How long a workout file and its data dir must be unchanged before it is uploaded",
+		)
+		fs.StringVar(
+			&timeFormat, "timeFormat", "",
+			"The format of any dates in the workout files, as defined by the time package",
+		)
+		fs.IntVar(
+			&barPathCalcVersion, "barPathCalcVersion", -1,
+			"The bar path calc hyperparams version to calculate workout physics data with, the default hyperparams are used if <0",
+		)
+		fs.IntVar(
+			&barPathTrackerVersion, "barPathTrackerVersion", -1,
+			"The bar path tracker hyperparams version to calculate workout physics data with, the default hyperparams are used if <0",
+		)
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	opts.Opts = sbcsv.Opts{ReuseRecord: true, TimeFormat: timeFormat}
+	if opts.BarPathCalcHyperparams, err = hyperparamsForVersion[types.BarPathCalcHyperparams](
+		ctxt, barPathCalcVersion,
+	); err != nil {
+		return err
+	}
+	if opts.BarPathTrackerHyperparams, err = hyperparamsForVersion[types.BarPathTrackerHyperparams](
+		ctxt, barPathTrackerVersion,
+	); err != nil {
+		return err
+	}
+
+	sigCtxt, stop := signal.NotifyContext(ctxt, os.Interrupt)
+	defer stop()
+	return logic.WatchWorkoutDir(sigCtxt, &opts)
+}
+
+func runPhysicsCalc(ctxt context.Context, name string, args []string) error {
+	var file string
+	var weight float64
+	var reps, barPathCalcVersion int
+	ctxt, cleanup, err := setup(ctxt, name, args, func(fs *flag.FlagSet) {
+		fs.StringVar(
+			&file, "file", "",
+			"The time series csv file holding the bar path of a single set",
+		)
+		fs.Float64Var(&weight, "weight", 0, "The weight of the set in kg")
+		fs.IntVar(&reps, "reps", 1, "The number of reps in the set")
+		fs.IntVar(
+			&barPathCalcVersion, "barPathCalcVersion", -1,
+			"The bar path calc hyperparams version to use, the default hyperparams are used if <0",
+		)
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	rawData, err := logic.BarPathTimeSeriesCSV(&sbcsv.Opts{}, file)
+	if err != nil {
+		return err
+	}
+	params, err := hyperparamsForVersion[types.BarPathCalcHyperparams](
+		ctxt, barPathCalcVersion,
+	)
+	if err != nil {
+		return err
+	}
+	tracker, err := logic.ReadDefaultHyperparamsFor[types.BarPathTrackerHyperparams](ctxt)
+	if err != nil {
+		return err
+	}
+
+	exerciseData := types.ExerciseData{
+		Weight: types.Kilogram(weight),
+		Sets:   1,
+		Reps:   int32(reps),
+	}
+	if err := logic.CalcPhysicsData(
+		ctxt, params, &tracker, &exerciseData, rawData,
+	); err != nil {
+		return err
+	}
+	return printJSON(exerciseData.PhysData[0].Value)
+}
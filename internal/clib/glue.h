@@ -135,6 +135,12 @@ extern "C" {
 		double_t* avgPower;
 		wattPointInTime_t* minPower;
 		wattPointInTime_t* maxPower;
+		double_t* meanConcentricVel;
+		double_t* meanPropulsiveVel;
+		double_t* timeToPeakVel;
+		double_t* concentricDur;
+		double_t* eccentricDur;
+		double_t* rangeOfMotion;
 	} barPathData_t;
 
 #ifdef __cplusplus
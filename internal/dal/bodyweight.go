@@ -0,0 +1,312 @@
+package dal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	ReadBodyweightsInDateRangeOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		Res   *[]types.BodyweightEntry
+	}
+
+	FindLatestBodyweightOpts struct {
+		Email  string
+		Before time.Time
+		Res    *types.Found[types.BodyweightEntry]
+	}
+
+	DeleteBodyweightsInDateRangeOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		Res   *int64
+	}
+)
+
+const (
+	createBodyweightSql = `
+INSERT INTO providentia.bodyweight_log (client_id, date_measured, weight)
+SELECT providentia.client.id, $2, $3 FROM providentia.client
+WHERE providentia.client.email = $1;
+`
+
+	// Existing entries are updated with their own weight rather than ignored
+	// so that a row is always affected when the client exists.
+	ensureBodyweightExistsSql = `
+INSERT INTO providentia.bodyweight_log (client_id, date_measured, weight)
+SELECT providentia.client.id, $2, $3 FROM providentia.client
+WHERE providentia.client.email = $1
+ON CONFLICT (client_id, date_measured) DO UPDATE
+SET weight = providentia.bodyweight_log.weight;
+`
+
+	readBodyweightsInDateRangeSql = `
+SELECT
+	providentia.bodyweight_log.date_measured,
+	providentia.bodyweight_log.weight
+FROM providentia.bodyweight_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.bodyweight_log.client_id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.bodyweight_log.date_measured >= $2 AND
+	providentia.bodyweight_log.date_measured < $3
+ORDER BY providentia.bodyweight_log.date_measured ASC;
+`
+
+	findLatestBodyweightSql = `
+SELECT
+	providentia.bodyweight_log.date_measured,
+	providentia.bodyweight_log.weight
+FROM providentia.bodyweight_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.bodyweight_log.client_id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.bodyweight_log.date_measured < $2
+ORDER BY providentia.bodyweight_log.date_measured DESC
+LIMIT 1;
+`
+
+	updateBodyweightSql = `
+UPDATE providentia.bodyweight_log SET weight = $3
+FROM providentia.client
+WHERE
+	providentia.client.id = providentia.bodyweight_log.client_id AND
+	providentia.client.email = $1 AND
+	providentia.bodyweight_log.date_measured = $2;
+`
+
+	deleteBodyweightsInDateRangeSql = `
+DELETE FROM providentia.bodyweight_log
+USING providentia.client
+WHERE
+	providentia.client.id = providentia.bodyweight_log.client_id AND
+	providentia.client.email = $1 AND
+	providentia.bodyweight_log.date_measured >= $2 AND
+	providentia.bodyweight_log.date_measured < $3;
+`
+)
+
+func CreateBodyweights(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	entries []types.BodyweightEntry,
+) error {
+	return execBodyweightBatch(
+		ctxt, state, tx, entries, createBodyweightSql,
+		types.CouldNotCreateAllBodyweightsErr, "create",
+	)
+}
+
+// Creates the supplied bodyweight entries that do not already exist. Entries
+// that already exist keep their current weight.
+func EnsureBodyweightsExist(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	entries []types.BodyweightEntry,
+) error {
+	return execBodyweightBatch(
+		ctxt, state, tx, entries, ensureBodyweightExistsSql,
+		types.CouldNotCreateAllBodyweightsErr, "ensure",
+	)
+}
+
+func ReadBodyweightsInDateRange(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadBodyweightsInDateRangeOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotReadAllBodyweightsErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(
+		ctxt, readBodyweightsInDateRangeSql, opts.Email, opts.Start, opts.End,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllBodyweightsErr, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		iterEntry := types.BodyweightEntry{ClientEmail: opts.Email}
+		if err := rows.Scan(
+			&iterEntry.DateMeasured, &iterEntry.Weight,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotReadAllBodyweightsErr, err)
+		}
+		*opts.Res = append(*opts.Res, iterEntry)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllBodyweightsErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Read bodyweight entries in date range [%s, %s)",
+			opts.Start, opts.End,
+		),
+		"NumRows", len(*opts.Res),
+	)
+	return nil
+}
+
+// Finds the most recent bodyweight entry of the supplied client that was
+// measured strictly before the supplied date.
+func FindLatestBodyweight(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts FindLatestBodyweightOpts,
+) error {
+	*opts.Res = types.Found[types.BodyweightEntry]{
+		Value: types.BodyweightEntry{ClientEmail: opts.Email},
+	}
+	err := tx.QueryRow(
+		ctxt, findLatestBodyweightSql, opts.Email, opts.Before,
+	).Scan(&opts.Res.Value.DateMeasured, &opts.Res.Value.Weight)
+	if errors.Is(err, pgx.ErrNoRows) {
+		opts.Res.Value = types.BodyweightEntry{ClientEmail: opts.Email}
+		return nil
+	} else if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllBodyweightsErr, err)
+	}
+	opts.Res.Found = true
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf("DAL: Found latest bodyweight entry before %s", opts.Before),
+		"Email", opts.Email,
+	)
+	return nil
+}
+
+func UpdateBodyweights(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	entries []types.BodyweightEntry,
+) error {
+	return execBodyweightBatch(
+		ctxt, state, tx, entries, updateBodyweightSql,
+		types.CouldNotUpdateAllBodyweightsErr, "update",
+	)
+}
+
+func DeleteBodyweightsInDateRange(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts DeleteBodyweightsInDateRangeOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotDeleteAllBodyweightsErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+
+	cmdTag, err := tx.Exec(
+		ctxt, deleteBodyweightsInDateRangeSql, opts.Email, opts.Start, opts.End,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotDeleteAllBodyweightsErr, err)
+	}
+	*opts.Res = cmdTag.RowsAffected()
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Deleted bodyweight entries in date range [%s, %s)",
+			opts.Start, opts.End,
+		),
+		"NumRows", *opts.Res,
+	)
+	return nil
+}
+
+// Runs the supplied sql for each entry, in batches. The sql must take the
+// client email, date measured, and weight as its arguments, in that order, and
+// must affect exactly one row per entry.
+func execBodyweightBatch(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	entries []types.BodyweightEntry,
+	sql string,
+	opErr error,
+	op string,
+) error {
+	for i, e := range entries {
+		if e.Weight <= 0 {
+			return sberr.Wrap(
+				opErr, "Bodyweight at idx %d must be >0, got %f", i, e.Weight,
+			)
+		}
+	}
+
+	for start, end := range batchIndexes(entries, int(state.Global.BatchSize)) {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		b := pgx.Batch{}
+		for i := start; i < end; i++ {
+			b.Queue(
+				sql, entries[i].ClientEmail, entries[i].DateMeasured,
+				entries[i].Weight,
+			)
+		}
+		results := tx.SendBatch(ctxt, &b)
+
+		for i := start; i < end; i++ {
+			if cmdTag, err := results.Exec(); err != nil {
+				results.Close()
+				return sberr.AppendError(opErr, err)
+			} else if cmdTag.RowsAffected() == 0 {
+				results.Close()
+				return sberr.AppendError(
+					opErr,
+					sberr.Wrap(
+						types.NotFoundErr,
+						"Could not %s bodyweight entry at idx %d (Does client exist?)",
+						op, i,
+					),
+				)
+			}
+		}
+		results.Close()
+
+		state.Log.Log(
+			ctxt, sblog.VLevel(3),
+			"DAL: Ran bodyweight entry batch",
+			"Op", op,
+			"NumRows", end-start,
+		)
+	}
+	return nil
+}
@@ -91,6 +91,23 @@ func ReadNumClients(
 	)
 }
 
+func ReadAllClients(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	clients *[]types.Client,
+) error {
+	return genericReadAll(
+		ctxt, state, tx, &genericReadAllOpts[types.Client]{
+			TableName: clientTableName,
+			Columns:   []string{"first_name", "last_name", "email"},
+			OrderBy:   "email",
+			Res:       clients,
+			Err:       types.CouldNotReadAllClientsErr,
+		},
+	)
+}
+
 func ReadClientsByEmail(
 	ctxt context.Context,
 	state *types.State,
@@ -163,10 +180,13 @@ func UpdateClients(
 				results.Close()
 				return err
 			} else if cmdTag.RowsAffected() == 0 {
-				return sberr.Wrap(
+				return sberr.AppendError(
 					types.CouldNotUpdateAllClientsErr,
-					"Could not update client at idx %d (Does client exist?)",
-					i,
+					sberr.Wrap(
+						types.NotFoundErr,
+						"Could not update client at idx %d (Does client exist?)",
+						i,
+					),
 				)
 			}
 		}
@@ -0,0 +1,143 @@
+package dal
+
+import (
+	"context"
+	"errors"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	ReadClientAttributesOpts struct {
+		Emails []string
+		Res    *[]types.ClientAttributes
+	}
+)
+
+const (
+	setClientAttributesSql = `
+INSERT INTO providentia.client_attributes (client_id, sex)
+SELECT providentia.client.id, $2 FROM providentia.client
+WHERE providentia.client.email = $1
+ON CONFLICT (client_id) DO UPDATE SET sex = EXCLUDED.sex;
+`
+
+	readClientAttributesSql = `
+SELECT COALESCE(providentia.client_attributes.sex, 0)
+FROM providentia.client
+LEFT JOIN providentia.client_attributes
+	ON providentia.client_attributes.client_id = providentia.client.id
+WHERE providentia.client.email = $1;
+`
+)
+
+func SetClientAttributes(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	attrs []types.ClientAttributes,
+) error {
+	for i, a := range attrs {
+		if !a.Sex.IsValid() {
+			return sberr.AppendError(
+				types.CouldNotSetAllClientAttributesErr,
+				sberr.Wrap(types.InvalidSexErr, "Idx %d: Got: %s", i, a.Sex),
+			)
+		}
+	}
+
+	for start, end := range batchIndexes(attrs, int(state.Global.BatchSize)) {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		b := pgx.Batch{}
+		for i := start; i < end; i++ {
+			b.Queue(setClientAttributesSql, attrs[i].Email, attrs[i].Sex)
+		}
+		results := tx.SendBatch(ctxt, &b)
+
+		for i := start; i < end; i++ {
+			if cmdTag, err := results.Exec(); err != nil {
+				results.Close()
+				return sberr.AppendError(
+					types.CouldNotSetAllClientAttributesErr, err,
+				)
+			} else if cmdTag.RowsAffected() == 0 {
+				results.Close()
+				return sberr.AppendError(
+					types.CouldNotSetAllClientAttributesErr,
+					sberr.Wrap(
+						types.NotFoundErr,
+						"Could not set client attributes at idx %d (Does client exist?)",
+						i,
+					),
+				)
+			}
+		}
+		results.Close()
+
+		state.Log.Log(
+			ctxt, sblog.VLevel(3),
+			"DAL: Set client attributes",
+			"NumRows", end-start,
+		)
+	}
+	return nil
+}
+
+func ReadClientAttributes(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadClientAttributesOpts,
+) error {
+	*opts.Res = (*opts.Res)[:0]
+	for start, end := range batchIndexes(opts.Emails, int(state.Global.BatchSize)) {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		b := pgx.Batch{}
+		for i := start; i < end; i++ {
+			b.Queue(readClientAttributesSql, opts.Emails[i])
+		}
+		results := tx.SendBatch(ctxt, &b)
+
+		for i := start; i < end; i++ {
+			iterAttrs := types.ClientAttributes{Email: opts.Emails[i]}
+			if err := results.QueryRow().Scan(&iterAttrs.Sex); err != nil {
+				results.Close()
+				if errors.Is(err, pgx.ErrNoRows) {
+					return sberr.AppendError(
+						types.CouldNotReadAllClientAttributesErr,
+						sberr.Wrap(
+							types.NotFoundErr,
+							"Could not read client attributes at idx %d (Does client exist?)",
+							i,
+						),
+					)
+				}
+				return sberr.AppendError(
+					types.CouldNotReadAllClientAttributesErr, err,
+				)
+			}
+			*opts.Res = append(*opts.Res, iterAttrs)
+		}
+		results.Close()
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Read client attributes",
+		"NumRows", len(*opts.Res),
+	)
+	return nil
+}
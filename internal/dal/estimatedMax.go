@@ -0,0 +1,240 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	ReadEstimatedMaxesInDateRangeOpts struct {
+		Email    string
+		Exercise string
+		Start    time.Time
+		End      time.Time
+		Formula  types.EstimatedMaxFormula
+		Res      *[]types.EstimatedMaxEntry
+	}
+
+	ReadMainLiftEstimatedMaxesOpts struct {
+		Email   string
+		Start   time.Time
+		End     time.Time
+		Formula types.EstimatedMaxFormula
+		Res     *[]MainLiftEstimatedMax
+	}
+
+	MainLiftEstimatedMax struct {
+		Focus types.ExerciseFocus
+		types.EstimatedMaxEntry
+	}
+)
+
+const (
+	readEstimatedMaxEntriesInDateRangeSql = `
+SELECT
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.weight,
+	providentia.training_log.reps,
+	providentia.training_log.effort
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.exercise.name = $2 AND
+	providentia.training_log.date_performed >= $3 AND
+	providentia.training_log.date_performed < $4
+ORDER BY
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr ASC;
+`
+
+	readMainLiftEstimatedMaxEntriesSql = `
+SELECT
+	providentia.exercise.focus_id,
+	providentia.exercise.name,
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.weight,
+	providentia.training_log.reps,
+	providentia.training_log.effort
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.exercise.kind_id = $2 AND
+	providentia.exercise.focus_id = ANY($3) AND
+	providentia.training_log.date_performed >= $4 AND
+	providentia.training_log.date_performed < $5
+ORDER BY
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr ASC;
+`
+)
+
+func ReadEstimatedMaxesInDateRange(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadEstimatedMaxesInDateRangeOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotReadAllEstimatedMaxesErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+	if !opts.Formula.IsValid() {
+		return sberr.AppendError(
+			types.CouldNotReadAllEstimatedMaxesErr,
+			sberr.Wrap(
+				types.InvalidEstimatedMaxFormulaErr, "Got: %s", opts.Formula,
+			),
+		)
+	}
+
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(
+		ctxt, readEstimatedMaxEntriesInDateRangeSql,
+		opts.Email, opts.Exercise, opts.Start, opts.End,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllEstimatedMaxesErr, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		iterEntry := types.EstimatedMaxEntry{
+			WorkoutId: types.WorkoutId{ClientEmail: opts.Email},
+			Exercise:  opts.Exercise,
+		}
+		if err := rows.Scan(
+			&iterEntry.DatePerformed,
+			&iterEntry.Session,
+			&iterEntry.Weight,
+			&iterEntry.Reps,
+			&iterEntry.Effort,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotReadAllEstimatedMaxesErr, err)
+		}
+
+		e1rm := estimatedMax(
+			opts.Formula, iterEntry.Weight, iterEntry.Reps, iterEntry.Effort,
+		)
+		if !e1rm.Present {
+			continue
+		}
+		iterEntry.EstimatedMax = e1rm.Value
+		*opts.Res = append(*opts.Res, iterEntry)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllEstimatedMaxesErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Read estimated maxes in date range [%s, %s)",
+			opts.Start, opts.End,
+		),
+		"Exercise", opts.Exercise,
+		"Formula", opts.Formula,
+		"NumEntries", len(*opts.Res),
+	)
+	return nil
+}
+
+// Reads the estimated max of every squat, bench, and deadlift training log
+// entry of the supplied client in the supplied date range. Only exercises with
+// a kind of [types.MainCompound] are read.
+func ReadMainLiftEstimatedMaxes(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadMainLiftEstimatedMaxesOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotReadAllEstimatedMaxesErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+	if !opts.Formula.IsValid() {
+		return sberr.AppendError(
+			types.CouldNotReadAllEstimatedMaxesErr,
+			sberr.Wrap(
+				types.InvalidEstimatedMaxFormulaErr, "Got: %s", opts.Formula,
+			),
+		)
+	}
+
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(
+		ctxt, readMainLiftEstimatedMaxEntriesSql,
+		opts.Email, int32(types.MainCompound),
+		[]int32{int32(types.Squat), int32(types.Bench), int32(types.Deadlift)},
+		opts.Start, opts.End,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllEstimatedMaxesErr, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		iterEntry := MainLiftEstimatedMax{
+			EstimatedMaxEntry: types.EstimatedMaxEntry{
+				WorkoutId: types.WorkoutId{ClientEmail: opts.Email},
+			},
+		}
+		if err := rows.Scan(
+			&iterEntry.Focus,
+			&iterEntry.Exercise,
+			&iterEntry.DatePerformed,
+			&iterEntry.Session,
+			&iterEntry.Weight,
+			&iterEntry.Reps,
+			&iterEntry.Effort,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotReadAllEstimatedMaxesErr, err)
+		}
+
+		e1rm := estimatedMax(
+			opts.Formula, iterEntry.Weight, iterEntry.Reps, iterEntry.Effort,
+		)
+		if !e1rm.Present {
+			continue
+		}
+		iterEntry.EstimatedMax = e1rm.Value
+		*opts.Res = append(*opts.Res, iterEntry)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllEstimatedMaxesErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Read main lift estimated maxes in date range [%s, %s)",
+			opts.Start, opts.End,
+		),
+		"Formula", opts.Formula,
+		"NumEntries", len(*opts.Res),
+	)
+	return nil
+}
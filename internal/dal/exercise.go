@@ -108,6 +108,23 @@ func ReadNumExercises(
 	)
 }
 
+func ReadAllExercises(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	exercises *[]types.Exercise,
+) error {
+	return genericReadAll(
+		ctxt, state, tx, &genericReadAllOpts[types.Exercise]{
+			TableName: exerciseTableName,
+			Columns:   []string{"name", "kind_id", "focus_id"},
+			OrderBy:   "name",
+			Res:       exercises,
+			Err:       types.CouldNotReadAllExercisesErr,
+		},
+	)
+}
+
 func ReadExercisesByName(
 	ctxt context.Context,
 	state *types.State,
@@ -45,6 +45,14 @@ type (
 		Res       *int64
 	}
 
+	genericReadAllOpts[T any] struct {
+		Res       *[]T
+		TableName string
+		Columns   []string
+		OrderBy   string
+		Err       error
+	}
+
 	genericReadByUniqueIdOpts[T any, U any] struct {
 		Ids        []T
 		Res        *[]U
@@ -92,6 +100,8 @@ INSERT INTO providentia.%s (%s) VALUES (%s) ON CONFLICT (%s) DO NOTHING;
 
 	readTotalNumSql = `SELECT COUNT(*) FROM providentia.%s;`
 
+	readAllSql = `SELECT %s FROM providentia.%s ORDER BY %s;`
+
 	readByUniqueIdSql = `
 SELECT %s FROM providentia.%s JOIN UNNEST($1::%s[])
 WITH ORDINALITY t(%s, ord)
@@ -301,6 +311,43 @@ func genericReadTotalNum(
 	return row.Scan(opts.Res)
 }
 
+func genericReadAll[T any](
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts *genericReadAllOpts[T],
+) error {
+	*opts.Res = (*opts.Res)[:0]
+	sql := fmt.Sprintf(
+		readAllSql, strings.Join(opts.Columns, ", "),
+		opts.TableName, opts.OrderBy,
+	)
+
+	rows, err := tx.Query(ctxt, sql)
+	if err != nil {
+		return sberr.AppendError(opts.Err, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		iterRes, err := pgx.RowToStructByName[T](rows)
+		if err != nil {
+			return sberr.AppendError(opts.Err, err)
+		}
+		*opts.Res = append(*opts.Res, iterRes)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(opts.Err, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf("DAL: Read all %ss", opts.TableName),
+		"NumRows", len(*opts.Res),
+	)
+	return nil
+}
+
 func genericReadByUniqueId[T any, U any](
 	ctxt context.Context,
 	state *types.State,
@@ -338,10 +385,13 @@ func genericReadByUniqueId[T any, U any](
 		rows.Close()
 
 		if cntr != end {
-			return sberr.Wrap(
+			return sberr.AppendError(
 				opts.Err,
-				"Only read %d entries out of batch of %d requests",
-				cntr-start, end-start,
+				sberr.Wrap(
+					types.NotFoundErr,
+					"Only read %d entries out of batch of %d requests",
+					cntr-start, end-start,
+				),
 			)
 		}
 
@@ -441,10 +491,13 @@ func genericDeleteByUniqueId[T any](
 				return sberr.AppendError(opts.Err, err)
 			} else if cmdTag.RowsAffected() == 0 {
 				results.Close()
-				return sberr.Wrap(
+				return sberr.AppendError(
 					opts.Err,
-					"Could not delete entry with id '%v' (Does id exist?)",
-					opts.Ids[i],
+					sberr.Wrap(
+						types.NotFoundErr,
+						"Could not delete entry with id '%v' (Does id exist?)",
+						opts.Ids[i],
+					),
 				)
 			}
 		}
@@ -44,6 +44,13 @@ WITH ORDINALITY t(version, ord)
 USING (version)
 WHERE model_id=$2
 ORDER BY ord;
+`
+
+	readAllHyperparamsForSql = `
+SELECT version, params
+FROM providentia.hyperparams
+WHERE model_id = $1
+ORDER BY version;
 `
 
 	findHyperparamsByVersionForSql = `
@@ -67,6 +74,8 @@ func getModelIdFor[T types.Hyperparams]() types.ModelID {
 		return types.BarPathCalc
 	case *types.BarPathTrackerHyperparams:
 		return types.BarPathTracker
+	case *types.FitnessFatigueHyperparams:
+		return types.FitnessFatigue
 	}
 	return types.UnknownModel
 }
@@ -77,6 +86,8 @@ func getVersionFrom[T types.Hyperparams](v *T) int32 {
 		return params.Version
 	case *types.BarPathTrackerHyperparams:
 		return params.Version
+	case *types.FitnessFatigueHyperparams:
+		return params.Version
 	}
 	return 0
 }
@@ -87,6 +98,8 @@ func setVersionTo[T types.Hyperparams](v *T, version int32) {
 		params.Version = version
 	case *types.BarPathTrackerHyperparams:
 		params.Version = version
+	case *types.FitnessFatigueHyperparams:
+		params.Version = version
 	}
 }
 
@@ -156,6 +169,44 @@ func validateHyperparams[T types.Hyperparams](v *T) (opErr error) {
 				),
 			)
 		}
+	case *types.FitnessFatigueHyperparams:
+		if params.TimeFrame == 0 {
+			return sberr.AppendError(
+				types.InvalidFitnessFatigueErr,
+				sberr.Wrap(
+					types.InvalidTimeFrameErr,
+					"Must be >0. Got: %d", params.TimeFrame,
+				),
+			)
+		}
+		if params.FatigueDecay <= 0 {
+			return sberr.AppendError(
+				types.InvalidFitnessFatigueErr,
+				sberr.Wrap(
+					types.InvalidDecayErr,
+					"Fatigue decay must be >0. Got: %f", params.FatigueDecay,
+				),
+			)
+		}
+		if params.FitnessDecay <= params.FatigueDecay {
+			return sberr.AppendError(
+				types.InvalidFitnessFatigueErr,
+				sberr.Wrap(
+					types.InvalidDecayErr,
+					"Fitness decay (%f) must be > fatigue decay (%f)",
+					params.FitnessDecay, params.FatigueDecay,
+				),
+			)
+		}
+		if params.MinNumSamples < 3 {
+			return sberr.AppendError(
+				types.InvalidFitnessFatigueErr,
+				sberr.Wrap(
+					types.InvalidMinNumSamplesErr,
+					"Must be >=3. Got: %d", params.MinNumSamples,
+				),
+			)
+		}
 	}
 	return nil
 }
@@ -286,10 +337,13 @@ func ReadHyperparamsByVersionFor[T types.Hyperparams](
 		rows.Close()
 
 		if cntr != end {
-			return sberr.Wrap(
+			return sberr.AppendError(
 				types.CouldNotReadAllHyperparamsErr,
-				"Only read %d entries out of batch of %d requests",
-				cntr-start, end-start,
+				sberr.Wrap(
+					types.NotFoundErr,
+					"Only read %d entries out of batch of %d requests",
+					cntr-start, end-start,
+				),
 			)
 		}
 
@@ -302,6 +356,45 @@ func ReadHyperparamsByVersionFor[T types.Hyperparams](
 	return nil
 }
 
+func ReadAllHyperparamsFor[T types.Hyperparams](
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	params *[]T,
+) error {
+	*params = (*params)[:0]
+	modelId := getModelIdFor[T]()
+	rows, err := tx.Query(ctxt, readAllHyperparamsForSql, modelId)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllHyperparamsErr, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		iterRes, err := pgx.RowToStructByName[versionParamRes](rows)
+		if err != nil {
+			return sberr.AppendError(types.CouldNotReadAllHyperparamsErr, err)
+		}
+
+		var iterParams T
+		if err = json.Unmarshal(iterRes.Params, &iterParams); err != nil {
+			return sberr.AppendError(types.CouldNotReadAllHyperparamsErr, err)
+		}
+		setVersionTo(&iterParams, iterRes.Version)
+		*params = append(*params, iterParams)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllHyperparamsErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf("DAL: Read all hyperparams for %s", modelId),
+		"NumRows", len(*params),
+	)
+	return nil
+}
+
 func ReadDefaultHyperparamsFor[T types.Hyperparams](
 	ctxt context.Context,
 	state *types.State,
@@ -421,10 +514,13 @@ func DeleteHyperparams[T types.Hyperparams](
 				)
 			} else if cmdTag.RowsAffected() == 0 {
 				results.Close()
-				return sberr.Wrap(
+				return sberr.AppendError(
 					types.CouldNotDeleteAllHyperparamsErr,
-					"Could not delete entry with version '%d' (Does it exist?)",
-					versions[i],
+					sberr.Wrap(
+						types.NotFoundErr,
+						"Could not delete entry with version '%d' (Does it exist?)",
+						versions[i],
+					),
 				)
 			}
 		}
@@ -0,0 +1,120 @@
+package dal
+
+import (
+	"context"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	ReadImportJournalOpts struct {
+		File string
+		Res  *[]types.ImportJournalEntry
+	}
+)
+
+const (
+	createImportJournalEntrySql = `
+INSERT INTO providentia.import_journal (file, chunk, file_hash, chunk_hash)
+VALUES ($1, $2, $3, $4);
+`
+
+	readImportJournalSql = `
+SELECT chunk, file_hash, chunk_hash FROM providentia.import_journal
+WHERE file = $1 ORDER BY chunk;
+`
+
+	deleteImportJournalSql = `
+DELETE FROM providentia.import_journal WHERE file = ANY($1);
+`
+)
+
+func CreateImportJournalEntries(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	entries []types.ImportJournalEntry,
+) error {
+	for start, end := range batchIndexes(entries, int(state.Global.BatchSize)) {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		b := pgx.Batch{}
+		for i := start; i < end; i++ {
+			b.Queue(
+				createImportJournalEntrySql, entries[i].File, entries[i].Chunk,
+				entries[i].FileHash, entries[i].ChunkHash,
+			)
+		}
+		if err := tx.SendBatch(ctxt, &b).Close(); err != nil {
+			return sberr.AppendError(types.CouldNotCreateImportJournalErr, err)
+		}
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Created import journal entries",
+		"NumRows", len(entries),
+	)
+	return nil
+}
+
+func ReadImportJournal(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadImportJournalOpts,
+) error {
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(ctxt, readImportJournalSql, opts.File)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadImportJournalErr, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		iterEntry := types.ImportJournalEntry{File: opts.File}
+		if err := rows.Scan(
+			&iterEntry.Chunk, &iterEntry.FileHash, &iterEntry.ChunkHash,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotReadImportJournalErr, err)
+		}
+		*opts.Res = append(*opts.Res, iterEntry)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadImportJournalErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Read import journal entries",
+		"File", opts.File,
+		"NumRows", len(*opts.Res),
+	)
+	return nil
+}
+
+func DeleteImportJournal(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	files []string,
+) error {
+	cmdTag, err := tx.Exec(ctxt, deleteImportJournalSql, files)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotDeleteImportJournalErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Deleted import journal entries",
+		"NumRows", cmdTag.RowsAffected(),
+	)
+	return nil
+}
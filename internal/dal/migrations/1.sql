@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS model_state_client_id_idx
+	ON providentia.model_state (client_id, hyperparams_id);
+
+ALTER TABLE providentia.model_state
+	ADD COLUMN estimated_max_formula INT4 NOT NULL;
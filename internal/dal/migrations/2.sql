@@ -0,0 +1,7 @@
+ALTER TABLE providentia.physics_data
+	ADD COLUMN IF NOT EXISTS mean_concentric_vel FLOAT8[] NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS mean_propulsive_vel FLOAT8[] NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS time_to_peak_vel FLOAT8[] NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS concentric_dur FLOAT8[] NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS eccentric_dur FLOAT8[] NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS range_of_motion FLOAT8[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS providentia.client_attributes (
+	client_id INT8 NOT NULL PRIMARY KEY REFERENCES providentia.client(id) ON DELETE CASCADE,
+	sex INT4 NOT NULL DEFAULT 0 CHECK (sex >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS providentia.bodyweight_log (
+	id SERIAL8 NOT NULL PRIMARY KEY,
+	client_id INT8 NOT NULL REFERENCES providentia.client(id) ON DELETE CASCADE,
+	date_measured DATE NOT NULL,
+	weight FLOAT8 NOT NULL CHECK (weight > 0),
+
+	UNIQUE(client_id, date_measured)
+);
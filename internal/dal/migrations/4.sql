@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS providentia.import_journal (
+	file TEXT NOT NULL,
+	chunk INT4 NOT NULL CHECK (chunk >= 0),
+	file_hash BYTEA NOT NULL,
+	chunk_hash BYTEA NOT NULL,
+	completed TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+	PRIMARY KEY(file, chunk)
+);
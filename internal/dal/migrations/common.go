@@ -45,6 +45,20 @@ var PostOps = map[sbsqlm.Migration]sbsqlm.PostMigrationOp[*types.State]{
 			return err
 		}
 
+		return nil
+	},
+	1: func(ctxt context.Context, tx pgx.Tx, state *types.State) error {
+		if err := dal.CreateModelsWithID(
+			ctxt, state, tx, FitnessFatigueModelSetupData,
+		); err != nil {
+			return err
+		}
+		if err := dal.CreateHyperparams(
+			ctxt, state, tx, FitnessFatigueHyperparamsSetupData,
+		); err != nil {
+			return err
+		}
+
 		return nil
 	},
 }
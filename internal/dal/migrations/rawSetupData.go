@@ -44,6 +44,24 @@ var (
 		},
 	}
 
+	FitnessFatigueModelSetupData = []dal.CreateModelsWithIDOpts{
+		{
+			ModelID: types.FitnessFatigue,
+			Name:    types.FitnessFatigue.String(),
+			Desc:    "Controls for the algorithims that fit a clients estimated max to the fitness and fatigue generated by their training.",
+		},
+	}
+
+	FitnessFatigueHyperparamsSetupData = []types.FitnessFatigueHyperparams{
+		{
+			Version:       0,
+			TimeFrame:     180,
+			FitnessDecay:  42,
+			FatigueDecay:  7,
+			MinNumSamples: 5,
+		},
+	}
+
 	ExerciseFocusSetupData = []dal.CreateExerciseFocusWithIDOpts{
 		{
 			ExerciseFocus: types.UnknownExerciseFocus,
@@ -0,0 +1,262 @@
+package dal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	// A single training log entry as it is used when fitting models.
+	TrainingLogEntry struct {
+		Id            int64          `db:"id"`
+		ExerciseName  string         `db:"name"`
+		DatePerformed time.Time      `db:"date_performed"`
+		Weight        types.Kilogram `db:"weight"`
+		Sets          float64        `db:"sets"`
+		Reps          int32          `db:"reps"`
+		Effort        types.RPE      `db:"effort"`
+		Volume        types.Kilogram `db:"volume"`
+	}
+
+	ReadTrainingLogForClientOpts struct {
+		Email string
+		Res   *[]TrainingLogEntry
+	}
+
+	// The fitted state of a model for a single training log entry. The meaning
+	// of each value in V depends on the model. Formula is the estimated max
+	// formula the state was fit with.
+	ModelState struct {
+		TrainingLogId int64
+		V             [10]float64
+		TimeFrame     int64
+		MSE           float64
+		PredWeight    types.Kilogram
+		Formula       types.EstimatedMaxFormula
+	}
+
+	CreateModelStatesOpts struct {
+		Email   string
+		ModelId types.ModelID
+		Version int32
+		Formula types.EstimatedMaxFormula
+		States  []ModelState
+	}
+
+	ReadLatestModelStateOpts struct {
+		Email    string
+		Exercise string
+		Date     time.Time
+		ModelId  types.ModelID
+		Version  int32
+		Res      *ModelState
+		Found    *bool
+	}
+)
+
+const (
+	readTrainingLogForClientSql = `
+SELECT
+	providentia.training_log.id,
+	providentia.exercise.name,
+	providentia.training_log.date_performed,
+	providentia.training_log.weight,
+	providentia.training_log.sets,
+	providentia.training_log.reps,
+	providentia.training_log.effort,
+	providentia.training_log.volume
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+WHERE providentia.client.email = $1
+ORDER BY
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr;
+`
+
+	deleteModelStatesForClientSql = `
+DELETE FROM providentia.model_state
+USING providentia.client, providentia.hyperparams
+WHERE
+	providentia.client.id = providentia.model_state.client_id AND
+	providentia.hyperparams.id = providentia.model_state.hyperparams_id AND
+	providentia.client.email = $1 AND
+	providentia.hyperparams.model_id = $2 AND
+	providentia.hyperparams.version = $3;
+`
+
+	createModelStateSql = `
+INSERT INTO providentia.model_state (
+	client_id, training_log_id, hyperparams_id,
+	v1, v2, v3, v4, v5, v6, v7, v8, v9, v10,
+	time_frame, mse, pred_weight, estimated_max_formula
+) VALUES (
+	(
+		SELECT providentia.training_log.client_id
+		FROM providentia.training_log
+		WHERE providentia.training_log.id = $1
+	),
+	$1,
+	(
+		SELECT providentia.hyperparams.id FROM providentia.hyperparams
+		WHERE
+			providentia.hyperparams.model_id = $2 AND
+			providentia.hyperparams.version = $3
+	),
+	$4, $5, $6, $7, $8, $9, $10, $11, $12, $13,
+	$14, $15, $16, $17
+);
+`
+
+	readLatestModelStateSql = `
+SELECT
+	providentia.model_state.training_log_id,
+	providentia.model_state.v1,
+	providentia.model_state.v2,
+	providentia.model_state.v3,
+	providentia.model_state.v4,
+	providentia.model_state.v5,
+	providentia.model_state.v6,
+	providentia.model_state.v7,
+	providentia.model_state.v8,
+	providentia.model_state.v9,
+	providentia.model_state.v10,
+	providentia.model_state.time_frame,
+	providentia.model_state.mse,
+	providentia.model_state.pred_weight,
+	providentia.model_state.estimated_max_formula
+FROM providentia.model_state
+JOIN providentia.training_log
+	ON providentia.training_log.id = providentia.model_state.training_log_id
+JOIN providentia.client
+	ON providentia.client.id = providentia.model_state.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+JOIN providentia.hyperparams
+	ON providentia.hyperparams.id = providentia.model_state.hyperparams_id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.exercise.name = $2 AND
+	providentia.training_log.date_performed <= $3 AND
+	providentia.hyperparams.model_id = $4 AND
+	providentia.hyperparams.version = $5
+ORDER BY
+	providentia.training_log.date_performed DESC,
+	providentia.training_log.inter_session_cntr DESC,
+	providentia.training_log.inter_workout_cntr DESC
+LIMIT 1;
+`
+)
+
+func ReadTrainingLogForClient(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadTrainingLogForClientOpts,
+) error {
+	rows, err := tx.Query(ctxt, readTrainingLogForClientSql, opts.Email)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllTrainingLogsErr, err)
+	}
+	*opts.Res, err = pgx.AppendRows(
+		(*opts.Res)[:0], rows, pgx.RowToStructByName[TrainingLogEntry],
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllTrainingLogsErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Read training log for client",
+		"NumRows", len(*opts.Res),
+	)
+	return nil
+}
+
+// Replaces all model states for the supplied client and hyperparams with the
+// supplied model states. Every state is recorded as being fit with the supplied
+// formula.
+func CreateModelStates(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts CreateModelStatesOpts,
+) error {
+	if _, err := tx.Exec(
+		ctxt, deleteModelStatesForClientSql,
+		opts.Email, opts.ModelId, opts.Version,
+	); err != nil {
+		return sberr.AppendError(types.CouldNotCreateAllModelStatesErr, err)
+	}
+
+	for start, end := range batchIndexes(opts.States, int(state.Global.BatchSize)) {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		b := pgx.Batch{}
+		for i := start; i < end; i++ {
+			s := &opts.States[i]
+			b.Queue(
+				createModelStateSql,
+				s.TrainingLogId, opts.ModelId, opts.Version,
+				s.V[0], s.V[1], s.V[2], s.V[3], s.V[4],
+				s.V[5], s.V[6], s.V[7], s.V[8], s.V[9],
+				s.TimeFrame, s.MSE, s.PredWeight, opts.Formula,
+			)
+		}
+		if err := tx.SendBatch(ctxt, &b).Close(); err != nil {
+			return sberr.AppendError(types.CouldNotCreateAllModelStatesErr, err)
+		}
+
+		state.Log.Log(
+			ctxt, sblog.VLevel(3),
+			fmt.Sprintf("DAL: Created model states for %s", opts.ModelId),
+			"NumRows", end-start,
+		)
+	}
+	return nil
+}
+
+// Reads the model state of the most recent training log entry for the supplied
+// exercise that was performed on or before the supplied date.
+func ReadLatestModelState(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadLatestModelStateOpts,
+) error {
+	s := opts.Res
+	err := tx.QueryRow(
+		ctxt, readLatestModelStateSql,
+		opts.Email, opts.Exercise, opts.Date, opts.ModelId, opts.Version,
+	).Scan(
+		&s.TrainingLogId,
+		&s.V[0], &s.V[1], &s.V[2], &s.V[3], &s.V[4],
+		&s.V[5], &s.V[6], &s.V[7], &s.V[8], &s.V[9],
+		&s.TimeFrame, &s.MSE, &s.PredWeight, &s.Formula,
+	)
+	*opts.Found = err == nil
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return sberr.AppendError(types.CouldNotReadAllModelStatesErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf("DAL: Read latest model state for %s", opts.ModelId),
+		"Found", *opts.Found,
+	)
+	return nil
+}
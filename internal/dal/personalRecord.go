@@ -0,0 +1,345 @@
+package dal
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	personalrecord "code.barbellmath.net/barbell-math/providentia/internal/models/personalRecord"
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	ReadPersonalRecordsOpts struct {
+		Email     string
+		Exercises []string
+		// When true only the most recent record of each kind is returned,
+		// otherwise every record that was ever set is returned.
+		CurrentOnly bool
+		Res         *[]types.PersonalRecord
+	}
+
+	personalRecordWorkout struct {
+		Session       uint16
+		DatePerformed time.Time
+	}
+)
+
+const (
+	readPersonalRecordEntriesSql = `
+SELECT
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.reps,
+	providentia.training_log.effort,
+	peak.vel
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+LEFT JOIN LATERAL (
+	SELECT MAX(rep_max_vel.pt[1]) AS vel
+	FROM providentia.training_log_to_physics_data
+	JOIN providentia.physics_data
+		ON providentia.training_log_to_physics_data.physics_id = providentia.physics_data.id
+	CROSS JOIN unnest(providentia.physics_data.max_vel) AS rep_max_vel(pt)
+	WHERE providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id
+) AS peak ON TRUE
+WHERE
+	providentia.client.email = $1 AND
+	(cardinality($2::TEXT[]) = 0 OR providentia.exercise.name = ANY($2)) AND
+	providentia.training_log.reps > 0
+ORDER BY
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr ASC;
+`
+
+	// Reads the distinct entries of a client for the supplied exercises,
+	// excluding the entries of the supplied workouts. The peak velocity is
+	// only read for the supplied exercise and weight pairs, which are the only
+	// ones a new entry can set a velocity record for, so the physics data of
+	// the rest of the history is never unnested.
+	readOtherPersonalRecordEntriesSql = `
+SELECT
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.reps,
+	providentia.training_log.effort,
+	MAX(peak.vel)
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+LEFT JOIN LATERAL (
+	SELECT MAX(rep_max_vel.pt[1]) AS vel
+	FROM providentia.training_log_to_physics_data
+	JOIN providentia.physics_data
+		ON providentia.training_log_to_physics_data.physics_id = providentia.physics_data.id
+	CROSS JOIN unnest(providentia.physics_data.max_vel) AS rep_max_vel(pt)
+	WHERE
+		providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id AND
+		(providentia.exercise.name, providentia.training_log.weight) IN (
+			SELECT * FROM unnest($5::TEXT[], $6::FLOAT8[])
+		)
+) AS peak ON TRUE
+WHERE
+	providentia.client.email = $1 AND
+	providentia.exercise.name = ANY($2) AND
+	providentia.training_log.reps > 0 AND
+	NOT EXISTS (
+		SELECT 1 FROM unnest($3::DATE[], $4::INT2[]) AS w(date_performed, session)
+		WHERE
+			w.date_performed = providentia.training_log.date_performed AND
+			w.session = providentia.training_log.inter_session_cntr
+	)
+GROUP BY
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.reps,
+	providentia.training_log.effort;
+`
+)
+
+func ReadPersonalRecords(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadPersonalRecordsOpts,
+) error {
+	*opts.Res = (*opts.Res)[:0]
+	if err := detectPersonalRecords(
+		ctxt, state, tx, opts.Email, opts.Exercises, opts.Res,
+	); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllPersonalRecordsErr, err)
+	}
+	if opts.CurrentOnly {
+		*opts.Res = personalrecord.Current(*opts.Res)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3), "DAL: Read personal records",
+		"Email", opts.Email,
+		"CurrentOnly", opts.CurrentOnly,
+		"NumRecords", len(*opts.Res),
+	)
+	return nil
+}
+
+// Appends the personal records that were set by the supplied workouts to res.
+// The entries of the supplied workouts are compared against every other entry
+// of the same client and exercise in the database, including entries that
+// were performed after them, so only records that are still current are
+// appended. The workouts must already have been created.
+func detectNewPersonalRecords(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	workouts []types.Workout,
+	res *[]types.PersonalRecord,
+) error {
+	type velKey struct {
+		Exercise string
+		Weight   types.Kilogram
+	}
+	type clientData struct {
+		Entries   []personalrecord.Entry
+		Exercises []string
+		Workouts  map[personalRecordWorkout]struct{}
+		VelKeys   map[velKey]struct{}
+	}
+
+	clients := map[string]*clientData{}
+	clientOrder := []string{}
+	for _, w := range workouts {
+		c, ok := clients[w.ClientEmail]
+		if !ok {
+			c = &clientData{
+				Workouts: map[personalRecordWorkout]struct{}{},
+				VelKeys:  map[velKey]struct{}{},
+			}
+			clients[w.ClientEmail] = c
+			clientOrder = append(clientOrder, w.ClientEmail)
+		}
+		c.Workouts[personalRecordWorkout{
+			Session:       w.Session,
+			DatePerformed: util.TruncDate(w.DatePerformed),
+		}] = struct{}{}
+		for _, e := range w.Exercises {
+			if e.Reps <= 0 {
+				continue
+			}
+			iterEntry := personalrecord.Entry{
+				DatePerformed: util.TruncDate(w.DatePerformed),
+				Session:       w.Session,
+				Exercise:      e.Name,
+				Weight:        e.Weight,
+				Reps:          e.Reps,
+				Effort:        e.Effort,
+			}
+			for _, p := range e.PhysData {
+				if !p.Present {
+					continue
+				}
+				for _, v := range p.Value.MaxVel {
+					if !iterEntry.PeakVel.Present || v.Value > iterEntry.PeakVel.Value {
+						iterEntry.PeakVel = types.Optional[types.MeterPerSec]{
+							Present: true, Value: v.Value,
+						}
+					}
+				}
+			}
+			if iterEntry.PeakVel.Present {
+				c.VelKeys[velKey{Exercise: e.Name, Weight: e.Weight}] = struct{}{}
+			}
+			c.Entries = append(c.Entries, iterEntry)
+			if !slices.Contains(c.Exercises, e.Name) {
+				c.Exercises = append(c.Exercises, e.Name)
+			}
+		}
+	}
+
+	for _, email := range clientOrder {
+		c := clients[email]
+		if len(c.Entries) == 0 {
+			continue
+		}
+		// Entries of the same session keep the order they were supplied in
+		slices.SortStableFunc(c.Entries, func(l, r personalrecord.Entry) int {
+			if d := l.DatePerformed.Compare(r.DatePerformed); d != 0 {
+				return d
+			}
+			return cmp.Compare(l.Session, r.Session)
+		})
+
+		dates := make([]time.Time, 0, len(c.Workouts))
+		sessions := make([]int16, 0, len(c.Workouts))
+		for w := range c.Workouts {
+			dates = append(dates, w.DatePerformed)
+			sessions = append(sessions, int16(w.Session))
+		}
+		velExercises := make([]string, 0, len(c.VelKeys))
+		velWeights := make([]float64, 0, len(c.VelKeys))
+		for k := range c.VelKeys {
+			velExercises = append(velExercises, k.Exercise)
+			velWeights = append(velWeights, float64(k.Weight))
+		}
+
+		other, err := readOtherPersonalRecordEntries(
+			ctxt, tx, email, c.Exercises,
+			dates, sessions, velExercises, velWeights,
+		)
+		if err != nil {
+			return sberr.AppendError(types.CouldNotDetectPersonalRecordsErr, err)
+		}
+		personalrecord.DetectNew(
+			email, c.Entries, other, state.Global.EstimatedMaxFormula, res,
+		)
+	}
+	return nil
+}
+
+func readOtherPersonalRecordEntries(
+	ctxt context.Context,
+	tx pgx.Tx,
+	email string,
+	exercises []string,
+	dates []time.Time,
+	sessions []int16,
+	velExercises []string,
+	velWeights []float64,
+) ([]personalrecord.Entry, error) {
+	rows, err := tx.Query(
+		ctxt, readOtherPersonalRecordEntriesSql,
+		email, exercises, dates, sessions, velExercises, velWeights,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []personalrecord.Entry{}
+	for rows.Next() {
+		var iterEntry personalrecord.Entry
+		var peakVel *float64
+		if err := rows.Scan(
+			&iterEntry.Exercise,
+			&iterEntry.Weight,
+			&iterEntry.Reps,
+			&iterEntry.Effort,
+			&peakVel,
+		); err != nil {
+			return nil, err
+		}
+		if peakVel != nil {
+			iterEntry.PeakVel = types.Optional[types.MeterPerSec]{
+				Present: true,
+				Value:   types.MeterPerSec(*peakVel),
+			}
+		}
+		res = append(res, iterEntry)
+	}
+	return res, rows.Err()
+}
+
+// Appends every personal record the supplied client has set for the supplied
+// exercises to res. If no exercises are supplied records for all exercises are
+// detected.
+func detectPersonalRecords(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	email string,
+	exercises []string,
+	res *[]types.PersonalRecord,
+) error {
+	if exercises == nil {
+		exercises = []string{}
+	}
+	rows, err := tx.Query(ctxt, readPersonalRecordEntriesSql, email, exercises)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	entries := []personalrecord.Entry{}
+	for rows.Next() {
+		var iterEntry personalrecord.Entry
+		var peakVel *float64
+		if err := rows.Scan(
+			&iterEntry.DatePerformed,
+			&iterEntry.Session,
+			&iterEntry.Exercise,
+			&iterEntry.Weight,
+			&iterEntry.Reps,
+			&iterEntry.Effort,
+			&peakVel,
+		); err != nil {
+			return err
+		}
+		if peakVel != nil {
+			iterEntry.PeakVel = types.Optional[types.MeterPerSec]{
+				Present: true,
+				Value:   types.MeterPerSec(*peakVel),
+			}
+		}
+		entries = append(entries, iterEntry)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	personalrecord.Detect(
+		email, entries, state.Global.EstimatedMaxFormula, res,
+	)
+	return nil
+}
@@ -13,11 +13,101 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+type (
+	// Identifies a single set of an exercise in the training log.
+	trainingLogSet struct {
+		TrainingLog *trainingLog
+		SetNum      int32
+	}
+
+	// The weight of a set along with the mean concentric velocity of each of
+	// its reps.
+	SetVelocityData struct {
+		Weight            types.Kilogram
+		MeanConcentricVel []types.MeterPerSec
+	}
+
+	// The mean concentric velocity of each rep of a set along with the data
+	// that identifies the set.
+	SetRepVelData struct {
+		DatePerformed     time.Time
+		Session           uint16
+		Exercise          string
+		Weight            types.Kilogram
+		Reps              int32
+		SetNum            int32
+		MeanConcentricVel []types.MeterPerSec
+	}
+
+	ReadSetRepVelDataInDateRangeOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		Res   *[]SetRepVelData
+	}
+
+	ReadSetVelocityDataOpts struct {
+		Email    string
+		Exercise string
+		Res      *[]SetVelocityData
+	}
+)
+
 const (
 	physicsDataTableName = "physics_data"
 
+	// The physics data columns of a single set of a training log entry. Every
+	// query that selects these columns must also include physicsDataJoinsSql
+	// and scan the columns with physicsDataScanTargets.
+	physicsDataSelectSql = `
+	COALESCE(bar_path_calc.version, 0),
+	COALESCE(bar_path_track.version, 0),
+	COALESCE(providentia.physics_data.path, ''),
+	providentia.physics_data.time,
+	providentia.physics_data.position,
+	providentia.physics_data.velocity,
+	providentia.physics_data.acceleration,
+	providentia.physics_data.jerk,
+	providentia.physics_data.force,
+	providentia.physics_data.impulse,
+	providentia.physics_data.work,
+	providentia.physics_data.power,
+	providentia.physics_data.rep_splits,
+	providentia.physics_data.min_vel,
+	providentia.physics_data.max_vel,
+	providentia.physics_data.min_acc,
+	providentia.physics_data.max_acc,
+	providentia.physics_data.min_force,
+	providentia.physics_data.max_force,
+	providentia.physics_data.min_impulse,
+	providentia.physics_data.max_impulse,
+	providentia.physics_data.avg_work,
+	providentia.physics_data.min_work,
+	providentia.physics_data.max_work,
+	providentia.physics_data.avg_power,
+	providentia.physics_data.min_power,
+	providentia.physics_data.max_power,
+	providentia.physics_data.mean_concentric_vel,
+	providentia.physics_data.mean_propulsive_vel,
+	providentia.physics_data.time_to_peak_vel,
+	providentia.physics_data.concentric_dur,
+	providentia.physics_data.eccentric_dur,
+	providentia.physics_data.range_of_motion`
+
+	// Joins the physics data of every set of a training log entry. Entries
+	// without physics data are still returned with NULL physics data columns.
+	physicsDataJoinsSql = `
+LEFT JOIN providentia.training_log_to_physics_data
+	ON providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id
+LEFT JOIN providentia.physics_data
+	ON providentia.training_log_to_physics_data.physics_id = providentia.physics_data.id
+LEFT JOIN providentia.hyperparams AS bar_path_calc
+	ON bar_path_calc.id = providentia.physics_data.bar_path_calc_id
+LEFT JOIN providentia.hyperparams AS bar_path_track
+	ON bar_path_track.id = providentia.physics_data.bar_path_track_id`
+
 	barPathCalcIdSelectSql = `(
-	SELECT providentia.model.id FROM providentia.hyperparams
+	SELECT providentia.hyperparams.id FROM providentia.hyperparams
 	JOIN providentia.model
 		ON providentia.model.id = providentia.hyperparams.model_id
 	WHERE providentia.model.name='%s'
@@ -25,13 +115,65 @@ const (
 )`
 
 	barPathTrackerIdSelectSql = `(
-	SELECT providentia.model.id FROM providentia.hyperparams
+	SELECT providentia.hyperparams.id FROM providentia.hyperparams
 	JOIN providentia.model
 		ON providentia.model.id = providentia.hyperparams.model_id
 	WHERE providentia.model.name='%s'
 		AND providentia.hyperparams.version=$3
 )`
 
+	readSetVelocityDataSql = `
+SELECT
+	providentia.training_log.weight,
+	providentia.physics_data.mean_concentric_vel
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+JOIN providentia.training_log_to_physics_data
+	ON providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id
+JOIN providentia.physics_data
+	ON providentia.training_log_to_physics_data.physics_id = providentia.physics_data.id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.exercise.name = $2
+ORDER BY
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr,
+	providentia.training_log_to_physics_data.set_num;
+`
+
+	readSetRepVelDataInDateRangeSql = `
+SELECT
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.reps,
+	providentia.training_log_to_physics_data.set_num+1,
+	providentia.physics_data.mean_concentric_vel
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+JOIN providentia.training_log_to_physics_data
+	ON providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id
+JOIN providentia.physics_data
+	ON providentia.training_log_to_physics_data.physics_id = providentia.physics_data.id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.training_log.date_performed >= $2 AND
+	providentia.training_log.date_performed < $3
+ORDER BY
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr,
+	providentia.training_log_to_physics_data.set_num;
+`
+
 	deletePhysicsDataByIdSql = `
 DELETE FROM providentia.physics_data
 USING (
@@ -48,6 +190,23 @@ USING (
 	tmp.date_performed = $3;
 `
 
+	deletePhysicsDataBySetSql = `
+DELETE FROM providentia.physics_data
+USING
+	providentia.training_log_to_physics_data,
+	providentia.training_log,
+	providentia.client
+WHERE
+	providentia.physics_data.id = providentia.training_log_to_physics_data.physics_id AND
+	providentia.training_log.id = providentia.training_log_to_physics_data.training_log_id AND
+	providentia.client.id = providentia.training_log.client_id AND
+	providentia.client.email = $1 AND
+	providentia.training_log.inter_session_cntr = $2 AND
+	providentia.training_log.date_performed = $3 AND
+	providentia.training_log.inter_workout_cntr = $4 AND
+	providentia.training_log_to_physics_data.set_num = $5;
+`
+
 	deletePhysicsDataBetweenDatesSql = `
 DELETE FROM providentia.physics_data
 USING (
@@ -86,12 +245,15 @@ func createPhysicsDataReturningIds(
 				"min_impulse", "max_impulse",
 				"avg_work", "min_work", "max_work",
 				"avg_power", "min_power", "max_power",
+				"mean_concentric_vel", "mean_propulsive_vel",
+				"time_to_peak_vel", "concentric_dur", "eccentric_dur",
+				"range_of_motion",
 			},
 			ValueGetter: func(
 				v *genericCreateReturningIdVal[*types.PhysicsData],
 				res *[]any,
 			) error {
-				*res = make([]any, 27)
+				*res = make([]any, 33)
 				(*res)[0] = v.Val.VideoPath
 				(*res)[1] = v.Val.BarPathCalcVersion
 				(*res)[2] = v.Val.BarPathTrackerVersion
@@ -119,6 +281,12 @@ func createPhysicsDataReturningIds(
 				(*res)[24] = v.Val.AvgPower
 				(*res)[25] = *(*[]genericPoint)(unsafe.Pointer(&v.Val.MinPower))
 				(*res)[26] = *(*[]genericPoint)(unsafe.Pointer(&v.Val.MaxPower))
+				(*res)[27] = v.Val.MeanConcentricVel
+				(*res)[28] = v.Val.MeanPropulsiveVel
+				(*res)[29] = v.Val.TimeToPeakVel
+				(*res)[30] = v.Val.ConcentricDur
+				(*res)[31] = v.Val.EccentricDur
+				(*res)[32] = v.Val.RangeOfMotion
 				return nil
 			},
 			ModifyValuePlaceholders: func(placeholders []string) []string {
@@ -181,6 +349,52 @@ func deletePhysicsDataById(
 	return nil
 }
 
+func deletePhysicsDataBySet(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	data []trainingLogSet,
+) error {
+	for start, end := range batchIndexes(data, int(state.Global.BatchSize)) {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		b := pgx.Batch{}
+		for i := start; i < end; i++ {
+			tl := data[i].TrainingLog
+			b.Queue(
+				deletePhysicsDataBySetSql,
+				tl.ClientEmail, tl.InterSessionCntr, tl.DatePerformed,
+				tl.InterWorkoutCntr, data[i].SetNum,
+			)
+		}
+		results := tx.SendBatch(ctxt, &b)
+
+		for i := start; i < end; i++ {
+			// It is possible there is no physics data associated with a set.
+			// That is ok, do not check cmdTag and do not return an error.
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return sberr.AppendError(
+					types.CouldNotDeleteAllPhysicsDataErr, err,
+				)
+			}
+		}
+		results.Close()
+
+		state.Log.Log(
+			ctxt, sblog.VLevel(3),
+			"DAL: Deleted physics_data entries by set",
+			"NumRows", end-start,
+		)
+	}
+
+	return nil
+}
+
 func deletePhysicsDataInDateRange(
 	ctxt context.Context,
 	state *types.State,
@@ -207,3 +421,141 @@ func deletePhysicsDataInDateRange(
 	)
 	return nil
 }
+
+// Reads the weight and the stored mean concentric velocity of each rep of
+// every set of the supplied exercise that has physics data for the supplied
+// client.
+func ReadSetVelocityData(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadSetVelocityDataOpts,
+) error {
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(
+		ctxt, readSetVelocityDataSql, opts.Email, opts.Exercise,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllPhysicsDataErr, err)
+	}
+
+	for rows.Next() {
+		var iterResult SetVelocityData
+		if err := rows.Scan(
+			&iterResult.Weight,
+			&iterResult.MeanConcentricVel,
+		); err != nil {
+			rows.Close()
+			return sberr.AppendError(types.CouldNotReadAllPhysicsDataErr, err)
+		}
+		*opts.Res = append(*opts.Res, iterResult)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllPhysicsDataErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Read set velocity data",
+		"NumRows", len(*opts.Res),
+	)
+	return nil
+}
+
+// Reads the mean concentric velocity of each rep of every set that has physics
+// data for the supplied client in the supplied date range. `Start` is inclusive
+// and `End` is exclusive.
+func ReadSetRepVelDataInDateRange(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadSetRepVelDataInDateRangeOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotReadAllPhysicsDataErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(
+		ctxt, readSetRepVelDataInDateRangeSql,
+		opts.Email, opts.Start, opts.End,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllPhysicsDataErr, err)
+	}
+
+	for rows.Next() {
+		var iterResult SetRepVelData
+		if err := rows.Scan(
+			&iterResult.DatePerformed,
+			&iterResult.Session,
+			&iterResult.Exercise,
+			&iterResult.Weight,
+			&iterResult.Reps,
+			&iterResult.SetNum,
+			&iterResult.MeanConcentricVel,
+		); err != nil {
+			rows.Close()
+			return sberr.AppendError(types.CouldNotReadAllPhysicsDataErr, err)
+		}
+		*opts.Res = append(*opts.Res, iterResult)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllPhysicsDataErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Read set rep velocity data in date range [%s, %s)",
+			opts.Start, opts.End,
+		),
+		"NumRows", len(*opts.Res),
+	)
+	return nil
+}
+
+// Returns the scan targets for the columns of physicsDataSelectSql.
+func physicsDataScanTargets(res *types.PhysicsData) []any {
+	return []any{
+		&res.BarPathCalcVersion,
+		&res.BarPathTrackerVersion,
+		&res.VideoPath,
+		&res.Time,
+		(*[]genericPoint)(unsafe.Pointer(&res.Position)),
+		(*[]genericPoint)(unsafe.Pointer(&res.Velocity)),
+		(*[]genericPoint)(unsafe.Pointer(&res.Acceleration)),
+		(*[]genericPoint)(unsafe.Pointer(&res.Jerk)),
+		(*[]genericPoint)(unsafe.Pointer(&res.Force)),
+		(*[]genericPoint)(unsafe.Pointer(&res.Impulse)),
+		&res.Work,
+		&res.Power,
+		(*[]genericPoint)(unsafe.Pointer(&res.RepSplits)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MinVel)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MaxVel)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MinAcc)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MaxAcc)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MinForce)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MaxForce)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MinImpulse)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MaxImpulse)),
+		&res.AvgWork,
+		(*[]genericPoint)(unsafe.Pointer(&res.MinWork)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MaxWork)),
+		&res.AvgPower,
+		(*[]genericPoint)(unsafe.Pointer(&res.MinPower)),
+		(*[]genericPoint)(unsafe.Pointer(&res.MaxPower)),
+		&res.MeanConcentricVel,
+		&res.MeanPropulsiveVel,
+		&res.TimeToPeakVel,
+		&res.ConcentricDur,
+		&res.EccentricDur,
+		&res.RangeOfMotion,
+	}
+}
@@ -0,0 +1,144 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	AggregateTrainingOpts struct {
+		Email       string
+		Start       time.Time
+		End         time.Time
+		Granularity types.TrainingGranularity
+		GroupBy     types.TrainingGroupBy
+		Res         *[]types.TrainingAggregate
+	}
+)
+
+const (
+	// The group column is supplied from [trainingGroupByCols] so it is never
+	// user supplied text.
+	aggregateTrainingSql = `
+SELECT
+	date_trunc($4::TEXT, providentia.training_log.date_performed::TIMESTAMP)::DATE AS bucket,
+	%s AS grp,
+	COUNT(*),
+	SUM(providentia.training_log.volume),
+	AVG(providentia.training_log.volume),
+	SUM(providentia.training_log.exertion),
+	AVG(providentia.training_log.exertion),
+	SUM(providentia.training_log.total_reps),
+	AVG(providentia.training_log.total_reps)
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+JOIN providentia.exercise_focus
+	ON providentia.exercise_focus.id = providentia.exercise.focus_id
+JOIN providentia.exercise_kind
+	ON providentia.exercise_kind.id = providentia.exercise.kind_id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.training_log.date_performed >= $2 AND
+	providentia.training_log.date_performed < $3
+GROUP BY bucket, grp
+ORDER BY bucket, grp ASC;
+`
+)
+
+var (
+	trainingGranularityUnits = map[types.TrainingGranularity]string{
+		types.Daily:   "day",
+		types.Weekly:  "week",
+		types.Monthly: "month",
+	}
+
+	trainingGroupByCols = map[types.TrainingGroupBy]string{
+		types.ByExercise:      "providentia.exercise.name",
+		types.ByExerciseFocus: "providentia.exercise_focus.focus",
+		types.ByExerciseKind:  "providentia.exercise_kind.kind",
+	}
+)
+
+func AggregateTraining(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts AggregateTrainingOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotAggregateTrainingErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+	unit, ok := trainingGranularityUnits[opts.Granularity]
+	if !ok {
+		return sberr.AppendError(
+			types.CouldNotAggregateTrainingErr,
+			sberr.Wrap(
+				types.InvalidTrainingGranularityErr,
+				"Got: %s", opts.Granularity,
+			),
+		)
+	}
+	groupCol, ok := trainingGroupByCols[opts.GroupBy]
+	if !ok {
+		return sberr.AppendError(
+			types.CouldNotAggregateTrainingErr,
+			sberr.Wrap(types.InvalidTrainingGroupByErr, "Got: %s", opts.GroupBy),
+		)
+	}
+
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(
+		ctxt, fmt.Sprintf(aggregateTrainingSql, groupCol),
+		opts.Email, opts.Start, opts.End, unit,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotAggregateTrainingErr, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var iterRes types.TrainingAggregate
+		if err := rows.Scan(
+			&iterRes.BucketStart,
+			&iterRes.Group,
+			&iterRes.NumEntries,
+			&iterRes.TotalVolume,
+			&iterRes.AvgVolume,
+			&iterRes.TotalExertion,
+			&iterRes.AvgExertion,
+			&iterRes.TotalReps,
+			&iterRes.AvgTotalReps,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotAggregateTrainingErr, err)
+		}
+		*opts.Res = append(*opts.Res, iterRes)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotAggregateTrainingErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Aggregated training in date range [%s, %s)",
+			opts.Start, opts.End,
+		),
+		"Granularity", opts.Granularity,
+		"GroupBy", opts.GroupBy,
+		"NumRows", len(*opts.Res),
+	)
+	return nil
+}
@@ -0,0 +1,105 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	// The summed volume and exertion of all training log entries on a single
+	// day.
+	DailyTrainingLoad struct {
+		DatePerformed time.Time
+		Volume        types.Kilogram
+		Exertion      types.RPE
+	}
+
+	ReadDailyTrainingLoadOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		Focus *types.ExerciseFocus
+		Res   *[]DailyTrainingLoad
+	}
+)
+
+const (
+	readDailyTrainingLoadSql = `
+SELECT
+	providentia.training_log.date_performed,
+	SUM(providentia.training_log.volume),
+	SUM(providentia.training_log.exertion)
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.training_log.date_performed >= $2 AND
+	providentia.training_log.date_performed < $3 AND
+	($4::INT4 IS NULL OR providentia.exercise.focus_id = $4)
+GROUP BY providentia.training_log.date_performed
+ORDER BY providentia.training_log.date_performed ASC;
+`
+)
+
+func ReadDailyTrainingLoad(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadDailyTrainingLoadOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotReadAllTrainingLogsErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+	if opts.Focus != nil && !opts.Focus.IsValid() {
+		return sberr.AppendError(
+			types.CouldNotReadAllTrainingLogsErr,
+			sberr.Wrap(types.InvalidExerciseFocusErr, "Got: %s", *opts.Focus),
+		)
+	}
+
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(
+		ctxt, readDailyTrainingLoadSql,
+		opts.Email, opts.Start, opts.End, opts.Focus,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllTrainingLogsErr, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var iterRes DailyTrainingLoad
+		if err := rows.Scan(
+			&iterRes.DatePerformed, &iterRes.Volume, &iterRes.Exertion,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotReadAllTrainingLogsErr, err)
+		}
+		*opts.Res = append(*opts.Res, iterRes)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllTrainingLogsErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Read daily training load in date range [%s, %s)",
+			opts.Start, opts.End,
+		),
+		"NumDays", len(*opts.Res),
+	)
+	return nil
+}
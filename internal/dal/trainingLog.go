@@ -40,6 +40,36 @@ const (
 	WHERE providentia.exercise.name=$2
 )`
 
+	updateTrainingLogSql = `
+UPDATE providentia.training_log SET
+	exercise_id = (
+		SELECT providentia.exercise.id FROM providentia.exercise
+		WHERE providentia.exercise.name = $5
+	),
+	weight = $6,
+	sets = $7,
+	reps = $8,
+	effort = $9
+FROM providentia.client
+WHERE
+	providentia.client.id = providentia.training_log.client_id AND
+	providentia.client.email = $1 AND
+	providentia.training_log.inter_session_cntr = $2 AND
+	providentia.training_log.date_performed = $3 AND
+	providentia.training_log.inter_workout_cntr = $4;
+`
+
+	trainingLogIdSql = `
+SELECT providentia.training_log.id FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+WHERE
+	providentia.client.email = $1 AND
+	providentia.training_log.inter_session_cntr = $2 AND
+	providentia.training_log.date_performed = $3 AND
+	providentia.training_log.inter_workout_cntr = $4;
+`
+
 	deleteTrainingLogsByIdSql = `
 DELETE FROM providentia.training_log
 USING providentia.client
@@ -109,6 +139,109 @@ func createTrainingLogsReturningIds(
 	)
 }
 
+func updateTrainingLogs(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	data []trainingLog,
+) error {
+	for start, end := range batchIndexes(data, int(state.Global.BatchSize)) {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		b := pgx.Batch{}
+		for i := start; i < end; i++ {
+			b.Queue(
+				updateTrainingLogSql,
+				data[i].ClientEmail, data[i].InterSessionCntr,
+				data[i].DatePerformed, data[i].InterWorkoutCntr,
+				data[i].ExerciseName, data[i].Weight, data[i].Sets,
+				data[i].Reps, data[i].Effort,
+			)
+		}
+		results := tx.SendBatch(ctxt, &b)
+
+		for i := start; i < end; i++ {
+			if cmdTag, err := results.Exec(); err != nil {
+				results.Close()
+				return sberr.AppendError(
+					types.CouldNotUpdateAllTrainingLogsErr, err,
+				)
+			} else if cmdTag.RowsAffected() == 0 {
+				results.Close()
+				return sberr.AppendError(
+					types.CouldNotUpdateAllTrainingLogsErr,
+					sberr.Wrap(
+						types.NotFoundErr,
+						"Could not update exercise %d of workout with id '%+v' (Does id exist?)",
+						data[i].InterWorkoutCntr,
+						types.WorkoutId{
+							ClientEmail:   data[i].ClientEmail,
+							Session:       uint16(data[i].InterSessionCntr),
+							DatePerformed: data[i].DatePerformed,
+						},
+					),
+				)
+			}
+		}
+		results.Close()
+
+		state.Log.Log(
+			ctxt, sblog.VLevel(3),
+			"DAL: Updated training_log entries",
+			"NumRows", end-start,
+		)
+	}
+
+	return nil
+}
+
+func readTrainingLogIds(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	data []genericCreateReturningIdVal[trainingLog],
+) error {
+	for start, end := range batchIndexes(data, int(state.Global.BatchSize)) {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		b := pgx.Batch{}
+		for i := start; i < end; i++ {
+			b.Queue(
+				trainingLogIdSql,
+				data[i].Val.ClientEmail, data[i].Val.InterSessionCntr,
+				data[i].Val.DatePerformed, data[i].Val.InterWorkoutCntr,
+			)
+		}
+		results := tx.SendBatch(ctxt, &b)
+
+		for i := start; i < end; i++ {
+			if err := results.QueryRow().Scan(&data[i].Id); err != nil {
+				results.Close()
+				return sberr.AppendError(
+					types.CouldNotUpdateAllTrainingLogsErr, err,
+				)
+			}
+		}
+		results.Close()
+
+		state.Log.Log(
+			ctxt, sblog.VLevel(3),
+			"DAL: Read training_log ids",
+			"NumRows", end-start,
+		)
+	}
+
+	return nil
+}
+
 func deleteTrainingLogsById(
 	ctxt context.Context,
 	state *types.State,
@@ -139,10 +272,13 @@ func deleteTrainingLogsById(
 				)
 			} else if cmdTag.RowsAffected() == 0 {
 				results.Close()
-				return sberr.Wrap(
+				return sberr.AppendError(
 					types.CouldNotDeleteAllTrainingLogsErr,
-					"Could not delete entry with id '%+v' (Does id exist?)",
-					ids[i],
+					sberr.Wrap(
+						types.NotFoundErr,
+						"Could not delete entry with id '%+v' (Does id exist?)",
+						ids[i],
+					),
 				)
 			}
 		}
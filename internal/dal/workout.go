@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"math"
 	"time"
-	"unsafe"
 
+	estimatedmax "code.barbellmath.net/barbell-math/providentia/internal/models/estimatedMax"
+	velocityloss "code.barbellmath.net/barbell-math/providentia/internal/models/velocityLoss"
 	"code.barbellmath.net/barbell-math/providentia/internal/util"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
 	sbarena "code.barbellmath.net/barbell-math/smoothbrain-arena"
@@ -16,6 +17,13 @@ import (
 )
 
 type (
+	CreateWorkoutsOpts struct {
+		Workouts []types.Workout
+		// When not nil, the personal records set by the created workouts are
+		// appended to this slice.
+		PRs *[]types.PersonalRecord
+	}
+
 	ReadNumWorkoutsForClientOpts struct {
 		Email string
 		Res   *int64
@@ -38,6 +46,25 @@ type (
 		Res   *[]types.Workout
 	}
 
+	// Identifies an exercise, or a set within an exercise, by its indexes in
+	// a slice of workouts.
+	WorkoutIdx struct {
+		Workout  int
+		Exercise int
+		Set      int
+	}
+
+	UpdateWorkoutsOpts struct {
+		Workouts []types.Workout
+		// The exercises that need their training log entries updated. The Set
+		// field is ignored.
+		Exercises []WorkoutIdx
+		// The sets that need their physics data replaced. Sets that do not
+		// have physics data present in Workouts will have any existing physics
+		// data deleted.
+		Sets []WorkoutIdx
+	}
+
 	DeleteWorkoutsInDateRangeOpts struct {
 		Email string
 		Start time.Time
@@ -71,6 +98,21 @@ type (
 )
 
 const (
+	existingWorkoutIdsSql = `
+SELECT ids.email, ids.date_performed, ids.session
+FROM unnest($1::TEXT[], $2::DATE[], $3::INT2[])
+	AS ids(email, date_performed, session)
+WHERE EXISTS (
+	SELECT 1 FROM providentia.training_log
+	JOIN providentia.client
+		ON providentia.training_log.client_id = providentia.client.id
+	WHERE
+		providentia.client.email = ids.email AND
+		providentia.training_log.date_performed = ids.date_performed AND
+		providentia.training_log.inter_session_cntr = ids.session
+);
+`
+
 	readNumWorkoutsForClientSql = `
 SELECT COUNT(*) FROM (
 	SELECT date_performed, inter_session_cntr
@@ -92,43 +134,12 @@ SELECT
 	providentia.training_log.effort,
 	providentia.training_log.volume,
 	providentia.training_log.exertion,
-	providentia.training_log.total_reps,
-	COALESCE(providentia.physics_data.bar_path_calc_id, 0),
-	COALESCE(providentia.physics_data.bar_path_track_id, 0),
-	COALESCE(providentia.physics_data.path, ''),
-	providentia.physics_data.time,
-	providentia.physics_data.position,
-	providentia.physics_data.velocity,
-	providentia.physics_data.acceleration,
-	providentia.physics_data.jerk,
-	providentia.physics_data.force,
-	providentia.physics_data.impulse,
-	providentia.physics_data.work,
-	providentia.physics_data.power,
-	providentia.physics_data.rep_splits,
-	providentia.physics_data.min_vel,
-	providentia.physics_data.max_vel,
-	providentia.physics_data.max_acc,
-	providentia.physics_data.max_acc,
-	providentia.physics_data.max_force,
-	providentia.physics_data.max_force,
-	providentia.physics_data.max_impulse,
-	providentia.physics_data.max_impulse,
-	providentia.physics_data.avg_work,
-	providentia.physics_data.max_work,
-	providentia.physics_data.max_work,
-	providentia.physics_data.avg_power,
-	providentia.physics_data.max_power,
-	providentia.physics_data.max_power
+	providentia.training_log.total_reps,` + physicsDataSelectSql + `
 FROM providentia.training_log
 JOIN providentia.client
 	ON providentia.client.id = providentia.training_log.client_id
 JOIN providentia.exercise
-	ON providentia.exercise.id = providentia.training_log.exercise_id
-LEFT JOIN providentia.training_log_to_physics_data
-	ON providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id
-LEFT JOIN providentia.physics_data
-	ON providentia.training_log_to_physics_data.physics_id = providentia.physics_data.id
+	ON providentia.exercise.id = providentia.training_log.exercise_id` + physicsDataJoinsSql + `
 WHERE
 	email = $1 AND
 	inter_session_cntr = $2 AND
@@ -148,43 +159,12 @@ SELECT
 	providentia.training_log.effort,
 	providentia.training_log.volume,
 	providentia.training_log.exertion,
-	providentia.training_log.total_reps,
-	COALESCE(providentia.physics_data.bar_path_calc_id, 0),
-	COALESCE(providentia.physics_data.bar_path_track_id, 0),
-	COALESCE(providentia.physics_data.path, ''),
-	providentia.physics_data.time,
-	providentia.physics_data.position,
-	providentia.physics_data.velocity,
-	providentia.physics_data.acceleration,
-	providentia.physics_data.jerk,
-	providentia.physics_data.force,
-	providentia.physics_data.impulse,
-	providentia.physics_data.work,
-	providentia.physics_data.power,
-	providentia.physics_data.rep_splits,
-	providentia.physics_data.min_vel,
-	providentia.physics_data.max_vel,
-	providentia.physics_data.max_acc,
-	providentia.physics_data.max_acc,
-	providentia.physics_data.max_force,
-	providentia.physics_data.max_force,
-	providentia.physics_data.max_impulse,
-	providentia.physics_data.max_impulse,
-	providentia.physics_data.avg_work,
-	providentia.physics_data.max_work,
-	providentia.physics_data.max_work,
-	providentia.physics_data.avg_power,
-	providentia.physics_data.max_power,
-	providentia.physics_data.max_power
+	providentia.training_log.total_reps,` + physicsDataSelectSql + `
 FROM providentia.training_log
 JOIN providentia.client
 	ON providentia.client.id = providentia.training_log.client_id
 JOIN providentia.exercise
-	ON providentia.exercise.id = providentia.training_log.exercise_id
-LEFT JOIN providentia.training_log_to_physics_data
-	ON providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id
-LEFT JOIN providentia.physics_data
-	ON providentia.training_log_to_physics_data.physics_id = providentia.physics_data.id
+	ON providentia.exercise.id = providentia.training_log.exercise_id` + physicsDataJoinsSql + `
 WHERE
 	email = $1 AND
 	date_performed >= $2 AND
@@ -197,7 +177,7 @@ func CreateWorkouts(
 	ctxt context.Context,
 	state *types.State,
 	tx pgx.Tx,
-	workouts []types.Workout,
+	opts CreateWorkoutsOpts,
 ) error {
 	type physDataRes = genericCreateReturningIdVal[*types.PhysicsData]
 	type trainingLogRes = genericCreateReturningIdVal[trainingLog]
@@ -212,7 +192,7 @@ func CreateWorkouts(
 		int(state.Global.BatchSize),
 	)
 
-	for _, w := range workouts {
+	for _, w := range opts.Workouts {
 		select {
 		case <-ctxt.Done():
 			return ctxt.Err()
@@ -273,6 +253,184 @@ func CreateWorkouts(
 		}
 	}
 
+	if opts.PRs != nil {
+		if err := detectNewPersonalRecords(
+			ctxt, state, tx, opts.Workouts, opts.PRs,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotCreateAllWorkoutsErr, err)
+		}
+	}
+
+	return nil
+}
+
+// Creates the supplied workouts that do not already exist. A workout exists
+// when the database holds any training log entries with its [types.WorkoutId].
+// Existing workouts are left untouched, their exercises are not compared to
+// the supplied workouts.
+func EnsureWorkoutsExist(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts CreateWorkoutsOpts,
+) error {
+	if len(opts.Workouts) == 0 {
+		return nil
+	}
+
+	emails := make([]string, len(opts.Workouts))
+	dates := make([]time.Time, len(opts.Workouts))
+	sessions := make([]int16, len(opts.Workouts))
+	for i, w := range opts.Workouts {
+		emails[i] = w.ClientEmail
+		dates[i] = w.DatePerformed
+		sessions[i] = int16(w.Session)
+	}
+	rows, err := tx.Query(ctxt, existingWorkoutIdsSql, emails, dates, sessions)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotCreateAllWorkoutsErr, err)
+	}
+	defer rows.Close()
+
+	existing := map[types.WorkoutId]struct{}{}
+	for rows.Next() {
+		var iterId types.WorkoutId
+		var session int16
+		if err := rows.Scan(
+			&iterId.ClientEmail, &iterId.DatePerformed, &session,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotCreateAllWorkoutsErr, err)
+		}
+		iterId.Session = uint16(session)
+		existing[workoutIdKey(iterId)] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotCreateAllWorkoutsErr, err)
+	}
+	rows.Close()
+
+	newOpts := opts
+	newOpts.Workouts = make([]types.Workout, 0, len(opts.Workouts))
+	for _, w := range opts.Workouts {
+		if _, ok := existing[workoutIdKey(w.WorkoutId)]; !ok {
+			newOpts.Workouts = append(newOpts.Workouts, w)
+		}
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Found existing workouts",
+		"NumExisting/NumRows", fmt.Sprintf(
+			"%d/%d",
+			len(opts.Workouts)-len(newOpts.Workouts), len(opts.Workouts),
+		),
+	)
+	return CreateWorkouts(ctxt, state, tx, newOpts)
+}
+
+// Workout ids are only compared by the date the workout was performed on, the
+// time and location of DatePerformed are dropped so ids can be used as keys.
+func workoutIdKey(id types.WorkoutId) types.WorkoutId {
+	y, m, d := id.DatePerformed.Date()
+	id.DatePerformed = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return id
+}
+
+func UpdateWorkouts(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts UpdateWorkoutsOpts,
+) error {
+	type physDataRes = genericCreateReturningIdVal[*types.PhysicsData]
+	type trainingLogRes = genericCreateReturningIdVal[trainingLog]
+
+	toTrainingLog := func(idx WorkoutIdx) trainingLog {
+		w := &opts.Workouts[idx.Workout]
+		e := &w.Exercises[idx.Exercise]
+		return trainingLog{
+			ClientEmail:      w.ClientEmail,
+			ExerciseName:     e.Name,
+			DatePerformed:    w.DatePerformed,
+			InterSessionCntr: int16(w.Session),
+			InterWorkoutCntr: int16(idx.Exercise + 1),
+			Weight:           e.Weight,
+			Sets:             e.Sets,
+			Reps:             e.Reps,
+			Effort:           e.Effort,
+		}
+	}
+
+	trainingLogs := make([]trainingLog, len(opts.Exercises))
+	for i, idx := range opts.Exercises {
+		trainingLogs[i] = toTrainingLog(idx)
+	}
+	if err := updateTrainingLogs(ctxt, state, tx, trainingLogs); err != nil {
+		return sberr.AppendError(types.CouldNotUpdateAllWorkoutsErr, err)
+	}
+
+	physicsArena := sbarena.NewTypedArena[physDataRes](
+		int(state.Global.BatchSize),
+	)
+	trainingLogArena := sbarena.NewTypedArena[trainingLogRes](
+		int(state.Global.BatchSize),
+	)
+	tlToPdArena := sbarena.NewTypedArena[trainingLogToPhysicsData](
+		int(state.Global.BatchSize),
+	)
+
+	sets := make([]trainingLogSet, len(opts.Sets))
+	for i, idx := range opts.Sets {
+		iterTl := trainingLogArena.Alloc()
+		*iterTl = trainingLogRes{Val: toTrainingLog(idx)}
+		sets[i] = trainingLogSet{TrainingLog: &iterTl.Val, SetNum: int32(idx.Set)}
+
+		e := &opts.Workouts[idx.Workout].Exercises[idx.Exercise]
+		if idx.Set < len(e.PhysData) && e.PhysData[idx.Set].Present {
+			iterPd := physicsArena.Alloc()
+			*iterPd = physDataRes{
+				Val: &e.PhysData[idx.Set].Value,
+			}
+
+			iterTlToPd := tlToPdArena.Alloc()
+			*iterTlToPd = trainingLogToPhysicsData{
+				TrainingLogId: &iterTl.Id,
+				PhysicsId:     &iterPd.Id,
+				SetNum:        int32(idx.Set),
+			}
+		}
+	}
+
+	if err := deletePhysicsDataBySet(ctxt, state, tx, sets); err != nil {
+		return sberr.AppendError(types.CouldNotUpdateAllWorkoutsErr, err)
+	}
+
+	for _, c := range trainingLogArena.Chunks() {
+		if err := readTrainingLogIds(ctxt, state, tx, c); err != nil {
+			return sberr.AppendError(types.CouldNotUpdateAllWorkoutsErr, err)
+		}
+	}
+
+	for _, c := range physicsArena.Chunks() {
+		if err := createPhysicsDataReturningIds(ctxt, state, tx, c); err != nil {
+			return sberr.AppendError(types.CouldNotUpdateAllWorkoutsErr, err)
+		}
+	}
+
+	for _, c := range tlToPdArena.Chunks() {
+		if err := createTrainingLogToPhysicsMappings(
+			ctxt, state, tx, c,
+		); err != nil {
+			return sberr.AppendError(types.CouldNotUpdateAllWorkoutsErr, err)
+		}
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Updated workout entries",
+		"NumExercises", len(opts.Exercises),
+		"NumSets", len(opts.Sets),
+	)
 	return nil
 }
 
@@ -311,13 +469,18 @@ func ReadWorkoutsById(
 			return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
 		}
 		if !foundData {
-			return sberr.Wrap(
+			return sberr.AppendError(
 				types.CouldNotReadAllWorkoutsErr,
-				"Could not read entry with id '%+v' (Does id exist?)",
-				opts.Ids[i],
+				sberr.Wrap(
+					types.NotFoundErr,
+					"Could not read entry with id '%+v' (Does id exist?)",
+					opts.Ids[i],
+				),
 			)
 		}
 		iterW.WorkoutId = opts.Ids[i]
+		setWorkoutFatigue(state, iterW)
+		setWorkoutEstimatedMaxes(state, iterW)
 	}
 
 	state.Log.Log(
@@ -358,6 +521,8 @@ func FindWorkoutsById(
 		found++
 		iterW.Present = true
 		iterW.Value.WorkoutId = opts.Ids[i]
+		setWorkoutFatigue(state, &iterW.Value)
+		setWorkoutEstimatedMaxes(state, &iterW.Value)
 	}
 
 	state.Log.Log(
@@ -385,44 +550,7 @@ func readSingleWorkout(
 	var iterE *types.ExerciseData
 	for rows.Next() {
 		iterResult := readWorkoutSqlResult{}
-		if err := rows.Scan(
-			&iterResult.ExerciseName,
-			&iterResult.Weight,
-			&iterResult.Sets,
-			&iterResult.CurSet,
-			&iterResult.Reps,
-			&iterResult.Effort,
-			&iterResult.Volume,
-			&iterResult.Exertion,
-			&iterResult.TotalReps,
-			&iterResult.BarPathCalcVersion,
-			&iterResult.BarPathTrackerVersion,
-			&iterResult.VideoPath,
-			&iterResult.Time,
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Position)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Velocity)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Acceleration)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Jerk)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Force)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Impulse)),
-			&iterResult.Work,
-			&iterResult.Power,
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.RepSplits)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinVel)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxVel)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinAcc)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxAcc)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinForce)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxForce)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinImpulse)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxImpulse)),
-			&iterResult.AvgWork,
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinWork)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxWork)),
-			&iterResult.AvgPower,
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinPower)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxPower)),
-		); err != nil {
+		if err := rows.Scan(readWorkoutScanTargets(&iterResult)...); err != nil {
 			rows.Close()
 			return false, err
 		}
@@ -460,6 +588,37 @@ func readSingleWorkout(
 	return iterE != nil, nil
 }
 
+// Calculates the set fatigue of every exercise in the supplied workout from
+// its physics data.
+func setWorkoutFatigue(state *types.State, w *types.Workout) {
+	for i := range w.Exercises {
+		w.Exercises[i].SetFatigue = velocityloss.CalcForExercise(
+			&w.Exercises[i], float64(state.Global.VelocityLossThreshold),
+		)
+	}
+}
+
+// Calculates the estimated max of every exercise in the supplied workout with
+// the formula set in the global conf.
+func setWorkoutEstimatedMaxes(state *types.State, w *types.Workout) {
+	for i := range w.Exercises {
+		e := &w.Exercises[i]
+		e.EstimatedMax = estimatedMax(
+			state.Global.EstimatedMaxFormula, e.Weight, e.Reps, e.Effort,
+		)
+	}
+}
+
+func estimatedMax(
+	formula types.EstimatedMaxFormula,
+	weight types.Kilogram,
+	reps int32,
+	effort types.RPE,
+) types.Optional[types.Kilogram] {
+	res, ok := estimatedmax.Calc(formula, weight, reps, effort)
+	return types.Optional[types.Kilogram]{Present: ok, Value: res}
+}
+
 func FindWorkoutsInDateRange(
 	ctxt context.Context,
 	state *types.State,
@@ -490,44 +649,7 @@ func FindWorkoutsInDateRange(
 	for rows.Next() {
 		iterResult := findworkoutBetweenDatesSqlResult{}
 		if err := rows.Scan(
-			&iterResult.DatePerformed,
-			&iterResult.Session,
-			&iterResult.ExerciseName,
-			&iterResult.Weight,
-			&iterResult.Sets,
-			&iterResult.CurSet,
-			&iterResult.Reps,
-			&iterResult.Effort,
-			&iterResult.Volume,
-			&iterResult.Exertion,
-			&iterResult.TotalReps,
-			&iterResult.BarPathCalcVersion,
-			&iterResult.BarPathTrackerVersion,
-			&iterResult.VideoPath,
-			&iterResult.Time,
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Position)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Velocity)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Acceleration)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Jerk)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Force)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.Impulse)),
-			&iterResult.Work,
-			&iterResult.Power,
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.RepSplits)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinVel)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxVel)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinAcc)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxAcc)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinForce)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxForce)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinImpulse)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxImpulse)),
-			&iterResult.AvgWork,
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinWork)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxWork)),
-			&iterResult.AvgPower,
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MinPower)),
-			(*[]genericPoint)(unsafe.Pointer(&iterResult.MaxPower)),
+			findWorkoutBetweenDatesScanTargets(&iterResult)...,
 		); err != nil {
 			rows.Close()
 			return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
@@ -574,6 +696,10 @@ func FindWorkoutsInDateRange(
 		}
 	}
 	rows.Close()
+	for i := range *opts.Res {
+		setWorkoutFatigue(state, &(*opts.Res)[i])
+		setWorkoutEstimatedMaxes(state, &(*opts.Res)[i])
+	}
 
 	state.Log.Log(
 		ctxt, sblog.VLevel(3),
@@ -647,3 +773,35 @@ func DeleteWorkoutsInDateRange(
 	)
 	return nil
 }
+
+func readWorkoutScanTargets(res *readWorkoutSqlResult) []any {
+	return append([]any{
+		&res.ExerciseName,
+		&res.Weight,
+		&res.Sets,
+		&res.CurSet,
+		&res.Reps,
+		&res.Effort,
+		&res.Volume,
+		&res.Exertion,
+		&res.TotalReps,
+	}, physicsDataScanTargets(&res.PhysicsData)...)
+}
+
+func findWorkoutBetweenDatesScanTargets(
+	res *findworkoutBetweenDatesSqlResult,
+) []any {
+	return append([]any{
+		&res.DatePerformed,
+		&res.Session,
+		&res.ExerciseName,
+		&res.Weight,
+		&res.Sets,
+		&res.CurSet,
+		&res.Reps,
+		&res.Effort,
+		&res.Volume,
+		&res.Exertion,
+		&res.TotalReps,
+	}, physicsDataScanTargets(&res.PhysicsData)...)
+}
@@ -0,0 +1,420 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	StreamWorkoutsByIdOpts struct {
+		Ids          []types.WorkoutId
+		SkipPhysData bool
+		// Called with each workout as it is read. Returning false stops the
+		// stream without an error.
+		Yield func(w types.Workout) bool
+	}
+
+	StreamWorkoutsInDateRangeOpts struct {
+		Email        string
+		Start        time.Time
+		End          time.Time
+		SkipPhysData bool
+		// Called with each workout as it is read. Returning false stops the
+		// stream without an error.
+		Yield func(w types.Workout) bool
+	}
+
+	streamWorkoutSqlResult struct {
+		DatePerformed    time.Time
+		Session          uint16
+		InterWorkoutCntr int16
+		ExerciseName     string
+		Weight           types.Kilogram
+		Sets             float64
+		CurSet           int
+		Reps             int32
+		Effort           types.RPE
+		types.AbstractData
+		types.PhysicsData
+	}
+)
+
+const (
+	workoutsCursorName = "providentia_workouts_cursor"
+
+	streamWorkoutsBetweenDatesSql = `
+SELECT
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr,
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.sets,
+	COALESCE(providentia.training_log_to_physics_data.set_num+1, 0) AS cur_set,
+	providentia.training_log.reps,
+	providentia.training_log.effort,
+	providentia.training_log.volume,
+	providentia.training_log.exertion,
+	providentia.training_log.total_reps,` + physicsDataSelectSql + `
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id` + physicsDataJoinsSql + `
+WHERE
+	email = $1 AND
+	date_performed >= $2 AND
+	date_performed < $3
+ORDER BY date_performed, inter_session_cntr, inter_workout_cntr, cur_set ASC;
+`
+
+	streamWorkoutsBetweenDatesNoPhysSql = `
+SELECT
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr,
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.sets,
+	providentia.training_log.reps,
+	providentia.training_log.effort,
+	providentia.training_log.volume,
+	providentia.training_log.exertion,
+	providentia.training_log.total_reps
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+WHERE
+	email = $1 AND
+	date_performed >= $2 AND
+	date_performed < $3
+ORDER BY date_performed, inter_session_cntr, inter_workout_cntr ASC;
+`
+
+	streamWorkoutByIdNoPhysSql = `
+SELECT
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr,
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.sets,
+	providentia.training_log.reps,
+	providentia.training_log.effort,
+	providentia.training_log.volume,
+	providentia.training_log.exertion,
+	providentia.training_log.total_reps
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+WHERE
+	email = $1 AND
+	inter_session_cntr = $2 AND
+	date_performed = $3
+ORDER BY inter_workout_cntr ASC;
+`
+)
+
+func StreamWorkoutsById(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts StreamWorkoutsByIdOpts,
+) error {
+	for i := range opts.Ids {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		var iterW types.Workout
+		var foundData bool
+		var err error
+		if opts.SkipPhysData {
+			foundData, err = readSingleWorkoutNoPhys(
+				ctxt, tx, &opts.Ids[i], &iterW,
+			)
+		} else {
+			foundData, err = readSingleWorkout(ctxt, tx, &opts.Ids[i], &iterW)
+		}
+		if err != nil {
+			return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
+		}
+		if !foundData {
+			return sberr.AppendError(
+				types.CouldNotReadAllWorkoutsErr,
+				sberr.Wrap(
+					types.NotFoundErr,
+					"Could not read entry with id '%+v' (Does id exist?)",
+					opts.Ids[i],
+				),
+			)
+		}
+		iterW.WorkoutId = opts.Ids[i]
+		if !opts.SkipPhysData {
+			setWorkoutFatigue(state, &iterW)
+		}
+		setWorkoutEstimatedMaxes(state, &iterW)
+
+		if !opts.Yield(iterW) {
+			break
+		}
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Streamed workouts by WorkoutIds",
+		"NumRows", len(opts.Ids),
+	)
+	return nil
+}
+
+func readSingleWorkoutNoPhys(
+	ctxt context.Context,
+	tx pgx.Tx,
+	id *types.WorkoutId,
+	iterW *types.Workout,
+) (bool, error) {
+	rows, err := tx.Query(
+		ctxt,
+		streamWorkoutByIdNoPhysSql, id.ClientEmail, id.Session, id.DatePerformed,
+	)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		iterResult := streamWorkoutSqlResult{}
+		if err := rows.Scan(
+			streamWorkoutNoPhysScanTargets(&iterResult)...,
+		); err != nil {
+			return false, err
+		}
+		iterW.Exercises = append(
+			iterW.Exercises, streamedExerciseData(&iterResult, false),
+		)
+	}
+	return len(iterW.Exercises) > 0, rows.Err()
+}
+
+func StreamWorkoutsInDateRange(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts StreamWorkoutsInDateRangeOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotReadAllWorkoutsErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+
+	query := streamWorkoutsBetweenDatesSql
+	scanTargets := streamWorkoutScanTargets
+	if opts.SkipPhysData {
+		query = streamWorkoutsBetweenDatesNoPhysSql
+		scanTargets = streamWorkoutNoPhysScanTargets
+	}
+
+	found := 0
+	stopped := false
+	var iterW types.Workout
+	var iterInterWorkoutCntr int16
+	yieldCur := func() bool {
+		if len(iterW.Exercises) == 0 {
+			return true
+		}
+		if !opts.SkipPhysData {
+			setWorkoutFatigue(state, &iterW)
+		}
+		setWorkoutEstimatedMaxes(state, &iterW)
+		found++
+		stopped = !opts.Yield(iterW)
+		return !stopped
+	}
+
+	err := cursorQuery(
+		ctxt, state, tx, workoutsCursorName, query,
+		[]any{opts.Email, opts.Start, opts.End},
+		func(rows pgx.Rows) (bool, error) {
+			iterResult := streamWorkoutSqlResult{}
+			if err := rows.Scan(scanTargets(&iterResult)...); err != nil {
+				return false, err
+			}
+
+			iterWorkoutId := types.WorkoutId{
+				ClientEmail:   opts.Email,
+				Session:       iterResult.Session,
+				DatePerformed: iterResult.DatePerformed,
+			}
+			if len(iterW.Exercises) == 0 || iterW.WorkoutId != iterWorkoutId {
+				if !yieldCur() {
+					return false, nil
+				}
+				iterW = types.Workout{WorkoutId: iterWorkoutId}
+				iterInterWorkoutCntr = 0
+			}
+			if iterInterWorkoutCntr != iterResult.InterWorkoutCntr {
+				iterW.Exercises = append(
+					iterW.Exercises,
+					streamedExerciseData(&iterResult, !opts.SkipPhysData),
+				)
+				iterInterWorkoutCntr = iterResult.InterWorkoutCntr
+			}
+
+			iterE := &iterW.Exercises[len(iterW.Exercises)-1]
+			if !opts.SkipPhysData && iterResult.CurSet > 0 {
+				iterE.PhysData[iterResult.CurSet-1] = types.Optional[types.PhysicsData]{
+					Present: len(iterResult.Time) > 0,
+					Value:   iterResult.PhysicsData,
+				}
+			}
+			return true, nil
+		},
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
+	}
+	// The last workout is only complete once all rows have been read.
+	if !stopped {
+		yieldCur()
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Streamed workouts in date range [%s, %s)", opts.Start, opts.End,
+		),
+		"Found", found,
+	)
+	return nil
+}
+
+// Declares a cursor for the supplied query and fetches the results in batches
+// that respect the [types.GlobalConf.BatchSize] variable, calling rowFunc for
+// every row. Fetching stops once all rows have been read or rowFunc returns
+// false or an error. The cursor is closed before returning.
+func cursorQuery(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	name string,
+	query string,
+	args []any,
+	rowFunc func(rows pgx.Rows) (bool, error),
+) error {
+	if _, err := tx.Exec(
+		ctxt, fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", name, query),
+		args...,
+	); err != nil {
+		return err
+	}
+
+	fetchSql := fmt.Sprintf(
+		"FETCH FORWARD %d FROM %s;", state.Global.BatchSize, name,
+	)
+	for {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		rows, err := tx.Query(ctxt, fetchSql)
+		if err != nil {
+			return err
+		}
+		var numRows uint64
+		for rows.Next() {
+			numRows++
+			if cont, err := rowFunc(rows); err != nil || !cont {
+				rows.Close()
+				if err == nil {
+					_, err = tx.Exec(ctxt, "CLOSE "+name+";")
+				}
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if numRows < uint64(state.Global.BatchSize) {
+			break
+		}
+	}
+
+	_, err := tx.Exec(ctxt, "CLOSE "+name+";")
+	return err
+}
+
+func streamedExerciseData(
+	res *streamWorkoutSqlResult,
+	withPhysData bool,
+) types.ExerciseData {
+	rv := types.ExerciseData{
+		Name:   res.ExerciseName,
+		Weight: res.Weight,
+		Sets:   res.Sets,
+		Reps:   res.Reps,
+		Effort: res.Effort,
+		AbstractData: types.Optional[types.AbstractData]{
+			Present: true,
+			Value:   res.AbstractData,
+		},
+	}
+	if withPhysData {
+		rv.PhysData = make(
+			[]types.Optional[types.PhysicsData], int(math.Ceil(res.Sets)),
+		)
+	}
+	return rv
+}
+
+func streamWorkoutNoPhysScanTargets(res *streamWorkoutSqlResult) []any {
+	return []any{
+		&res.DatePerformed,
+		&res.Session,
+		&res.InterWorkoutCntr,
+		&res.ExerciseName,
+		&res.Weight,
+		&res.Sets,
+		&res.Reps,
+		&res.Effort,
+		&res.Volume,
+		&res.Exertion,
+		&res.TotalReps,
+	}
+}
+
+func streamWorkoutScanTargets(res *streamWorkoutSqlResult) []any {
+	return append([]any{
+		&res.DatePerformed,
+		&res.Session,
+		&res.InterWorkoutCntr,
+		&res.ExerciseName,
+		&res.Weight,
+		&res.Sets,
+		&res.CurSet,
+		&res.Reps,
+		&res.Effort,
+		&res.Volume,
+		&res.Exertion,
+		&res.TotalReps,
+	}, physicsDataScanTargets(&res.PhysicsData)...)
+}
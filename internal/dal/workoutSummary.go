@@ -0,0 +1,276 @@
+package dal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	ReadWorkoutSummariesByIdOpts struct {
+		Ids []types.WorkoutId
+		Res *[]types.WorkoutSummary
+	}
+
+	FindWorkoutSummariesInDateRangeOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		Res   *[]types.WorkoutSummary
+	}
+
+	workoutSummarySqlResult struct {
+		DatePerformed    time.Time
+		Session          uint16
+		InterWorkoutCntr int16
+		ExerciseName     string
+		Weight           types.Kilogram
+		Sets             float64
+		Reps             int32
+		Effort           types.RPE
+		types.AbstractData
+		PhysDataSets []int32
+	}
+)
+
+// Note - the summary queries only use the training log to physics data mapping
+// table to determine which sets have physics data. The physics_data table is
+// never read.
+const (
+	workoutSummaryByIdSql = `
+SELECT
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr,
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.sets,
+	providentia.training_log.reps,
+	providentia.training_log.effort,
+	providentia.training_log.volume,
+	providentia.training_log.exertion,
+	providentia.training_log.total_reps,
+	COALESCE(
+		ARRAY_AGG(providentia.training_log_to_physics_data.set_num)
+		FILTER (WHERE providentia.training_log_to_physics_data.set_num IS NOT NULL),
+		'{}'
+	)
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+LEFT JOIN providentia.training_log_to_physics_data
+	ON providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id
+WHERE
+	email = $1 AND
+	inter_session_cntr = $2 AND
+	date_performed = $3
+GROUP BY providentia.training_log.id, providentia.exercise.name
+ORDER BY inter_workout_cntr ASC;
+`
+
+	workoutSummariesBetweenDatesSql = `
+SELECT
+	providentia.training_log.date_performed,
+	providentia.training_log.inter_session_cntr,
+	providentia.training_log.inter_workout_cntr,
+	providentia.exercise.name,
+	providentia.training_log.weight,
+	providentia.training_log.sets,
+	providentia.training_log.reps,
+	providentia.training_log.effort,
+	providentia.training_log.volume,
+	providentia.training_log.exertion,
+	providentia.training_log.total_reps,
+	COALESCE(
+		ARRAY_AGG(providentia.training_log_to_physics_data.set_num)
+		FILTER (WHERE providentia.training_log_to_physics_data.set_num IS NOT NULL),
+		'{}'
+	)
+FROM providentia.training_log
+JOIN providentia.client
+	ON providentia.client.id = providentia.training_log.client_id
+JOIN providentia.exercise
+	ON providentia.exercise.id = providentia.training_log.exercise_id
+LEFT JOIN providentia.training_log_to_physics_data
+	ON providentia.training_log_to_physics_data.training_log_id = providentia.training_log.id
+WHERE
+	email = $1 AND
+	date_performed >= $2 AND
+	date_performed < $3
+GROUP BY providentia.training_log.id, providentia.exercise.name
+ORDER BY date_performed, inter_session_cntr, inter_workout_cntr ASC;
+`
+)
+
+func ReadWorkoutSummariesById(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadWorkoutSummariesByIdOpts,
+) error {
+	*opts.Res = util.SliceClamp(*opts.Res, len(opts.Ids))
+
+	for i := range opts.Ids {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		iterW := &(*opts.Res)[i]
+		iterW.Exercises = iterW.Exercises[:0]
+		rows, err := tx.Query(
+			ctxt, workoutSummaryByIdSql,
+			opts.Ids[i].ClientEmail, opts.Ids[i].Session,
+			opts.Ids[i].DatePerformed,
+		)
+		if err != nil {
+			return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
+		}
+		for rows.Next() {
+			iterResult := workoutSummarySqlResult{}
+			if err := rows.Scan(
+				workoutSummaryScanTargets(&iterResult)...,
+			); err != nil {
+				rows.Close()
+				return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
+			}
+			iterW.Exercises = append(
+				iterW.Exercises,
+				iterResult.toExerciseSummary(state.Global.EstimatedMaxFormula),
+			)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
+		}
+
+		if len(iterW.Exercises) == 0 {
+			return sberr.AppendError(
+				types.CouldNotReadAllWorkoutsErr,
+				sberr.Wrap(
+					types.NotFoundErr,
+					"Could not read entry with id '%+v' (Does id exist?)",
+					opts.Ids[i],
+				),
+			)
+		}
+		iterW.WorkoutId = opts.Ids[i]
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"DAL: Read workout summaries by WorkoutIds",
+		"NumRows", len(opts.Ids),
+	)
+	return nil
+}
+
+func FindWorkoutSummariesInDateRange(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts FindWorkoutSummariesInDateRangeOpts,
+) error {
+	if opts.End.Before(opts.Start) {
+		return sberr.Wrap(
+			types.CouldNotReadAllWorkoutsErr,
+			"Start date (%s) must be before end date (%s)",
+			opts.Start, opts.End,
+		)
+	}
+
+	*opts.Res = (*opts.Res)[:0]
+	rows, err := tx.Query(
+		ctxt,
+		workoutSummariesBetweenDatesSql, opts.Email, opts.Start, opts.End,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
+	}
+	defer rows.Close()
+
+	var iterW *types.WorkoutSummary
+	for rows.Next() {
+		iterResult := workoutSummarySqlResult{}
+		if err := rows.Scan(workoutSummaryScanTargets(&iterResult)...); err != nil {
+			return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
+		}
+
+		iterWorkoutId := types.WorkoutId{
+			ClientEmail:   opts.Email,
+			Session:       iterResult.Session,
+			DatePerformed: iterResult.DatePerformed,
+		}
+		if iterW == nil || iterW.WorkoutId != iterWorkoutId {
+			*opts.Res = append(*opts.Res, types.WorkoutSummary{
+				WorkoutId: iterWorkoutId,
+			})
+			iterW = &(*opts.Res)[len(*opts.Res)-1]
+		}
+		iterW.Exercises = append(
+			iterW.Exercises,
+			iterResult.toExerciseSummary(state.Global.EstimatedMaxFormula),
+		)
+	}
+	if err := rows.Err(); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllWorkoutsErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		fmt.Sprintf(
+			"DAL: Found workout summaries in date range [%s, %s)",
+			opts.Start, opts.End,
+		),
+		"Found", len(*opts.Res),
+	)
+	return nil
+}
+
+func workoutSummaryScanTargets(res *workoutSummarySqlResult) []any {
+	return []any{
+		&res.DatePerformed,
+		&res.Session,
+		&res.InterWorkoutCntr,
+		&res.ExerciseName,
+		&res.Weight,
+		&res.Sets,
+		&res.Reps,
+		&res.Effort,
+		&res.Volume,
+		&res.Exertion,
+		&res.TotalReps,
+		&res.PhysDataSets,
+	}
+}
+
+func (w *workoutSummarySqlResult) toExerciseSummary(
+	formula types.EstimatedMaxFormula,
+) types.ExerciseSummary {
+	rv := types.ExerciseSummary{
+		Name:         w.ExerciseName,
+		Weight:       w.Weight,
+		Sets:         w.Sets,
+		Reps:         w.Reps,
+		Effort:       w.Effort,
+		AbstractData: w.AbstractData,
+		EstimatedMax: estimatedMax(formula, w.Weight, w.Reps, w.Effort),
+		HasPhysData:  make([]bool, int(math.Ceil(w.Sets))),
+	}
+	for _, setNum := range w.PhysDataSets {
+		if int(setNum) < len(rv.HasPhysData) {
+			rv.HasPhysData[setNum] = true
+		}
+	}
+	return rv
+}
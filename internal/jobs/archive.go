@@ -0,0 +1,599 @@
+package jobs
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	DumpOpts struct {
+		W io.Writer
+	}
+
+	RestoreOpts struct {
+		R io.Reader
+		*types.RestoreOpts
+	}
+
+	// The first file of every archive. Describes the version of the archive
+	// format and what the archive holds.
+	archiveManifest struct {
+		Version             int32
+		Created             time.Time
+		NumClients          int
+		NumClientAttributes int
+		NumBodyweights      int
+		NumExercises        int
+		NumHyperparams      int
+		NumWorkouts         int
+	}
+)
+
+const (
+	archiveManifestFile         = "manifest.json"
+	archiveClientDir            = "clients"
+	archiveExerciseDir          = "exercises"
+	archiveHyperparamDir        = "hyperparams"
+	archiveClientAttributesFile = "clientAttributes.jsonl"
+	archiveBodyweightFile       = "bodyweights.jsonl"
+)
+
+var (
+	// The workouts and bodyweights of every client are dumped in this date
+	// range, which covers every date that can be stored.
+	archiveStart = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	archiveEnd   = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// Writes every client, client attribute, bodyweight, exercise, hyperparam, and
+// workout in the database to a gzipped tar archive. The archive holds a
+// manifest followed by the data in the layout that [BulkUploadData] loads.
+// Workouts are written as JSON Lines files so that their physics data, along
+// with the versions of the hyperparams it was calculated with, is kept as is.
+// Client attributes and bodyweights are written to their own JSON Lines files
+// in the root of the archive.
+func Dump(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts DumpOpts,
+) error {
+	dir, err := os.MkdirTemp("", "providentia-dump-*")
+	if err != nil {
+		return sberr.AppendError(types.CouldNotDumpErr, err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest, err := dumpToDir(ctxt, state, tx, dir)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotDumpErr, err)
+	}
+	if err := writeArchive(opts.W, dir); err != nil {
+		return sberr.AppendError(types.CouldNotDumpErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		formatJobLogLine("Dump", 0, "Finished dumping database"),
+		"NumClients", manifest.NumClients,
+		"NumClientAttributes", manifest.NumClientAttributes,
+		"NumBodyweights", manifest.NumBodyweights,
+		"NumExercises", manifest.NumExercises,
+		"NumHyperparams", manifest.NumHyperparams,
+		"NumWorkouts", manifest.NumWorkouts,
+	)
+	return nil
+}
+
+func dumpToDir(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	dir string,
+) (manifest archiveManifest, opErr error) {
+	manifest = archiveManifest{
+		Version: types.ArchiveVersion,
+		Created: time.Now().UTC(),
+	}
+	for _, d := range []string{
+		archiveClientDir, archiveExerciseDir, archiveHyperparamDir,
+		types.ExportWorkoutDir,
+	} {
+		if opErr = os.MkdirAll(filepath.Join(dir, d), 0o755); opErr != nil {
+			return
+		}
+	}
+
+	clients := []types.Client{}
+	if opErr = dal.ReadAllClients(ctxt, state, tx, &clients); opErr != nil {
+		return
+	}
+	manifest.NumClients = len(clients)
+	if opErr = writeNonEmptyCSV(
+		filepath.Join(dir, archiveClientDir, "clients.csv"), clients,
+	); opErr != nil {
+		return
+	}
+
+	exercises := []types.Exercise{}
+	if opErr = dal.ReadAllExercises(ctxt, state, tx, &exercises); opErr != nil {
+		return
+	}
+	manifest.NumExercises = len(exercises)
+	if opErr = writeNonEmptyCSV(
+		filepath.Join(dir, archiveExerciseDir, "exercises.csv"), exercises,
+	); opErr != nil {
+		return
+	}
+
+	hyperparamDir := filepath.Join(dir, archiveHyperparamDir)
+	var n int
+	if n, opErr = dumpHyperparams[types.BarPathCalcHyperparams](
+		ctxt, state, tx, hyperparamDir, types.BarPathCalcFileExt,
+	); opErr != nil {
+		return
+	}
+	manifest.NumHyperparams += n
+	if n, opErr = dumpHyperparams[types.BarPathTrackerHyperparams](
+		ctxt, state, tx, hyperparamDir, types.BarPathTrackerFileExt,
+	); opErr != nil {
+		return
+	}
+	manifest.NumHyperparams += n
+	if n, opErr = dumpHyperparams[types.FitnessFatigueHyperparams](
+		ctxt, state, tx, hyperparamDir, types.FitnessFatigueFileExt,
+	); opErr != nil {
+		return
+	}
+	manifest.NumHyperparams += n
+
+	emails := make([]string, len(clients))
+	for i, c := range clients {
+		emails[i] = c.Email
+	}
+	if manifest.NumClientAttributes, opErr = dumpClientAttributes(
+		ctxt, state, tx, emails, filepath.Join(dir, archiveClientAttributesFile),
+	); opErr != nil {
+		return
+	}
+	if manifest.NumBodyweights, opErr = dumpBodyweights(
+		ctxt, state, tx, emails, filepath.Join(dir, archiveBodyweightFile),
+	); opErr != nil {
+		return
+	}
+
+	for _, email := range emails {
+		if n, opErr = dumpWorkouts(
+			ctxt, state, tx, email,
+			filepath.Join(dir, types.ExportWorkoutDir, email+jsonlFileExt),
+		); opErr != nil {
+			return
+		}
+		manifest.NumWorkouts += n
+	}
+
+	var rawManifest []byte
+	if rawManifest, opErr = json.MarshalIndent(manifest, "", "\t"); opErr != nil {
+		return
+	}
+	opErr = os.WriteFile(
+		filepath.Join(dir, archiveManifestFile), rawManifest, 0o644,
+	)
+	return
+}
+
+func dumpHyperparams[T types.Hyperparams](
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	dir string,
+	ext string,
+) (int, error) {
+	params := []T{}
+	if err := dal.ReadAllHyperparamsFor(ctxt, state, tx, &params); err != nil {
+		return 0, err
+	}
+	return len(params), writeNonEmptyCSV(
+		filepath.Join(dir, "hyperparams."+ext+".csv"), params,
+	)
+}
+
+// Clients without a sex are not written, they are the same as clients without
+// any attributes.
+func dumpClientAttributes(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	emails []string,
+	file string,
+) (int, error) {
+	attrs := []types.ClientAttributes{}
+	if err := dal.ReadClientAttributes(
+		ctxt, state, tx, dal.ReadClientAttributesOpts{
+			Emails: emails, Res: &attrs,
+		},
+	); err != nil {
+		return 0, err
+	}
+	attrs = slices.DeleteFunc(attrs, func(a types.ClientAttributes) bool {
+		return a.Sex == types.UnknownSex
+	})
+	return len(attrs), writeJSONLFile(file, attrs)
+}
+
+func dumpBodyweights(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	emails []string,
+	file string,
+) (int, error) {
+	res := []types.BodyweightEntry{}
+	iterEntries := []types.BodyweightEntry{}
+	for _, email := range emails {
+		if err := dal.ReadBodyweightsInDateRange(
+			ctxt, state, tx, dal.ReadBodyweightsInDateRangeOpts{
+				Email: email,
+				Start: archiveStart,
+				End:   archiveEnd,
+				Res:   &iterEntries,
+			},
+		); err != nil {
+			return 0, err
+		}
+		res = append(res, iterEntries...)
+	}
+	return len(res), writeJSONLFile(file, res)
+}
+
+// Workouts are streamed to the file so that every workout of the client does
+// not need to be held in memory at once.
+func dumpWorkouts(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	email string,
+	file string,
+) (n int, opErr error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && opErr == nil {
+			opErr = err
+		}
+	}()
+
+	var writeErr error
+	if opErr = dal.StreamWorkoutsInDateRange(
+		ctxt, state, tx, dal.StreamWorkoutsInDateRangeOpts{
+			Email: email,
+			Start: archiveStart,
+			End:   archiveEnd,
+			Yield: func(w types.Workout) bool {
+				writeErr = WriteJSONL(f, []types.Workout{w})
+				n++
+				return writeErr == nil
+			},
+		},
+	); opErr != nil {
+		return
+	}
+	opErr = writeErr
+	return
+}
+
+func writeJSONLFile[T any](file string, vals []T) (opErr error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && opErr == nil {
+			opErr = err
+		}
+	}()
+	return WriteJSONL(f, vals)
+}
+
+// Empty files are not written because the csv loaders expect every file to
+// have at least one row of data.
+func writeNonEmptyCSV[T any](file string, vals []T) error {
+	if len(vals) == 0 {
+		return nil
+	}
+	return writeStructsToCSV(file, vals)
+}
+
+// Writes the contents of the supplied dir to a gzipped tar archive. The
+// manifest is always the first file in the archive.
+func writeArchive(w io.Writer, dir string) (opErr error) {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	defer func() {
+		if err := tw.Close(); err != nil && opErr == nil {
+			opErr = err
+		}
+		if err := gw.Close(); err != nil && opErr == nil {
+			opErr = err
+		}
+	}()
+
+	if opErr = writeArchiveEntry(
+		tw, dir, filepath.Join(dir, archiveManifestFile),
+	); opErr != nil {
+		return
+	}
+	opErr = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir || d.Name() == archiveManifestFile {
+			return nil
+		}
+		return writeArchiveEntry(tw, dir, p)
+	})
+	return
+}
+
+func writeArchiveEntry(tw *tar.Writer, dir string, p string) error {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = filepath.ToSlash(rel)
+	if fi.IsDir() {
+		hdr.Name += "/"
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return nil
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Loads an archive written by [Dump] into the database. Clients, exercises,
+// hyperparams, and workouts are loaded with the create types in the supplied
+// options. Bodyweights are loaded with the client create type and client
+// attributes always replace any existing attributes. The physics data of the
+// workouts is not recalculated, it references the hyperparams that were
+// restored from the same archive.
+func Restore(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts RestoreOpts,
+) error {
+	dir, err := os.MkdirTemp("", "providentia-restore-*")
+	if err != nil {
+		return sberr.AppendError(types.CouldNotRestoreErr, err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest, err := extractArchive(opts.R, dir)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotRestoreErr, err)
+	}
+
+	if err := BulkUploadData(ctxt, state, tx, &types.BulkUploadDataOpts{
+		Opts: sbcsv.Opts{TimeFormat: types.ExportDateFormat},
+		// The hyperparams are only used to calculate the physics data of csv
+		// workout files, which [extractArchive] does not allow.
+		BarPathCalcHyperparams:    &types.BarPathCalcHyperparams{},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		ClientCreateType:          opts.ClientCreateType,
+		ClientDir:                 filepath.Join(dir, archiveClientDir),
+		ExerciseCreateType:        opts.ExerciseCreateType,
+		ExerciseDir:               filepath.Join(dir, archiveExerciseDir),
+		HyperparamsCreateType:     opts.HyperparamsCreateType,
+		HyperparamsDir:            filepath.Join(dir, archiveHyperparamDir),
+		WorkoutCreateType:         opts.WorkoutCreateType,
+		WorkoutDir:                filepath.Join(dir, types.ExportWorkoutDir),
+	}); err != nil {
+		return sberr.AppendError(types.CouldNotRestoreErr, err)
+	}
+
+	attrs, err := readArchiveJSONLFile[types.ClientAttributes](
+		filepath.Join(dir, archiveClientAttributesFile),
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotRestoreErr, err)
+	}
+	if err := dal.SetClientAttributes(ctxt, state, tx, attrs); err != nil {
+		return sberr.AppendError(types.CouldNotRestoreErr, err)
+	}
+
+	bodyweights, err := readArchiveJSONLFile[types.BodyweightEntry](
+		filepath.Join(dir, archiveBodyweightFile),
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotRestoreErr, err)
+	}
+	createBodyweights := dal.CreateBodyweights
+	if opts.ClientCreateType == types.EnsureExists {
+		createBodyweights = dal.EnsureBodyweightsExist
+	}
+	if err := createBodyweights(ctxt, state, tx, bodyweights); err != nil {
+		return sberr.AppendError(types.CouldNotRestoreErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		formatJobLogLine("Restore", 0, "Finished restoring database"),
+		"Created", manifest.Created,
+		"NumClients", manifest.NumClients,
+		"NumClientAttributes", manifest.NumClientAttributes,
+		"NumBodyweights", manifest.NumBodyweights,
+		"NumExercises", manifest.NumExercises,
+		"NumHyperparams", manifest.NumHyperparams,
+		"NumWorkouts", manifest.NumWorkouts,
+	)
+	return nil
+}
+
+// Files that are missing from the archive are treated as empty, they may not
+// be in archives that were not written by [Dump].
+func readArchiveJSONLFile[T any](file string) ([]T, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadJSONL[T](f)
+}
+
+// Extracts the supplied archive to the supplied dir, returning the manifest of
+// the archive.
+func extractArchive(
+	r io.Reader,
+	dir string,
+) (manifest archiveManifest, opErr error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		opErr = sberr.AppendError(types.InvalidArchiveErr, err)
+		return
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for i := 0; ; i++ {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			opErr = sberr.AppendError(types.InvalidArchiveErr, err)
+			return
+		}
+
+		if i == 0 {
+			if hdr.Name != archiveManifestFile {
+				opErr = sberr.Wrap(
+					types.InvalidArchiveErr,
+					"The first file must be %s, got %s",
+					archiveManifestFile, hdr.Name,
+				)
+				return
+			}
+			if opErr = json.NewDecoder(tr).Decode(&manifest); opErr != nil {
+				opErr = sberr.AppendError(types.InvalidArchiveErr, opErr)
+				return
+			}
+			if manifest.Version != types.ArchiveVersion {
+				opErr = sberr.Wrap(
+					types.UnsupportedArchiveVersionErr,
+					"Expected version %d, got %d",
+					types.ArchiveVersion, manifest.Version,
+				)
+				return
+			}
+			continue
+		}
+
+		if !filepath.IsLocal(hdr.Name) {
+			opErr = sberr.Wrap(
+				types.InvalidArchiveErr,
+				"Archive contained a file outside of the archive: %s",
+				hdr.Name,
+			)
+			return
+		}
+		p := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if opErr = os.MkdirAll(p, 0o755); opErr != nil {
+				return
+			}
+		case tar.TypeReg:
+			if path.Dir(hdr.Name) == types.ExportWorkoutDir &&
+				path.Ext(hdr.Name) != jsonlFileExt {
+				opErr = sberr.Wrap(
+					types.InvalidArchiveErr,
+					"Workouts must be JSON Lines files, got: %s", hdr.Name,
+				)
+				return
+			}
+			if opErr = extractArchiveFile(tr, p); opErr != nil {
+				return
+			}
+		default:
+			opErr = sberr.Wrap(
+				types.InvalidArchiveErr,
+				"Archive contained an unsupported file type: %s", hdr.Name,
+			)
+			return
+		}
+	}
+
+	if manifest.Version == 0 {
+		opErr = sberr.Wrap(
+			types.InvalidArchiveErr, "The archive did not have a manifest",
+		)
+		return
+	}
+
+	// Dirs may not be in archives that were not written by [Dump], make sure
+	// they exist so they can be loaded.
+	for _, d := range []string{
+		archiveClientDir, archiveExerciseDir, archiveHyperparamDir,
+		types.ExportWorkoutDir,
+	} {
+		if opErr = os.MkdirAll(filepath.Join(dir, d), 0o755); opErr != nil {
+			return
+		}
+	}
+	return
+}
+
+func extractArchiveFile(r io.Reader, p string) (opErr error) {
+	if opErr = os.MkdirAll(filepath.Dir(p), 0o755); opErr != nil {
+		return
+	}
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && opErr == nil {
+			opErr = err
+		}
+	}()
+	_, opErr = io.Copy(f, r)
+	return
+}
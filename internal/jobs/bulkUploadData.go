@@ -25,8 +25,39 @@ func resolveCreateFunc[T dal.AvailableTypes](
 	return create
 }
 
+func createWorkouts(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	workouts []types.Workout,
+) error {
+	return dal.CreateWorkouts(
+		ctxt, state, tx, dal.CreateWorkoutsOpts{Workouts: workouts},
+	)
+}
+
+func ensureWorkoutsExist(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	workouts []types.Workout,
+) error {
+	return dal.EnsureWorkoutsExist(
+		ctxt, state, tx, dal.CreateWorkoutsOpts{Workouts: workouts},
+	)
+}
+
+// Rejected row files written by previous uploads are skipped so that they are
+// never loaded as data.
 func getFilesInDirFunc(dir string) iter.Seq2[string, error] {
-	return util.FilesWithExtInDir(dir, ".csv", util.FilesWithExtInDirOpts{})
+	return util.FilterSeq2Err(
+		util.FilesWithExtInDir(
+			dir, []string{csvFileExt, jsonlFileExt}, util.FilesWithExtInDirOpts{},
+		),
+		func(v *string, e *error) bool {
+			return *e != nil || !isRejectedFile(*v)
+		},
+	)
 }
 
 func hyperparamFilterFunc(extension string) func(v *string, e *error) bool {
@@ -39,7 +70,7 @@ func hyperparamFilterFunc(extension string) func(v *string, e *error) bool {
 		if len(split) != 3 {
 			*e = sberr.Wrap(
 				types.UnknownFileInDataDirErr,
-				"Invalid hyperparam file. File name must have the following format: <file name>.<hyperparam type>.csv\nGot: %s",
+				"Invalid hyperparam file. File name must have the following format: <file name>.<hyperparam type>.<csv|jsonl>\nGot: %s",
 				*v,
 			)
 			return true
@@ -78,12 +109,66 @@ func BulkUploadData(
 		)
 	}
 
+	if opts.DryRun && opts.Report == nil {
+		return sberr.Wrap(
+			types.BulkDataUploadErr,
+			"When DryRun is true Report must not be nil",
+		)
+	}
+
+	skipRejected := !opts.DryRun && opts.ErrorPolicy == types.SkipRejectedRows
+	if skipRejected && opts.Counts == nil {
+		return sberr.Wrap(
+			types.BulkDataUploadErr,
+			"When ErrorPolicy is SkipRejectedRows Counts must not be nil",
+		)
+	}
+
+	// The rejected file of every loaded file is replaced at the start of an
+	// upload. A resumed upload skips the chunks that were already committed,
+	// so the rows those chunks rejected would be lost from both the rejected
+	// file and Counts.
+	resume := !opts.DryRun && opts.Resume
+	if resume && skipRejected {
+		return sberr.Wrap(
+			types.BulkDataUploadErr,
+			"Resume cannot be used with the SkipRejectedRows error policy",
+		)
+	}
+
+	var report *uploadReport
+	if opts.DryRun {
+		report = newUploadReport(opts.Report)
+		// Everything is written in a savepoint that is always rolled back so
+		// that the dry run can be run inside of a larger transaction.
+		sp, err := tx.Begin(ctxt)
+		if err != nil {
+			return sberr.AppendError(types.BulkDataUploadErr, err)
+		}
+		defer sp.Rollback(ctxt)
+		tx = sp
+	}
+
+	var rejected *rejectedRows
+	if skipRejected {
+		rejected = newRejectedRows(opts.Counts)
+		defer rejected.close()
+	}
+
+	var journal *importJournal
+	if resume {
+		journal = &importJournal{}
+	}
+
 	batch, _ := sbjobqueue.BatchWithContext(ctxt)
 
 	if err := UploadFromCSV(ctxt, state, tx, &CSVLoaderOpts[types.Client]{
-		Opts:  &opts.Opts,
-		Files: getFilesInDirFunc(opts.ClientDir),
-		Batch: batch,
+		Opts:     &opts.Opts,
+		Files:    getFilesInDirFunc(opts.ClientDir),
+		Batch:    batch,
+		Report:   report,
+		Rejected: rejected,
+		Journal:  journal,
 		Creator: resolveCreateFunc(
 			opts.ClientCreateType, dal.CreateClients, dal.EnsureClientsExist,
 		),
@@ -92,9 +177,12 @@ func BulkUploadData(
 	}
 
 	if err := UploadFromCSV(ctxt, state, tx, &CSVLoaderOpts[types.Exercise]{
-		Opts:  &opts.Opts,
-		Files: getFilesInDirFunc(opts.ExerciseDir),
-		Batch: batch,
+		Opts:     &opts.Opts,
+		Files:    getFilesInDirFunc(opts.ExerciseDir),
+		Batch:    batch,
+		Report:   report,
+		Rejected: rejected,
+		Journal:  journal,
 		Creator: resolveCreateFunc(
 			opts.ExerciseCreateType, dal.CreateExercises, dal.EnsureExercisesExist,
 		),
@@ -109,7 +197,10 @@ func BulkUploadData(
 				getFilesInDirFunc(opts.HyperparamsDir),
 				hyperparamFilterFunc(types.BarPathCalcFileExt),
 			),
-			Batch: batch,
+			Batch:    batch,
+			Report:   report,
+			Rejected: rejected,
+			Journal:  journal,
 			Creator: resolveCreateFunc(
 				opts.HyperparamsCreateType,
 				dal.CreateHyperparams[types.BarPathCalcHyperparams],
@@ -127,7 +218,10 @@ func BulkUploadData(
 				getFilesInDirFunc(opts.HyperparamsDir),
 				hyperparamFilterFunc(types.BarPathTrackerFileExt),
 			),
-			Batch: batch,
+			Batch:    batch,
+			Report:   report,
+			Rejected: rejected,
+			Journal:  journal,
 			Creator: resolveCreateFunc(
 				opts.HyperparamsCreateType,
 				dal.CreateHyperparams[types.BarPathTrackerHyperparams],
@@ -138,6 +232,27 @@ func BulkUploadData(
 		return sberr.AppendError(types.BulkDataUploadErr, err)
 	}
 
+	if err := UploadFromCSV(
+		ctxt, state, tx, &CSVLoaderOpts[types.FitnessFatigueHyperparams]{
+			Opts: &opts.Opts,
+			Files: util.FilterSeq2Err(
+				getFilesInDirFunc(opts.HyperparamsDir),
+				hyperparamFilterFunc(types.FitnessFatigueFileExt),
+			),
+			Batch:    batch,
+			Report:   report,
+			Rejected: rejected,
+			Journal:  journal,
+			Creator: resolveCreateFunc(
+				opts.HyperparamsCreateType,
+				dal.CreateHyperparams[types.FitnessFatigueHyperparams],
+				dal.EnsureHyperparamsExist[types.FitnessFatigueHyperparams],
+			),
+		},
+	); err != nil {
+		return sberr.AppendError(types.BulkDataUploadErr, err)
+	}
+
 	if err := batch.Wait(); err != nil {
 		return sberr.AppendError(types.BulkDataUploadErr, err)
 	}
@@ -146,11 +261,29 @@ func BulkUploadData(
 		Opts:                      &opts.Opts,
 		Files:                     getFilesInDirFunc(opts.WorkoutDir),
 		Batch:                     batch,
+		Report:                    report,
+		Rejected:                  rejected,
+		Journal:                   journal,
+		CreateType:                opts.WorkoutCreateType,
 		BarPathCalcHyperparams:    opts.BarPathCalcHyperparams,
 		BarPathTrackerHyperparams: opts.BarPathTrackerHyperparams,
 	}); err != nil {
 		return sberr.AppendError(types.BulkDataUploadErr, err)
 	}
 
-	return batch.Wait()
+	err := batch.Wait()
+	if err == nil && report != nil {
+		report.finish()
+	}
+	if err == nil && journal != nil {
+		if err := journal.finish(ctxt, state, tx); err != nil {
+			return sberr.AppendError(types.BulkDataUploadErr, err)
+		}
+	}
+	if err == nil && rejected != nil {
+		if err := rejected.close(); err != nil {
+			return sberr.AppendError(types.BulkDataUploadErr, err)
+		}
+	}
+	return err
 }
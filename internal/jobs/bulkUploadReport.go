@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	// Collects the problems found by a dry run bulk upload. When a loader is
+	// given a report it adds problems to it instead of returning an error so
+	// that every problem in every file is found. Safe for concurrent use.
+	uploadReport struct {
+		mtx sync.Mutex
+		res *types.BulkUploadReport
+	}
+)
+
+func newUploadReport(res *types.BulkUploadReport) *uploadReport {
+	return &uploadReport{res: res}
+}
+
+func (r *uploadReport) add(p types.BulkUploadProblem) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.res.Problems = append(r.res.Problems, p)
+}
+
+// Orders the problems by file, row, column, and error message and removes
+// duplicates. Problems are added by jobs running in parallel so they are in no
+// particular order until this is called. Duplicates come from dirs that are
+// read more than once, such as the hyperparams dir which is read once per
+// hyperparam type.
+func (r *uploadReport) finish() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	slices.SortStableFunc(
+		r.res.Problems,
+		func(a types.BulkUploadProblem, b types.BulkUploadProblem) int {
+			return cmp.Or(
+				cmp.Compare(a.File, b.File),
+				cmp.Compare(a.Row, b.Row),
+				cmp.Compare(a.Column, b.Column),
+				cmp.Compare(a.Err.Error(), b.Err.Error()),
+			)
+		},
+	)
+	r.res.Problems = slices.CompactFunc(
+		r.res.Problems,
+		func(a types.BulkUploadProblem, b types.BulkUploadProblem) bool {
+			return a.File == b.File && a.Row == b.Row &&
+				a.Column == b.Column && a.Err.Error() == b.Err.Error()
+		},
+	)
+}
+
+// Returns chunk options that put every file in a single chunk. Dry runs do not
+// chunk files so that the row of every problem is known. Workout files are not
+// chunked when rejecting rows so that the rows of a workout that spans two
+// chunks are never rejected twice.
+func singleChunkOpts(opts sbcsv.ChunkFileOpts) sbcsv.ChunkFileOpts {
+	opts.MinChunkRows = math.MaxInt
+	opts.MaxChunkRows = math.MaxInt
+	opts.RequestedNumChunks = 1
+	return opts
+}
+
+// Writes the supplied values, adding every value that cannot be written to
+// the report. rows holds the row of each value in the file.
+func dryRunWrite[T any](
+	ctxt context.Context,
+	tx pgx.Tx,
+	report *uploadReport,
+	file string,
+	rows []int,
+	vals []T,
+	write func(tx pgx.Tx, vals []T) error,
+) {
+	for i, err := range writeEachInSavepoint(ctxt, tx, vals, write) {
+		report.add(types.BulkUploadProblem{File: file, Row: rows[i], Err: err})
+	}
+}
+
+// Returns the line of a csv data row in its file given the number of the data
+// row, starting at 1. The header is the first line of every csv file. Rows
+// are assumed to not hold quoted values that span several lines.
+func csvRowLine(rowNum int) int {
+	return rowNum + 1
+}
+
+// Returns the name of the requested column that holds the value the supplied
+// error failed to parse, or an empty string if it cannot be determined. The
+// value is taken from the number or time parsing error when there is one.
+// Otherwise the column is only returned if it is the single requested column
+// whose value appears in the error.
+func csvErrColumn(
+	row []string,
+	reqCols []sbcsv.RequestedCols,
+	err error,
+) string {
+	var numErr *strconv.NumError
+	var timeErr *time.ParseError
+	matches := func(v string) bool { return strings.Contains(err.Error(), v) }
+	switch {
+	case errors.As(err, &numErr):
+		matches = func(v string) bool { return v == numErr.Num }
+	case errors.As(err, &timeErr):
+		matches = func(v string) bool { return v == timeErr.Value }
+	}
+
+	res := ""
+	for _, c := range reqCols {
+		if c.Idx < 0 || c.Idx >= len(row) || row[c.Idx] == "" ||
+			!matches(row[c.Idx]) {
+			continue
+		}
+		if res != "" {
+			return ""
+		}
+		res = c.Name
+	}
+	return res
+}
+
+// Returns the name of the field the supplied JSON Lines decoding error failed
+// on, or an empty string if the error is not tied to a single field.
+func jsonlErrColumn(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return typeErr.Field
+	}
+	return ""
+}
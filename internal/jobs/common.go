@@ -3,6 +3,9 @@ package jobs
 import (
 	"fmt"
 	"sync/atomic"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbjobqueue "code.barbellmath.net/barbell-math/smoothbrain-jobQueue"
 )
 
 var (
@@ -12,3 +15,21 @@ var (
 func formatJobLogLine(name string, uid uint64, msg string) string {
 	return fmt.Sprintf("JOB: %s (UID: %d): %s", name, uid, msg)
 }
+
+// Sends the supplied event to the progress sink of the state, if there is one.
+func sendProgress(state *types.State, e types.ProgressEvent) {
+	if state.Progress != nil {
+		state.Progress.Progress(e)
+	}
+}
+
+// Runs op while holding the lock of the supplied batch. Every job in a batch
+// shares the same transaction, which is backed by a single conn that is not
+// thread safe, so jobs must only use the transaction inside of op. This is
+// unfortunate... but it is the only way to keep a bulk upload in a single
+// transaction.
+func withTxLock(b *sbjobqueue.Batch, op func() error) error {
+	b.Lock()
+	defer b.Unlock()
+	return op()
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"io"
 	"iter"
+	"path"
+	"slices"
+	"strconv"
 
 	"code.barbellmath.net/barbell-math/providentia/internal/dal"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
@@ -24,16 +27,55 @@ type (
 		S         *types.State
 		Tx        pgx.Tx
 		UID       uint64
+		File      string
 		FileChunk io.Reader
 		Opts      *sbcsv.Opts
 		WriteFunc dal.CreateFunc[T]
+		Report    *uploadReport
+		Rejected  *rejectedRows
+		Header    []string
+		Journal   *journalChunk
 	}
 
+	genericJSONLLoader[T genericCSVAvailableTypes] struct {
+		B         *sbjobqueue.Batch
+		S         *types.State
+		Tx        pgx.Tx
+		UID       uint64
+		File      string
+		FileChunk *jsonlFileChunk
+		WriteFunc dal.CreateFunc[T]
+		Report    *uploadReport
+		Rejected  *rejectedRows
+		Journal   *journalChunk
+	}
+
+	// Files with a .jsonl extension are loaded as JSON Lines files, all other
+	// files are loaded as csv files.
 	CSVLoaderOpts[T genericCSVAvailableTypes] struct {
 		*sbcsv.Opts
 		Creator dal.CreateFunc[T]
 		Files   iter.Seq2[string, error]
 		Batch   *sbjobqueue.Batch
+		// When not nil problems are added to the report rather than returned
+		// and every value is written in a savepoint. Used for dry runs.
+		Report *uploadReport
+		// When not nil rows that cannot be uploaded are rejected rather than
+		// stopping the upload. Each file chunk is written in its own
+		// savepoint. Used by the [types.SkipRejectedRows] error policy.
+		// Ignored when Report is not nil.
+		Rejected *rejectedRows
+		// When not nil each file chunk is committed in its own transaction
+		// and chunks that the import journal records as completed are
+		// skipped. Ignored when Report is not nil.
+		Journal *importJournal
+	}
+
+	// The options used by [UploadFromCSVFiles].
+	CSVFilesOpts[T genericCSVAvailableTypes] struct {
+		*types.FromCSVOpts
+		Creator dal.CreateFunc[T]
+		Files   iter.Seq2[string, error]
 	}
 )
 
@@ -49,9 +91,18 @@ func UploadFromCSV[T genericCSVAvailableTypes](
 		opts.Batch, _ = sbjobqueue.BatchWithContext(ctxt)
 	}
 
+	chunkOpts := state.ClientCSVFileChunks
+	if opts.Report != nil {
+		chunkOpts = singleChunkOpts(chunkOpts)
+	}
+
 	for file, err := range opts.Files {
 		if err != nil {
-			return err
+			if opts.Report == nil {
+				return err
+			}
+			opts.Report.add(types.BulkUploadProblem{File: file, Err: err})
+			continue
 		}
 		select {
 		case <-ctxt.Done():
@@ -64,24 +115,100 @@ func UploadFromCSV[T genericCSVAvailableTypes](
 			formatJobLogLine("UploadFromCSV", 0, "Processing data file"),
 			"File", file,
 		)
+		sendProgress(state, types.ProgressEvent{
+			Kind: types.FileDiscovered, File: file,
+		})
+		if opts.Rejected != nil {
+			if err := opts.Rejected.reset(file); err != nil {
+				return err
+			}
+		}
+		var journaled *journaledFile
+		if opts.Journal != nil && opts.Report == nil {
+			if journaled, err = opts.Journal.open(ctxt, state, tx, file); err != nil {
+				return err
+			}
+		}
+		if path.Ext(file) == jsonlFileExt {
+			fileChunks, err := chunkJSONLFile(file, chunkOpts)
+			if err != nil {
+				if opts.Report == nil {
+					return err
+				}
+				opts.Report.add(types.BulkUploadProblem{File: file, Err: err})
+				continue
+			}
+			for i, chunk := range fileChunks {
+				chunkJournal, skip, err := journaled.chunk(i, chunk.Data)
+				if err != nil {
+					return err
+				} else if skip {
+					continue
+				}
+				uid := UID_CNTR.Add(1)
+				sendProgress(state, types.ProgressEvent{
+					Kind: types.ChunkScheduled, File: file, Job: uid,
+				})
+				state.CSVLoaderJobQueue.Schedule(&genericJSONLLoader[T]{
+					S:         state,
+					Tx:        tx,
+					B:         opts.Batch,
+					UID:       uid,
+					File:      file,
+					FileChunk: chunk,
+					WriteFunc: opts.Creator,
+					Report:    opts.Report,
+					Rejected:  opts.Rejected,
+					Journal:   chunkJournal,
+				})
+			}
+			continue
+		}
+
+		var header []string
+		if opts.Rejected != nil {
+			if header, err = readCSVHeader(file); err != nil {
+				return err
+			}
+		}
+
 		fileChunks, err := sbcsv.ChunkFile(
-			file, sbcsv.NewBasicFileChunk, state.ClientCSVFileChunks,
+			file, sbcsv.NewBasicFileChunk, chunkOpts,
 		)
 		if err != nil {
-			return err
+			if opts.Report == nil {
+				return err
+			}
+			opts.Report.add(types.BulkUploadProblem{File: file, Err: err})
+			continue
 		}
-		for _, chunk := range fileChunks {
+		for i, chunk := range fileChunks {
 			if len(chunk.Data) == 0 {
 				continue
 			}
+			chunkJournal, skip, err := journaled.chunk(i, chunk.Data)
+			if err != nil {
+				return err
+			} else if skip {
+				continue
+			}
+			uid := UID_CNTR.Add(1)
+			sendProgress(state, types.ProgressEvent{
+				Kind: types.ChunkScheduled, File: file, Job: uid,
+			})
 			state.CSVLoaderJobQueue.Schedule(&genericCSVLoader[T]{
 				S:         state,
 				Tx:        tx,
 				B:         opts.Batch,
-				UID:       UID_CNTR.Add(1),
+				UID:       uid,
+				File:      file,
 				FileChunk: chunk,
 				Opts:      opts.Opts,
 				WriteFunc: opts.Creator,
+				Report:    opts.Report,
+				Rejected:  opts.Rejected,
+				Header:    header,
+				Journal:   chunkJournal,
 			})
 		}
 	}
@@ -92,6 +219,42 @@ func UploadFromCSV[T genericCSVAvailableTypes](
 	return nil
 }
 
+// Uploads the supplied files using the error policy of the supplied options.
+// Used by the *FromCSV functions of the logic package.
+func UploadFromCSVFiles[T genericCSVAvailableTypes](
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts *CSVFilesOpts[T],
+) error {
+	if opts.ErrorPolicy == types.SkipRejectedRows && opts.Counts == nil {
+		return sberr.Wrap(
+			types.CSVLoaderJobQueueErr,
+			"When ErrorPolicy is SkipRejectedRows Counts must not be nil",
+		)
+	}
+
+	loaderOpts := CSVLoaderOpts[T]{
+		Opts:    &opts.Opts,
+		Creator: opts.Creator,
+		Files:   opts.Files,
+	}
+	if opts.ErrorPolicy == types.SkipRejectedRows {
+		loaderOpts.Rejected = newRejectedRows(opts.Counts)
+		defer loaderOpts.Rejected.close()
+	}
+
+	if err := UploadFromCSV(ctxt, state, tx, &loaderOpts); err != nil {
+		return err
+	}
+	if loaderOpts.Rejected != nil {
+		if err := loaderOpts.Rejected.close(); err != nil {
+			return sberr.AppendError(types.CSVLoaderJobQueueErr, err)
+		}
+	}
+	return nil
+}
+
 func (w *genericCSVLoader[T]) JobType(_ types.CSVLoaderJob) {}
 
 func (w *genericCSVLoader[T]) Batch() *sbjobqueue.Batch {
@@ -103,8 +266,28 @@ func (w *genericCSVLoader[T]) formatLogLine(msg string) string {
 }
 
 func (w *genericCSVLoader[T]) Run(ctxt context.Context) (opErr error) {
+	defer func() {
+		sendProgress(w.S, types.ProgressEvent{
+			Kind: types.ChunkCompleted, File: w.File, Job: w.UID, Err: opErr,
+		})
+	}()
+	if w.Journal != nil {
+		return w.Journal.run(ctxt, w.S, &w.Tx, w.run)
+	}
+	return w.run(ctxt)
+}
+
+func (w *genericCSVLoader[T]) run(ctxt context.Context) (opErr error) {
 	w.S.Log.Log(ctxt, sblog.VLevel(3), w.formatLogLine("Starting..."))
 
+	if w.Report != nil {
+		w.dryRun(ctxt)
+		return
+	}
+	if w.Rejected != nil {
+		return w.skipRejected(ctxt)
+	}
+
 	params := []T{}
 	if opErr = sbcsv.LoadReader(w.FileChunk, &sbcsv.LoadOpts{
 		Opts:          *w.Opts,
@@ -114,14 +297,15 @@ func (w *genericCSVLoader[T]) Run(ctxt context.Context) (opErr error) {
 		goto errReturn
 	}
 
-	// This is unfortunate... but it has to be done because a single transaction
-	// is backed by a single conn which is not thread safe.
-	w.B.Lock()
-	if opErr = w.WriteFunc(ctxt, w.S, w.Tx, params); opErr != nil {
+	if opErr = withTxLock(w.B, func() error {
+		return w.WriteFunc(ctxt, w.S, w.Tx, params)
+	}); opErr != nil {
 		goto errReturn
 	}
-	w.B.Unlock()
 
+	sendProgress(w.S, types.ProgressEvent{
+		Kind: types.RowsUploaded, File: w.File, Job: w.UID, NumRows: len(params),
+	})
 	w.S.Log.Log(
 		ctxt, sblog.VLevel(3),
 		w.formatLogLine("Finished loading clients"),
@@ -132,3 +316,253 @@ errReturn:
 	w.S.Log.Error(w.formatLogLine("Encountered error"), "Error", opErr)
 	return sberr.AppendError(types.CSVLoaderJobQueueErr, opErr)
 }
+
+// Loads and writes the file chunk, adding every row that could not be parsed
+// or written to the report. Dry runs put each file in a single chunk so rows
+// are counted from the start of the chunk.
+func (w *genericCSVLoader[T]) dryRun(ctxt context.Context) {
+	params, rows := []T{}, []int{}
+	rowNum := 0
+	if err := sbcsv.LoadReader(w.FileChunk, &sbcsv.LoadOpts{
+		Opts:          *w.Opts,
+		RequestedCols: sbcsv.ReqColsForStruct[T](),
+		Op: func(
+			o *sbcsv.Opts,
+			rowIdx int,
+			row []string,
+			reqCols []sbcsv.RequestedCols,
+		) error {
+			rowNum++
+			v, err := sbcsv.RowToStruct[T](o, row, reqCols)
+			if err != nil {
+				w.Report.add(types.BulkUploadProblem{
+					File:   w.File,
+					Row:    csvRowLine(rowNum),
+					Column: csvErrColumn(row, reqCols, err),
+					Err:    err,
+				})
+				return nil
+			}
+			params = append(params, v)
+			rows = append(rows, csvRowLine(rowNum))
+			return nil
+		},
+	}); err != nil {
+		w.Report.add(types.BulkUploadProblem{File: w.File, Err: err})
+	}
+
+	withTxLock(w.B, func() error {
+		dryRunWrite(
+			ctxt, w.Tx, w.Report, w.File, rows, params,
+			func(tx pgx.Tx, vals []T) error {
+				return w.WriteFunc(ctxt, w.S, tx, vals)
+			},
+		)
+		return nil
+	})
+
+	w.S.Log.Log(
+		ctxt, sblog.VLevel(3),
+		w.formatLogLine("Finished dry run"),
+		"NumRows", rowNum,
+	)
+}
+
+// Loads and writes the file chunk, rejecting every row that could not be
+// parsed or written. Errors that are not tied to a row, such as a missing
+// column, are still returned.
+func (w *genericCSVLoader[T]) skipRejected(ctxt context.Context) (opErr error) {
+	params, rows := []T{}, [][]string{}
+	var accepted int
+	if opErr = sbcsv.LoadReader(w.FileChunk, &sbcsv.LoadOpts{
+		Opts:          *w.Opts,
+		RequestedCols: sbcsv.ReqColsForStruct[T](),
+		Op: func(
+			o *sbcsv.Opts,
+			rowIdx int,
+			row []string,
+			reqCols []sbcsv.RequestedCols,
+		) error {
+			v, err := sbcsv.RowToStruct[T](o, row, reqCols)
+			if err != nil {
+				return w.Rejected.reject(w.File, w.Header, row, err)
+			}
+			params, rows = append(params, v), append(rows, slices.Clone(row))
+			return nil
+		},
+	}); opErr != nil {
+		goto errReturn
+	}
+
+	if accepted, opErr = rejectFailedWrites(
+		ctxt, w.B, w.Tx, w.Rejected, w.File, w.Header, rows, params,
+		func(tx pgx.Tx, vals []T) error {
+			return w.WriteFunc(ctxt, w.S, tx, vals)
+		},
+	); opErr != nil {
+		goto errReturn
+	}
+
+	sendProgress(w.S, types.ProgressEvent{
+		Kind: types.RowsUploaded, File: w.File, Job: w.UID, NumRows: accepted,
+	})
+	w.S.Log.Log(
+		ctxt, sblog.VLevel(3),
+		w.formatLogLine("Finished loading file chunk"),
+		"NumRows", len(params),
+	)
+	return
+errReturn:
+	w.S.Log.Error(w.formatLogLine("Encountered error"), "Error", opErr)
+	return sberr.AppendError(types.CSVLoaderJobQueueErr, opErr)
+}
+
+func (w *genericJSONLLoader[T]) JobType(_ types.CSVLoaderJob) {}
+
+func (w *genericJSONLLoader[T]) Batch() *sbjobqueue.Batch {
+	return w.B
+}
+
+func (w *genericJSONLLoader[T]) formatLogLine(msg string) string {
+	return formatJobLogLine("genericJSONLLoader", w.UID, msg)
+}
+
+func (w *genericJSONLLoader[T]) Run(ctxt context.Context) (opErr error) {
+	defer func() {
+		sendProgress(w.S, types.ProgressEvent{
+			Kind: types.ChunkCompleted, File: w.File, Job: w.UID, Err: opErr,
+		})
+	}()
+	if w.Journal != nil {
+		return w.Journal.run(ctxt, w.S, &w.Tx, w.run)
+	}
+	return w.run(ctxt)
+}
+
+func (w *genericJSONLLoader[T]) run(ctxt context.Context) (opErr error) {
+	w.S.Log.Log(ctxt, sblog.VLevel(3), w.formatLogLine("Starting..."))
+
+	if w.Report != nil {
+		w.dryRun(ctxt)
+		return
+	}
+	if w.Rejected != nil {
+		return w.skipRejected(ctxt)
+	}
+
+	params := []T{}
+	if opErr = loadJSONL(
+		w.FileChunk,
+		func(_ int, _ []byte, v *T) error {
+			params = append(params, *v)
+			return nil
+		},
+		nil,
+	); opErr != nil {
+		opErr = sberr.Wrap(opErr, "File: %s", w.File)
+		goto errReturn
+	}
+
+	if opErr = withTxLock(w.B, func() error {
+		return w.WriteFunc(ctxt, w.S, w.Tx, params)
+	}); opErr != nil {
+		goto errReturn
+	}
+
+	sendProgress(w.S, types.ProgressEvent{
+		Kind: types.RowsUploaded, File: w.File, Job: w.UID, NumRows: len(params),
+	})
+
+	w.S.Log.Log(
+		ctxt, sblog.VLevel(3),
+		w.formatLogLine("Finished loading file chunk"),
+		"NumRows", len(params),
+	)
+	return
+errReturn:
+	w.S.Log.Error(w.formatLogLine("Encountered error"), "Error", opErr)
+	return sberr.AppendError(types.CSVLoaderJobQueueErr, opErr)
+}
+
+// Loads and writes the file chunk, adding every line that could not be
+// decoded or written to the report.
+func (w *genericJSONLLoader[T]) dryRun(ctxt context.Context) {
+	params, rows := []T{}, []int{}
+	loadJSONL(
+		w.FileChunk,
+		func(lineNum int, _ []byte, v *T) error {
+			params, rows = append(params, *v), append(rows, lineNum)
+			return nil
+		},
+		func(lineNum int, _ []byte, err error) error {
+			w.Report.add(types.BulkUploadProblem{
+				File:   w.File,
+				Row:    lineNum,
+				Column: jsonlErrColumn(err),
+				Err:    err,
+			})
+			return nil
+		},
+	)
+
+	withTxLock(w.B, func() error {
+		dryRunWrite(
+			ctxt, w.Tx, w.Report, w.File, rows, params,
+			func(tx pgx.Tx, vals []T) error {
+				return w.WriteFunc(ctxt, w.S, tx, vals)
+			},
+		)
+		return nil
+	})
+
+	w.S.Log.Log(
+		ctxt, sblog.VLevel(3),
+		w.formatLogLine("Finished dry run"),
+		"NumRows", len(params),
+	)
+}
+
+// Loads and writes the file chunk, rejecting every line that could not be
+// decoded or written.
+func (w *genericJSONLLoader[T]) skipRejected(ctxt context.Context) (opErr error) {
+	params, rows := []T{}, [][]string{}
+	var accepted int
+	if opErr = loadJSONL(
+		w.FileChunk,
+		func(lineNum int, line []byte, v *T) error {
+			params = append(params, *v)
+			rows = append(rows, []string{strconv.Itoa(lineNum), string(line)})
+			return nil
+		},
+		func(lineNum int, line []byte, err error) error {
+			return w.Rejected.reject(
+				w.File, jsonlRejectedHeader,
+				[]string{strconv.Itoa(lineNum), string(line)}, err,
+			)
+		},
+	); opErr != nil {
+		goto errReturn
+	}
+
+	if accepted, opErr = rejectFailedWrites(
+		ctxt, w.B, w.Tx, w.Rejected, w.File, jsonlRejectedHeader, rows, params,
+		func(tx pgx.Tx, vals []T) error {
+			return w.WriteFunc(ctxt, w.S, tx, vals)
+		},
+	); opErr != nil {
+		goto errReturn
+	}
+
+	sendProgress(w.S, types.ProgressEvent{
+		Kind: types.RowsUploaded, File: w.File, Job: w.UID, NumRows: accepted,
+	})
+	w.S.Log.Log(
+		ctxt, sblog.VLevel(3),
+		w.formatLogLine("Finished loading file chunk"),
+		"NumRows", len(params),
+	)
+	return
+errReturn:
+	w.S.Log.Error(w.formatLogLine("Encountered error"), "Error", opErr)
+	return sberr.AppendError(types.CSVLoaderJobQueueErr, opErr)
+}
@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"encoding"
+	"encoding/csv"
+	"os"
+	"reflect"
+	"strconv"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+// Writes the supplied values to a csv file that the csv loaders can read back.
+// The column names are the names of the fields of T. Fields that implement
+// [encoding.TextMarshaler], such as enums, are written with their text value.
+func writeStructsToCSV[T any](file string, vals []T) (opErr error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return sberr.Wrap(
+			types.CouldNotDumpErr, "Expected a struct but got %s", t,
+		)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && opErr == nil {
+			opErr = err
+		}
+	}()
+
+	w := csv.NewWriter(f)
+	row := make([]string, t.NumField())
+	for i := range t.NumField() {
+		row[i] = t.Field(i).Name
+	}
+	if err := w.Write(row); err != nil {
+		return err
+	}
+
+	for _, v := range vals {
+		rv := reflect.ValueOf(v)
+		for i := range t.NumField() {
+			if row[i], err = formatCSVField(rv.Field(i)); err != nil {
+				return sberr.AppendError(
+					sberr.Wrap(
+						types.CouldNotDumpErr,
+						"Could not format field %s", t.Field(i).Name,
+					),
+					err,
+				)
+			}
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func formatCSVField(v reflect.Value) (string, error) {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return formatFloat(v.Float()), nil
+	default:
+		return "", sberr.Wrap(
+			types.CouldNotDumpErr, "Unsupported kind %s", v.Kind(),
+		)
+	}
+}
@@ -0,0 +1,243 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	fitnessfatigue "code.barbellmath.net/barbell-math/providentia/internal/models/fitnessFatigue"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	FitFitnessFatigueModelOpts struct {
+		Version int32
+		Emails  []string
+	}
+
+	PredictWeightOpts struct {
+		Email    string
+		Exercise string
+		Reps     int32
+		Effort   types.RPE
+		Date     time.Time
+		Res      *types.Kilogram
+	}
+
+	// The training log of a single exercise. The samples and ids are parallel
+	// slices.
+	exerciseHistory struct {
+		samples []fitnessfatigue.Sample
+		ids     []int64
+	}
+)
+
+// Fits the fitness fatigue model for every training log entry of each of the
+// supplied clients using the hyperparams with the supplied version. Estimated
+// maxes are calculated with the [types.GlobalConf.EstimatedMaxFormula], which
+// is stored with each model state. Any model states previously fit with the
+// same hyperparams are replaced. Entries that do not have enough history to fit
+// the model, or that the formula cannot predict a weight for, will not have a
+// model state.
+func FitFitnessFatigueModel(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts FitFitnessFatigueModelOpts,
+) error {
+	params := []types.FitnessFatigueHyperparams{}
+	if err := dal.ReadHyperparamsByVersionFor(
+		ctxt, state, tx, dal.ReadHyperparamsByVersionForOpts[types.FitnessFatigueHyperparams]{
+			Versions: []int32{opts.Version},
+			Params:   &params,
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotCreateAllModelStatesErr, err)
+	}
+
+	entries := []dal.TrainingLogEntry{}
+	for _, email := range opts.Emails {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		if err := dal.ReadTrainingLogForClient(
+			ctxt, state, tx, dal.ReadTrainingLogForClientOpts{
+				Email: email,
+				Res:   &entries,
+			},
+		); err != nil {
+			return sberr.AppendError(types.CouldNotCreateAllModelStatesErr, err)
+		}
+
+		formula := state.Global.EstimatedMaxFormula
+		createOpts := dal.CreateModelStatesOpts{
+			Email:   email,
+			ModelId: types.FitnessFatigue,
+			Version: opts.Version,
+			Formula: formula,
+		}
+		for _, h := range groupByExercise(entries) {
+			for i, s := range h.samples {
+				fit, ok := fitnessfatigue.Fit(
+					&params[0], formula, h.samples, s.DatePerformed,
+				)
+				if !ok {
+					continue
+				}
+				predWeight, ok := fit.PredictWeight(
+					&params[0], formula, h.samples, s.DatePerformed,
+					s.Reps, s.Effort,
+				)
+				if !ok {
+					continue
+				}
+				createOpts.States = append(createOpts.States, dal.ModelState{
+					TrainingLogId: h.ids[i],
+					V:             [10]float64{fit.Baseline, fit.Fitness, fit.Fatigue},
+					TimeFrame:     int64(params[0].TimeFrame),
+					MSE:           fit.MSE,
+					PredWeight:    predWeight,
+				})
+			}
+		}
+		if err := dal.CreateModelStates(ctxt, state, tx, createOpts); err != nil {
+			return sberr.AppendError(types.CouldNotCreateAllModelStatesErr, err)
+		}
+
+		state.Log.Log(
+			ctxt, sblog.VLevel(3),
+			"Finished fitting fitness fatigue model for client",
+			"NumEntries", len(entries),
+			"NumStates", len(createOpts.States),
+		)
+	}
+	return nil
+}
+
+// Predicts the weight the supplied client can lift for the supplied exercise,
+// reps, and effort on the supplied date using the default fitness fatigue
+// hyperparams. The most recent model state on or before the supplied date is
+// used. The model state must have been fit with the current
+// [types.GlobalConf.EstimatedMaxFormula], otherwise the model must be refit
+// before a weight can be predicted.
+func PredictWeight(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts PredictWeightOpts,
+) error {
+	if opts.Reps < 1 {
+		return sberr.Wrap(
+			types.CouldNotPredictWeightErr,
+			"Reps must be >=1, got %d", opts.Reps,
+		)
+	}
+	if opts.Effort < 0 || opts.Effort > 10 {
+		return sberr.Wrap(
+			types.CouldNotPredictWeightErr,
+			"Effort must be in the range [0, 10], got %f", opts.Effort,
+		)
+	}
+
+	var params types.FitnessFatigueHyperparams
+	if err := dal.ReadDefaultHyperparamsFor(ctxt, state, tx, &params); err != nil {
+		return sberr.AppendError(types.CouldNotPredictWeightErr, err)
+	}
+
+	var modelState dal.ModelState
+	var found bool
+	if err := dal.ReadLatestModelState(
+		ctxt, state, tx, dal.ReadLatestModelStateOpts{
+			Email:    opts.Email,
+			Exercise: opts.Exercise,
+			Date:     opts.Date,
+			ModelId:  types.FitnessFatigue,
+			Version:  params.Version,
+			Res:      &modelState,
+			Found:    &found,
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotPredictWeightErr, err)
+	}
+	if !found {
+		return sberr.Wrap(
+			types.NoModelStateErr,
+			"Client '%s' has no model state for exercise '%s' on or before %s (Has the model been fit?)",
+			opts.Email, opts.Exercise, opts.Date.Format(time.DateOnly),
+		)
+	}
+	// The fitted baseline is in terms of the estimated maxes of the formula
+	// the state was fit with so predicting with any other formula would
+	// silently give a wrong weight.
+	if modelState.Formula != state.Global.EstimatedMaxFormula {
+		return sberr.Wrap(
+			types.CouldNotPredictWeightErr,
+			"The model state was fit with the %s formula but the current formula is %s (Refit the model)",
+			modelState.Formula, state.Global.EstimatedMaxFormula,
+		)
+	}
+
+	entries := []dal.TrainingLogEntry{}
+	if err := dal.ReadTrainingLogForClient(
+		ctxt, state, tx, dal.ReadTrainingLogForClientOpts{
+			Email: opts.Email,
+			Res:   &entries,
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotPredictWeightErr, err)
+	}
+
+	fit := fitnessfatigue.State{
+		Baseline: modelState.V[0],
+		Fitness:  modelState.V[1],
+		Fatigue:  modelState.V[2],
+		MSE:      modelState.MSE,
+	}
+	var history []fitnessfatigue.Sample
+	if h, ok := groupByExercise(entries)[opts.Exercise]; ok {
+		history = h.samples
+	}
+	res, ok := fit.PredictWeight(
+		&params, state.Global.EstimatedMaxFormula, history,
+		opts.Date, opts.Reps, opts.Effort,
+	)
+	if !ok {
+		return sberr.Wrap(
+			types.CouldNotPredictWeightErr,
+			"The %s formula cannot be applied to %d reps at effort %f",
+			state.Global.EstimatedMaxFormula, opts.Reps, opts.Effort,
+		)
+	}
+	*opts.Res = res
+	return nil
+}
+
+// Groups the supplied training log entries by exercise. The order of the
+// entries is preserved within each group.
+func groupByExercise(
+	entries []dal.TrainingLogEntry,
+) map[string]*exerciseHistory {
+	res := map[string]*exerciseHistory{}
+	for _, e := range entries {
+		h, ok := res[e.ExerciseName]
+		if !ok {
+			h = &exerciseHistory{}
+			res[e.ExerciseName] = h
+		}
+		h.samples = append(h.samples, fitnessfatigue.Sample{
+			DatePerformed: e.DatePerformed,
+			Weight:        e.Weight,
+			Reps:          e.Reps,
+			Effort:        e.Effort,
+			Volume:        e.Volume,
+		})
+		h.ids = append(h.ids, e.Id)
+	}
+	return res
+}
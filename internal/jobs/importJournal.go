@@ -0,0 +1,169 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"os"
+	"sync"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	// Tracks the files of a resumable upload. Every file chunk of a resumable
+	// upload is committed in its own transaction along with an import journal
+	// entry so that the chunks a failed upload completed can be skipped when
+	// the upload is run again. Safe for concurrent use.
+	importJournal struct {
+		mtx   sync.Mutex
+		files []string
+	}
+
+	// The import journal entries of a single file.
+	journaledFile struct {
+		file      string
+		hash      []byte
+		completed map[int32][]byte
+	}
+
+	// A single file chunk of a resumable upload that has not been completed.
+	journalChunk struct {
+		file *journaledFile
+		idx  int32
+		hash []byte
+	}
+)
+
+// Hashes the supplied file and reads its import journal entries. An error is
+// returned if the file changed since its entries were written because the
+// chunks that were already committed no longer match the file.
+func (j *importJournal) open(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	file string,
+) (*journaledFile, error) {
+	hash, err := hashFile(file)
+	if err != nil {
+		return nil, sberr.AppendError(types.CouldNotReadImportJournalErr, err)
+	}
+
+	entries := []types.ImportJournalEntry{}
+	if err := dal.ReadImportJournal(ctxt, state, tx, dal.ReadImportJournalOpts{
+		File: file, Res: &entries,
+	}); err != nil {
+		return nil, err
+	}
+
+	res := &journaledFile{
+		file: file, hash: hash, completed: map[int32][]byte{},
+	}
+	for _, e := range entries {
+		if !bytes.Equal(e.FileHash, hash) {
+			return nil, sberr.Wrap(
+				types.ImportJournalMismatchErr,
+				"%s changed since it was partially uploaded, clear its import journal entries to upload it again",
+				file,
+			)
+		}
+		res.completed[e.Chunk] = e.ChunkHash
+	}
+
+	j.mtx.Lock()
+	j.files = append(j.files, file)
+	j.mtx.Unlock()
+	return res, nil
+}
+
+// Removes the import journal entries of every file that was opened. Called
+// once the upload has finished, in the same transaction as the upload, so
+// that the entries are only removed if the upload succeeds.
+func (j *importJournal) finish(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+) error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+	return dal.DeleteImportJournal(ctxt, state, tx, j.files)
+}
+
+// Returns the chunk with the supplied index and content. skip is true if the
+// chunk was completed by a previous upload. An error is returned if the chunk
+// was completed but its content changed, which happens when the chunk options
+// differ from the previous upload. A nil file is not journaled so nil is
+// returned for all of its chunks.
+func (f *journaledFile) chunk(
+	idx int,
+	data []byte,
+) (res *journalChunk, skip bool, err error) {
+	if f == nil {
+		return
+	}
+	hash := sha256.Sum256(data)
+	if prevHash, ok := f.completed[int32(idx)]; ok {
+		if !bytes.Equal(prevHash, hash[:]) {
+			err = sberr.Wrap(
+				types.ImportJournalMismatchErr,
+				"Chunk %d of %s does not match the chunk that was uploaded, the chunk options must not change when resuming an upload",
+				idx, f.file,
+			)
+			return
+		}
+		skip = true
+		return
+	}
+	res = &journalChunk{file: f, idx: int32(idx), hash: hash[:]}
+	return
+}
+
+// Runs the supplied op in a new transaction that is committed along with the
+// import journal entry of the chunk. tx is set to the new transaction before
+// the op is called. Errors returned by the op are returned as is.
+func (c *journalChunk) run(
+	ctxt context.Context,
+	state *types.State,
+	tx *pgx.Tx,
+	op func(ctxt context.Context) error,
+) error {
+	var opErr error
+	err := pgx.BeginTxFunc(
+		ctxt, state.DB, pgx.TxOptions{},
+		func(chunkTx pgx.Tx) error {
+			*tx = chunkTx
+			if opErr = op(ctxt); opErr != nil {
+				return opErr
+			}
+			return dal.CreateImportJournalEntries(
+				ctxt, state, chunkTx, []types.ImportJournalEntry{{
+					File:      c.file.file,
+					Chunk:     c.idx,
+					FileHash:  c.file.hash,
+					ChunkHash: c.hash,
+				}},
+			)
+		},
+	)
+	if err != nil && opErr == nil {
+		return sberr.AppendError(types.CSVLoaderJobQueueErr, err)
+	}
+	return err
+}
+
+func hashFile(file string) ([]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
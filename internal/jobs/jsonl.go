@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"runtime"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+type (
+	// A set of consecutive lines from a JSON Lines file. FirstLine is the line
+	// number of the first line in the chunk, starting at 1, and is only used to
+	// make errors point to the correct line in the file.
+	jsonlFileChunk struct {
+		Data      []byte
+		FirstLine int
+	}
+)
+
+const (
+	csvFileExt   = ".csv"
+	jsonlFileExt = ".jsonl"
+)
+
+// Writes the supplied values to the supplied writer as JSON Lines, one JSON
+// object per line.
+func WriteJSONL[T any](w io.Writer, vals []T) error {
+	enc := json.NewEncoder(w)
+	for i, v := range vals {
+		if err := enc.Encode(v); err != nil {
+			return sberr.AppendError(
+				sberr.Wrap(
+					types.CouldNotWriteJSONLErr, "Could not write value %d", i,
+				),
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// Reads all the values from the supplied JSON Lines reader. Blank lines are
+// skipped. Unknown fields are an error.
+func ReadJSONL[T any](r io.Reader) (res []T, err error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, sberr.AppendError(types.MalformedJSONLErr, err)
+	}
+	err = loadJSONL(
+		&jsonlFileChunk{Data: data, FirstLine: 1},
+		func(_ int, _ []byte, v *T) error {
+			res = append(res, *v)
+			return nil
+		},
+		nil,
+	)
+	return
+}
+
+// Splits the supplied JSON Lines file into chunks that can be processed in
+// parallel. Each line holds a single value so chunks can be split on any line.
+// The number of lines in each chunk respects the MinChunkRows, MaxChunkRows,
+// and RequestedNumChunks fields of the supplied options.
+func chunkJSONLFile(
+	file string,
+	opts sbcsv.ChunkFileOpts,
+) ([]*jsonlFileChunk, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lineEnds := []int{}
+	for i, b := range data {
+		if b == '\n' {
+			lineEnds = append(lineEnds, i+1)
+		}
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lineEnds = append(lineEnds, len(data))
+	}
+
+	numChunks := int(opts.RequestedNumChunks)
+	if numChunks <= 0 {
+		numChunks = runtime.NumCPU()
+	}
+	chunkLines := max(
+		(len(lineEnds)+numChunks-1)/numChunks, int(opts.MinChunkRows), 1,
+	)
+	if opts.MaxChunkRows > 0 {
+		chunkLines = min(chunkLines, int(opts.MaxChunkRows))
+	}
+
+	res := []*jsonlFileChunk{}
+	start := 0
+	for i := 0; i < len(lineEnds); i += chunkLines {
+		end := lineEnds[min(i+chunkLines, len(lineEnds))-1]
+		res = append(res, &jsonlFileChunk{
+			Data:      data[start:end],
+			FirstLine: i + 1,
+		})
+		start = end
+	}
+	return res, nil
+}
+
+// Decodes every line in the supplied chunk and calls op with the line number,
+// raw line, and decoded value. Blank lines are skipped. When a line cannot be
+// decoded or op returns an error onErr is called with the line number, raw
+// line, and error. If
+// onErr returns nil loading continues with the next line, otherwise the error
+// it returned is returned. A nil onErr stops at the first error.
+func loadJSONL[T any](
+	chunk *jsonlFileChunk,
+	op func(lineNum int, line []byte, v *T) error,
+	onErr func(lineNum int, line []byte, err error) error,
+) error {
+	if onErr == nil {
+		onErr = func(_ int, _ []byte, err error) error { return err }
+	}
+
+	data := chunk.Data
+	for lineNum := chunk.FirstLine; len(data) > 0; lineNum++ {
+		line := data
+		if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+			line, data = data[:idx], data[idx+1:]
+		} else {
+			data = nil
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var v T
+		var err error
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.DisallowUnknownFields()
+		if err = dec.Decode(&v); err == nil && dec.More() {
+			err = sberr.Wrap(
+				types.MalformedJSONLErr, "Each line must hold a single value",
+			)
+		}
+		if err == nil {
+			err = op(lineNum, line, &v)
+		}
+		if err != nil {
+			if err = onErr(lineNum, line, sberr.AppendError(
+				sberr.Wrap(types.MalformedJSONLErr, "Line %d", lineNum),
+				err,
+			)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
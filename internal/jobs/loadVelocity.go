@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	loadvelocity "code.barbellmath.net/barbell-math/providentia/internal/models/loadVelocity"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	CalcLoadVelocityProfileOpts struct {
+		Email    string
+		Exercise string
+		Res      *types.LoadVelocityProfile
+	}
+)
+
+// Calculates the load-velocity profile for the supplied client and exercise
+// from every set of the exercise that has physics data.
+func CalcLoadVelocityProfile(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts CalcLoadVelocityProfileOpts,
+) error {
+	data := []dal.SetVelocityData{}
+	if err := dal.ReadSetVelocityData(
+		ctxt, state, tx, dal.ReadSetVelocityDataOpts{
+			Email:    opts.Email,
+			Exercise: opts.Exercise,
+			Res:      &data,
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotCalcLoadVelocityProfileErr, err)
+	}
+
+	sets := make([]loadvelocity.Set, len(data))
+	for i, d := range data {
+		sets[i] = loadvelocity.Set(d)
+	}
+	if err := loadvelocity.Calc(sets, opts.Res); err != nil {
+		return sberr.AppendError(types.CouldNotCalcLoadVelocityProfileErr, err)
+	}
+	return nil
+}
@@ -43,7 +43,6 @@ func RunPhysicsJobs(
 	opts PhysicsOpts,
 ) error {
 	ceilSets := math.Ceil(opts.ExerciseData.Sets)
-	floorSets := math.Floor(opts.ExerciseData.Sets)
 	if opts.ExerciseData.Reps <= 0 {
 		return sberr.Wrap(
 			types.PhysicsJobQueueErr,
@@ -88,23 +87,13 @@ func RunPhysicsJobs(
 			opts.ExerciseData.PhysData[i].Present = false
 			continue
 		}
-
-		expReps := opts.ExerciseData.Reps
-		if ceilSets > opts.ExerciseData.Sets && int(floorSets) == i {
-			expReps = max(int32((opts.ExerciseData.Sets-floorSets)*float64(opts.ExerciseData.Reps)), 1)
+		if exerciseSet.Flag == types.VideoBarPathData &&
+			opts.BarTrackerCalcParams == nil {
+			return sberr.Wrap(
+				types.PhysicsJobQueueErr,
+				"Bar tracker hyperparams must be supplied when processing video data",
+			)
 		}
-		state.PhysicsJobQueue.Schedule(&physics{
-			B:                    opts.Batch,
-			S:                    state,
-			Tx:                   tx,
-			UID:                  UID_CNTR.Add(1),
-			BarPathCalcParams:    opts.BarPathCalcParams,
-			BarTrackerCalcParams: opts.BarTrackerCalcParams,
-			Weight:               opts.ExerciseData.Weight,
-			ExpNumReps:           expReps,
-			RawData:              opts.RawData[i],
-			Results:              &opts.ExerciseData.PhysData[i],
-		})
 
 		opts.ExerciseData.PhysData[i] = types.Optional[types.PhysicsData]{
 			Present: true,
@@ -112,6 +101,13 @@ func RunPhysicsJobs(
 		}
 		iterPhysData.Time = iterPhysData.Time[:0]
 		iterPhysData.Position = iterPhysData.Position[:0]
+
+		schedulePhysicsJob(
+			state, tx, opts.Batch,
+			opts.BarPathCalcParams, opts.BarTrackerCalcParams,
+			opts.ExerciseData.Weight, expNumReps(opts.ExerciseData, i),
+			exerciseSet, &opts.ExerciseData.PhysData[i],
+		)
 	}
 
 	if wait {
@@ -120,6 +116,60 @@ func RunPhysicsJobs(
 	return nil
 }
 
+// Returns the number of reps that are expected to be in the supplied set. This
+// will only differ from the number of reps in the exercise data for the last
+// set when a fractional number of sets was performed.
+func expNumReps(e *types.ExerciseData, set int) int32 {
+	floorSets := math.Floor(e.Sets)
+	if math.Ceil(e.Sets) > e.Sets && int(floorSets) == set {
+		return max(int32((e.Sets-floorSets)*float64(e.Reps)), 1)
+	}
+	return e.Reps
+}
+
+// Schedules the job that will calculate the physics data for a single set. Sets
+// with video data are scheduled on the video job queue, sets with time series
+// data are scheduled on the physics job queue.
+func schedulePhysicsJob(
+	state *types.State,
+	tx pgx.Tx,
+	b *sbjobqueue.Batch,
+	barPathCalcParams *types.BarPathCalcHyperparams,
+	barTrackerCalcParams *types.BarPathTrackerHyperparams,
+	weight types.Kilogram,
+	expNumReps int32,
+	rawData types.BarPathVariant,
+	res *types.Optional[types.PhysicsData],
+) {
+	if rawData.Flag == types.VideoBarPathData {
+		state.VideoJobQueue.Schedule(&video{
+			B:                    b,
+			S:                    state,
+			Tx:                   tx,
+			UID:                  UID_CNTR.Add(1),
+			BarPathCalcParams:    barPathCalcParams,
+			BarTrackerCalcParams: barTrackerCalcParams,
+			Weight:               weight,
+			ExpNumReps:           expNumReps,
+			VideoPath:            rawData.VideoPath,
+			Results:              res,
+		})
+		return
+	}
+	state.PhysicsJobQueue.Schedule(&physics{
+		B:                    b,
+		S:                    state,
+		Tx:                   tx,
+		UID:                  UID_CNTR.Add(1),
+		BarPathCalcParams:    barPathCalcParams,
+		BarTrackerCalcParams: barTrackerCalcParams,
+		Weight:               weight,
+		ExpNumReps:           expNumReps,
+		RawData:              rawData,
+		Results:              res,
+	})
+}
+
 func (p *physics) JobType(_ types.PhysicsJob) {}
 
 func (p *physics) Batch() *sbjobqueue.Batch {
@@ -131,16 +181,15 @@ func (p *physics) formatLogLine(msg string) string {
 }
 
 func (p *physics) Run(ctxt context.Context) (opErr error) {
+	defer func() {
+		sendProgress(p.S, types.ProgressEvent{
+			Kind: types.PhysicsJobFinished, Job: p.UID, Err: opErr,
+		})
+	}()
 	p.S.Log.Log(ctxt, sblog.VLevel(3), p.formatLogLine("Starting..."))
 
-	switch p.RawData.Flag {
-	case types.VideoBarPathData:
-		p.Results.Value.VideoPath = p.RawData.VideoPath
-		// TODO - run video model to set time and position data
-	case types.TimeSeriesBarPathData:
-		p.Results.Value.Time = p.RawData.TimeSeries.TimeData
-		p.Results.Value.Position = p.RawData.TimeSeries.PositionData
-	}
+	p.Results.Value.Time = p.RawData.TimeSeries.TimeData
+	p.Results.Value.Position = p.RawData.TimeSeries.PositionData
 
 	if opErr = barpathphysdata.Calc(
 		&p.Results.Value, p.BarPathCalcParams, p.Weight, p.ExpNumReps,
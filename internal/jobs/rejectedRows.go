@@ -0,0 +1,165 @@
+package jobs
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbjobqueue "code.barbellmath.net/barbell-math/smoothbrain-jobQueue"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	// Tracks the rows accepted and rejected by an upload that uses the
+	// [types.SkipRejectedRows] error policy. Rejected rows are written to a
+	// sidecar csv file next to the file they came from. Safe for concurrent
+	// use.
+	rejectedRows struct {
+		mtx    sync.Mutex
+		counts *types.UploadCounts
+		files  map[string]*rejectedFile
+	}
+
+	rejectedFile struct {
+		f *os.File
+		w *csv.Writer
+	}
+)
+
+const (
+	rejectedFileSuffix = ".rejected.csv"
+)
+
+var (
+	// The header of the rejected file of a JSON Lines file. Each rejected
+	// line is written with its line number and raw value.
+	jsonlRejectedHeader = []string{"Line", "Value"}
+)
+
+func newRejectedRows(counts *types.UploadCounts) *rejectedRows {
+	return &rejectedRows{counts: counts, files: map[string]*rejectedFile{}}
+}
+
+// Returns the name of the sidecar file that the rejected rows of the supplied
+// file are written to: <file name>.rejected.csv
+func rejectedFileName(file string) string {
+	return strings.TrimSuffix(file, path.Ext(file)) + rejectedFileSuffix
+}
+
+// Returns true if the supplied file is a sidecar file written by a previous
+// upload. Sidecar files are never loaded.
+func isRejectedFile(file string) bool {
+	return strings.HasSuffix(file, rejectedFileSuffix)
+}
+
+// Removes the sidecar file left by a previous upload of the supplied file so
+// that it only ever holds the rows rejected by the latest upload.
+func (r *rejectedRows) reset(file string) error {
+	err := os.Remove(rejectedFileName(file))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (r *rejectedRows) accept(n int) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.counts.Accepted += int64(n)
+}
+
+// Writes the supplied row to the sidecar file of the supplied file with the
+// error appended as the last column. The sidecar file is created with the
+// supplied header, followed by an Error column, the first time a row of the
+// file is rejected.
+func (r *rejectedRows) reject(
+	file string,
+	header []string,
+	row []string,
+	rowErr error,
+) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.counts.Rejected++
+
+	rf, ok := r.files[file]
+	if !ok {
+		f, err := os.Create(rejectedFileName(file))
+		if err != nil {
+			return err
+		}
+		rf = &rejectedFile{f: f, w: csv.NewWriter(f)}
+		r.files[file] = rf
+		if err := rf.w.Write(append(slices.Clone(header), "Error")); err != nil {
+			return err
+		}
+	}
+	return rf.w.Write(append(slices.Clone(row), rowErr.Error()))
+}
+
+// Flushes and closes every sidecar file.
+func (r *rejectedRows) close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	var res error
+	for file, rf := range r.files {
+		rf.w.Flush()
+		if err := rf.w.Error(); err != nil {
+			res = errors.Join(res, err)
+		}
+		if err := rf.f.Close(); err != nil {
+			res = errors.Join(res, err)
+		}
+		delete(r.files, file)
+	}
+	return res
+}
+
+// Writes the supplied values in a savepoint, rejecting every value that cannot
+// be written and accepting the rest. rows holds the raw row of each value in
+// the file. Returns the number of values that were accepted.
+func rejectFailedWrites[T any](
+	ctxt context.Context,
+	b *sbjobqueue.Batch,
+	tx pgx.Tx,
+	rejected *rejectedRows,
+	file string,
+	header []string,
+	rows [][]string,
+	vals []T,
+	write func(tx pgx.Tx, vals []T) error,
+) (int, error) {
+	var failed map[int]error
+	withTxLock(b, func() error {
+		failed = writeEachInSavepoint(ctxt, tx, vals, write)
+		return nil
+	})
+
+	for _, i := range slices.Sorted(maps.Keys(failed)) {
+		if err := rejected.reject(file, header, rows[i], failed[i]); err != nil {
+			return 0, err
+		}
+	}
+	rejected.accept(len(vals) - len(failed))
+	return len(vals) - len(failed), nil
+}
+
+// Reads the header of the supplied csv file so that it can be written to the
+// sidecar file of the csv file.
+func readCSVHeader(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return csv.NewReader(f).Read()
+}
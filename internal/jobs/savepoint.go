@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Writes the supplied values in a savepoint. If the write fails each value is
+// written in its own savepoint so that only the values that cannot be written
+// are left out. The returned map holds the index and error of every value that
+// could not be written.
+func writeEachInSavepoint[T any](
+	ctxt context.Context,
+	tx pgx.Tx,
+	vals []T,
+	write func(tx pgx.Tx, vals []T) error,
+) map[int]error {
+	res := map[int]error{}
+	if len(vals) == 0 {
+		return res
+	}
+	if err := writeInSavepoint(ctxt, tx, vals, write); err == nil {
+		return res
+	}
+	for i := range vals {
+		if err := writeInSavepoint(ctxt, tx, vals[i:i+1], write); err != nil {
+			res[i] = err
+		}
+	}
+	return res
+}
+
+// Writes the supplied values in a savepoint, rolling the savepoint back if the
+// write fails so that the transaction can still be used.
+func writeInSavepoint[T any](
+	ctxt context.Context,
+	tx pgx.Tx,
+	vals []T,
+	write func(tx pgx.Tx, vals []T) error,
+) error {
+	sp, err := tx.Begin(ctxt)
+	if err != nil {
+		return err
+	}
+	if err := write(sp, vals); err != nil {
+		sp.Rollback(ctxt)
+		return err
+	}
+	return sp.Commit(ctxt)
+}
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	velocityloss "code.barbellmath.net/barbell-math/providentia/internal/models/velocityLoss"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	ReadSetFatigueInDateRangeOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		Res   *[]types.SetFatigueEntry
+	}
+)
+
+// Calculates the set fatigue of every set with physics data for the supplied
+// client in the supplied date range.
+func ReadSetFatigueInDateRange(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ReadSetFatigueInDateRangeOpts,
+) error {
+	data := []dal.SetRepVelData{}
+	if err := dal.ReadSetRepVelDataInDateRange(
+		ctxt, state, tx, dal.ReadSetRepVelDataInDateRangeOpts{
+			Email: opts.Email,
+			Start: opts.Start,
+			End:   opts.End,
+			Res:   &data,
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotReadAllSetFatigueErr, err)
+	}
+
+	*opts.Res = (*opts.Res)[:0]
+	for _, d := range data {
+		iterEntry := types.SetFatigueEntry{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   opts.Email,
+				Session:       d.Session,
+				DatePerformed: d.DatePerformed,
+			},
+			Exercise: d.Exercise,
+			Weight:   d.Weight,
+			Reps:     d.Reps,
+			SetNum:   d.SetNum,
+		}
+		if !velocityloss.Calc(
+			d.MeanConcentricVel,
+			float64(state.Global.VelocityLossThreshold),
+			&iterEntry.SetFatigue,
+		) {
+			continue
+		}
+		*opts.Res = append(*opts.Res, iterEntry)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	strengthscore "code.barbellmath.net/barbell-math/providentia/internal/models/strengthScore"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	CalcStrengthScoresOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		Res   *types.StrengthScores
+	}
+)
+
+// Calculates the relative strength scores of the supplied client from their
+// best squat, bench, and deadlift in the supplied date range. The most recent
+// bodyweight entry before the end of the date range is used. The scores are
+// marked as partial if any of the lifts was not performed.
+func CalcStrengthScores(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts CalcStrengthScoresOpts,
+) error {
+	attrs := []types.ClientAttributes{}
+	if err := dal.ReadClientAttributes(
+		ctxt, state, tx, dal.ReadClientAttributesOpts{
+			Emails: []string{opts.Email},
+			Res:    &attrs,
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotCalcStrengthScoresErr, err)
+	}
+	if attrs[0].Sex == types.UnknownSex {
+		return sberr.AppendError(
+			types.CouldNotCalcStrengthScoresErr,
+			sberr.Wrap(
+				types.InvalidSexErr,
+				"The sex of client %s must be set", opts.Email,
+			),
+		)
+	}
+
+	var bodyweight types.Found[types.BodyweightEntry]
+	if err := dal.FindLatestBodyweight(
+		ctxt, state, tx, dal.FindLatestBodyweightOpts{
+			Email:  opts.Email,
+			Before: opts.End,
+			Res:    &bodyweight,
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotCalcStrengthScoresErr, err)
+	}
+	if !bodyweight.Found {
+		return sberr.AppendError(
+			types.CouldNotCalcStrengthScoresErr,
+			sberr.Wrap(
+				types.NoBodyweightErr,
+				"Client %s has no bodyweight entry before %s",
+				opts.Email, opts.End,
+			),
+		)
+	}
+
+	lifts := []dal.MainLiftEstimatedMax{}
+	if err := dal.ReadMainLiftEstimatedMaxes(
+		ctxt, state, tx, dal.ReadMainLiftEstimatedMaxesOpts{
+			Email:   opts.Email,
+			Start:   opts.Start,
+			End:     opts.End,
+			Formula: state.Global.EstimatedMaxFormula,
+			Res:     &lifts,
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotCalcStrengthScoresErr, err)
+	}
+
+	*opts.Res = types.StrengthScores{
+		ClientEmail: opts.Email,
+		Sex:         attrs[0].Sex,
+		Bodyweight:  bodyweight.Value,
+	}
+	best := map[types.ExerciseFocus]*types.Optional[types.EstimatedMaxEntry]{
+		types.Squat:    &opts.Res.Squat,
+		types.Bench:    &opts.Res.Bench,
+		types.Deadlift: &opts.Res.Deadlift,
+	}
+	for _, l := range lifts {
+		b := best[l.Focus]
+		if !b.Present || l.EstimatedMax > b.Value.EstimatedMax {
+			*b = types.Optional[types.EstimatedMaxEntry]{
+				Present: true,
+				Value:   l.EstimatedMaxEntry,
+			}
+		}
+	}
+	for _, f := range []types.ExerciseFocus{
+		types.Squat, types.Bench, types.Deadlift,
+	} {
+		if b := best[f]; b.Present {
+			opts.Res.Total += b.Value.EstimatedMax
+		} else {
+			opts.Res.Partial = true
+		}
+	}
+
+	bw, total := bodyweight.Value.Weight, opts.Res.Total
+	opts.Res.Dots, _ = strengthscore.Dots(opts.Res.Sex, bw, total)
+	opts.Res.Wilks, _ = strengthscore.Wilks(opts.Res.Sex, bw, total)
+	opts.Res.IPFGL, _ = strengthscore.IPFGL(opts.Res.Sex, bw, total)
+	return nil
+}
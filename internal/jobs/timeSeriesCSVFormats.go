@@ -0,0 +1,198 @@
+package jobs
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+var (
+	// The time series csv format that providentia exports and expects by
+	// default.
+	providentiaTimeSeriesCSVFormat = types.TimeSeriesCSVFormat{
+		Name:     "providentia",
+		Cols:     []string{"Time", "XPos", "YPos"},
+		ParseRow: parseTimeSeriesRow(1),
+	}
+	// The csv export format of the Metric VBT app. Displacement is exported in
+	// centimeters.
+	metricVBTTimeSeriesCSVFormat = types.TimeSeriesCSVFormat{
+		Name: "metricVBT",
+		Cols: []string{
+			"Time (s)",
+			"displacement (horizontal, cm)",
+			"displacement (vertical, cm)",
+		},
+		ParseRow: parseTimeSeriesRow(0.01),
+	}
+
+	timeSeriesCSVFormatsMtx sync.RWMutex
+	timeSeriesCSVFormats    = []types.TimeSeriesCSVFormat{
+		providentiaTimeSeriesCSVFormat,
+		metricVBTTimeSeriesCSVFormat,
+	}
+)
+
+// Returns a row parser for formats whose columns are time in seconds followed
+// by the x and y position. The position is multiplied by posScale to convert it
+// to meters.
+func parseTimeSeriesRow(
+	posScale types.Meter,
+) func(vals []string) (types.Second, types.Vec2[types.Meter, types.Meter], error) {
+	return func(
+		vals []string,
+	) (types.Second, types.Vec2[types.Meter, types.Meter], error) {
+		time, err := strconv.ParseFloat(vals[0], 64)
+		if err != nil {
+			return 0, types.Vec2[types.Meter, types.Meter]{}, err
+		}
+		xpos, err := strconv.ParseFloat(vals[1], 64)
+		if err != nil {
+			return 0, types.Vec2[types.Meter, types.Meter]{}, err
+		}
+		ypos, err := strconv.ParseFloat(vals[2], 64)
+		if err != nil {
+			return 0, types.Vec2[types.Meter, types.Meter]{}, err
+		}
+		return types.Second(time), types.Vec2[types.Meter, types.Meter]{
+			X: types.Meter(xpos) * posScale,
+			Y: types.Meter(ypos) * posScale,
+		}, nil
+	}
+}
+
+// Adds the supplied format to the list of formats that time series csv files
+// are checked against. Formats are checked in the order they were registered,
+// after the default providentia and Metric VBT formats.
+func RegisterTimeSeriesCSVFormat(f types.TimeSeriesCSVFormat) error {
+	if f.Name == "" {
+		return sberr.Wrap(
+			types.InvalidTimeSeriesCSVFormatErr, "The name must not be empty",
+		)
+	}
+	if len(f.Cols) == 0 {
+		return sberr.Wrap(
+			types.InvalidTimeSeriesCSVFormatErr,
+			"Format '%s' must request at least one column", f.Name,
+		)
+	}
+	if f.ParseRow == nil {
+		return sberr.Wrap(
+			types.InvalidTimeSeriesCSVFormatErr,
+			"Format '%s' must have a ParseRow function", f.Name,
+		)
+	}
+
+	timeSeriesCSVFormatsMtx.Lock()
+	defer timeSeriesCSVFormatsMtx.Unlock()
+	for _, iterF := range timeSeriesCSVFormats {
+		if iterF.Name == f.Name {
+			return sberr.Wrap(
+				types.InvalidTimeSeriesCSVFormatErr,
+				"A format with the name '%s' is already registered", f.Name,
+			)
+		}
+	}
+	timeSeriesCSVFormats = append(timeSeriesCSVFormats, f)
+	return nil
+}
+
+// Removes the format with the supplied name from the list of formats that
+// time series csv files are checked against. The default providentia and
+// Metric VBT formats cannot be removed.
+func UnregisterTimeSeriesCSVFormat(name string) error {
+	if name == providentiaTimeSeriesCSVFormat.Name ||
+		name == metricVBTTimeSeriesCSVFormat.Name {
+		return sberr.Wrap(
+			types.InvalidTimeSeriesCSVFormatErr,
+			"The default format '%s' cannot be unregistered", name,
+		)
+	}
+
+	timeSeriesCSVFormatsMtx.Lock()
+	defer timeSeriesCSVFormatsMtx.Unlock()
+	idx := slices.IndexFunc(
+		timeSeriesCSVFormats,
+		func(f types.TimeSeriesCSVFormat) bool { return f.Name == name },
+	)
+	if idx < 0 {
+		return sberr.Wrap(
+			types.UnknownTimeSeriesCSVFormatErr,
+			"No format with the name '%s' is registered", name,
+		)
+	}
+	// A new slice is made rather than deleting in place because loaders may
+	// be iterating over the current slice without holding the lock.
+	timeSeriesCSVFormats = slices.Concat(
+		timeSeriesCSVFormats[:idx], timeSeriesCSVFormats[idx+1:],
+	)
+	return nil
+}
+
+// Loads the supplied time series csv file using the first registered format
+// that has all of its columns present in the file.
+func LoadTimeSeriesCSVFile(
+	opts *sbcsv.Opts,
+	path string,
+) (types.RawTimeSeriesData, error) {
+	timeSeriesCSVFormatsMtx.RLock()
+	formats := timeSeriesCSVFormats
+	timeSeriesCSVFormatsMtx.RUnlock()
+
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+		res, err := loadTimeSeriesCSVFileWithFormat(opts, path, &f)
+		if errors.Is(err, sbcsv.MissingColumnErr) {
+			continue
+		}
+		return res, err
+	}
+	return types.RawTimeSeriesData{}, sberr.Wrap(
+		types.UnknownTimeSeriesCSVFormatErr,
+		"'%s' did not match any of the registered formats: %s",
+		path, strings.Join(names, ", "),
+	)
+}
+
+func loadTimeSeriesCSVFileWithFormat(
+	opts *sbcsv.Opts,
+	path string,
+	f *types.TimeSeriesCSVFormat,
+) (types.RawTimeSeriesData, error) {
+	reqCols := make([]sbcsv.RequestedCols, len(f.Cols))
+	for i, c := range f.Cols {
+		reqCols[i] = sbcsv.RequestedCols{Name: c}
+	}
+
+	res := types.RawTimeSeriesData{}
+	vals := make([]string, len(f.Cols))
+	err := sbcsv.LoadFile(path, &sbcsv.LoadOpts{
+		Opts:          *opts,
+		RequestedCols: reqCols,
+		Op: func(
+			o *sbcsv.Opts,
+			rowIdx int,
+			row []string,
+			reqCols []sbcsv.RequestedCols,
+		) error {
+			for i := range reqCols {
+				vals[i] = row[reqCols[i].Idx]
+			}
+			time, pos, err := f.ParseRow(vals)
+			if err != nil {
+				return err
+			}
+			res.TimeData = append(res.TimeData, time)
+			res.PositionData = append(res.PositionData, pos)
+			return nil
+		},
+	})
+	return res, err
+}
@@ -0,0 +1,265 @@
+package jobs
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sbjobqueue "code.barbellmath.net/barbell-math/smoothbrain-jobQueue"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	// Caches the hyperparams used when recalculating physics data so each
+	// version is only read from the database once.
+	updateHyperparams struct {
+		calc    map[int32]*types.BarPathCalcHyperparams
+		tracker map[int32]*types.BarPathTrackerHyperparams
+	}
+)
+
+// Updates the supplied workouts in place. Each supplied workout must already
+// exist and must have the same number of exercises as the existing workout so
+// that the inter workout counters of each exercise remain stable. The physics
+// data of each exercise must be empty or have one entry per set. Only training
+// log entries that changed are updated. Physics data is only recalculated for
+// sets whose bar path input changed, all other physics data is left untouched.
+func UpdateWorkouts(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	workouts []types.Workout,
+) error {
+	ids := make([]types.WorkoutId, len(workouts))
+	for i := range workouts {
+		ids[i] = workouts[i].WorkoutId
+	}
+	existing := []types.Workout{}
+	if err := dal.ReadWorkoutsById(
+		ctxt, state, tx, dal.ReadWorkoutsByIdOpts{Ids: ids, Res: &existing},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotUpdateAllWorkoutsErr, err)
+	}
+
+	// The supplied workouts are copied so the recalculated physics data does
+	// not modify the callers data.
+	opts := dal.UpdateWorkoutsOpts{Workouts: slices.Clone(workouts)}
+	params := updateHyperparams{
+		calc:    map[int32]*types.BarPathCalcHyperparams{},
+		tracker: map[int32]*types.BarPathTrackerHyperparams{},
+	}
+	b, _ := sbjobqueue.BatchWithContext(ctxt)
+
+	for i := range opts.Workouts {
+		select {
+		case <-ctxt.Done():
+			return ctxt.Err()
+		default:
+		}
+
+		w := &opts.Workouts[i]
+		if len(w.Exercises) != len(existing[i].Exercises) {
+			return sberr.Wrap(
+				types.CouldNotUpdateAllWorkoutsErr,
+				"Workout with id '%+v' has %d exercises, expected %d (Exercises cannot be added or removed)",
+				w.WorkoutId, len(w.Exercises), len(existing[i].Exercises),
+			)
+		}
+
+		w.Exercises = slices.Clone(w.Exercises)
+		for j := range w.Exercises {
+			e := &w.Exercises[j]
+			oldE := &existing[i].Exercises[j]
+			// Physics data is stored by its index in PhysData so a slice
+			// that does not have an entry for every set would be written to
+			// the wrong sets.
+			if numSets := int(math.Ceil(e.Sets)); len(e.PhysData) != 0 &&
+				len(e.PhysData) != numSets {
+				return sberr.Wrap(
+					types.CouldNotUpdateAllWorkoutsErr,
+					"Exercise %d of workout with id '%+v' has %d physics data entries, expected 0 or %d (one per set)",
+					j, w.WorkoutId, len(e.PhysData), numSets,
+				)
+			}
+			if exerciseChanged(e, oldE) {
+				opts.Exercises = append(
+					opts.Exercises, dal.WorkoutIdx{Workout: i, Exercise: j},
+				)
+			}
+
+			e.PhysData = slices.Clone(e.PhysData)
+			for k := range max(len(e.PhysData), len(oldE.PhysData)) {
+				if !physDataChanged(e, oldE, k) {
+					continue
+				}
+				opts.Sets = append(
+					opts.Sets, dal.WorkoutIdx{Workout: i, Exercise: j, Set: k},
+				)
+				if k >= len(e.PhysData) || !e.PhysData[k].Present {
+					continue
+				}
+
+				if err := schedulePhysicsUpdate(
+					ctxt, state, tx, b, &params, e, oldE, k,
+				); err != nil {
+					return sberr.AppendError(
+						types.CouldNotUpdateAllWorkoutsErr, err,
+					)
+				}
+			}
+		}
+	}
+
+	if err := b.Wait(); err != nil {
+		return sberr.AppendError(types.CouldNotUpdateAllWorkoutsErr, err)
+	}
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		"Finished recalculating physics data for updated workouts",
+		"NumSets", len(opts.Sets),
+	)
+
+	return dal.UpdateWorkouts(ctxt, state, tx, opts)
+}
+
+func exerciseChanged(e *types.ExerciseData, oldE *types.ExerciseData) bool {
+	return e.Name != oldE.Name ||
+		e.Weight != oldE.Weight ||
+		e.Sets != oldE.Sets ||
+		e.Reps != oldE.Reps ||
+		e.Effort != oldE.Effort
+}
+
+// Returns true if the physics data for the supplied set needs to be replaced.
+// This happens when physics data was added or removed, when the bar path input
+// changed, or when the weight or expected number of reps used to calculate the
+// physics data changed.
+func physDataChanged(
+	e *types.ExerciseData,
+	oldE *types.ExerciseData,
+	set int,
+) bool {
+	present := set < len(e.PhysData) && e.PhysData[set].Present
+	oldPresent := set < len(oldE.PhysData) && oldE.PhysData[set].Present
+	if present != oldPresent {
+		return true
+	}
+	if !present {
+		return false
+	}
+	if e.Weight != oldE.Weight ||
+		expNumReps(e, set) != expNumReps(oldE, set) {
+		return true
+	}
+
+	p := &e.PhysData[set].Value
+	oldP := &oldE.PhysData[set].Value
+	return p.VideoPath != oldP.VideoPath ||
+		p.BarPathCalcVersion != oldP.BarPathCalcVersion ||
+		p.BarPathTrackerVersion != oldP.BarPathTrackerVersion ||
+		!slices.Equal(p.Time, oldP.Time) ||
+		!slices.Equal(p.Position, oldP.Position)
+}
+
+// Schedules the physics job that will recalculate the physics data for the
+// supplied set. A new video path will be re-tracked, otherwise the time series
+// data is used so unchanged videos do not need to be tracked again.
+func schedulePhysicsUpdate(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	b *sbjobqueue.Batch,
+	params *updateHyperparams,
+	e *types.ExerciseData,
+	oldE *types.ExerciseData,
+	set int,
+) error {
+	if e.Reps <= 0 {
+		return sberr.Wrap(
+			types.PhysicsJobQueueErr,
+			"Supplied exercise data must have at least 1 rep",
+		)
+	}
+
+	p := e.PhysData[set].Value
+	oldVideoPath := ""
+	if set < len(oldE.PhysData) {
+		oldVideoPath = oldE.PhysData[set].Value.VideoPath
+	}
+
+	var rawData types.BarPathVariant
+	switch {
+	case p.VideoPath != "" && (p.VideoPath != oldVideoPath || len(p.Time) == 0):
+		rawData = types.BarPathVariant{
+			Flag:      types.VideoBarPathData,
+			VideoPath: p.VideoPath,
+		}
+	case len(p.Time) > 0:
+		rawData = types.BarPathVariant{
+			Flag: types.TimeSeriesBarPathData,
+			TimeSeries: types.RawTimeSeriesData{
+				TimeData:     p.Time,
+				PositionData: p.Position,
+			},
+		}
+	default:
+		return sberr.Wrap(
+			types.PhysicsJobQueueErr,
+			"Set %d of exercise '%s' has physics data but no bar path data",
+			set+1, e.Name,
+		)
+	}
+
+	calcParams, err := readUpdateHyperparams(
+		ctxt, state, tx, params.calc, p.BarPathCalcVersion,
+	)
+	if err != nil {
+		return err
+	}
+	trackerParams, err := readUpdateHyperparams(
+		ctxt, state, tx, params.tracker, p.BarPathTrackerVersion,
+	)
+	if err != nil {
+		return err
+	}
+
+	e.PhysData[set] = types.Optional[types.PhysicsData]{
+		Present: true,
+		Value: types.PhysicsData{
+			VideoPath:             p.VideoPath,
+			BarPathTrackerVersion: p.BarPathTrackerVersion,
+		},
+	}
+	schedulePhysicsJob(
+		state, tx, b, calcParams, trackerParams,
+		e.Weight, expNumReps(e, set), rawData, &e.PhysData[set],
+	)
+	return nil
+}
+
+func readUpdateHyperparams[T types.Hyperparams](
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	cache map[int32]*T,
+	version int32,
+) (*T, error) {
+	if res, ok := cache[version]; ok {
+		return res, nil
+	}
+	res := []T{}
+	if err := dal.ReadHyperparamsByVersionFor(
+		ctxt, state, tx, dal.ReadHyperparamsByVersionForOpts[T]{
+			Versions: []int32{version},
+			Params:   &res,
+		},
+	); err != nil {
+		return nil, err
+	}
+	cache[version] = &res[0]
+	return &res[0], nil
+}
@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	"context"
+
+	barpathphysdata "code.barbellmath.net/barbell-math/providentia/internal/models/barPathPhysData"
+	barpathtracker "code.barbellmath.net/barbell-math/providentia/internal/models/barPathTracker"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sbjobqueue "code.barbellmath.net/barbell-math/smoothbrain-jobQueue"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	video struct {
+		B   *sbjobqueue.Batch
+		S   *types.State
+		Tx  pgx.Tx
+		UID uint64
+
+		BarPathCalcParams    *types.BarPathCalcHyperparams
+		BarTrackerCalcParams *types.BarPathTrackerHyperparams
+		Weight               types.Kilogram
+		ExpNumReps           int32
+		VideoPath            string
+		Results              *types.Optional[types.PhysicsData]
+	}
+)
+
+func (v *video) JobType(_ types.VideoJob) {}
+
+func (v *video) Batch() *sbjobqueue.Batch {
+	return v.B
+}
+
+func (v *video) formatLogLine(msg string) string {
+	return formatJobLogLine("video", v.UID, msg)
+}
+
+func (v *video) Run(ctxt context.Context) (opErr error) {
+	defer func() {
+		sendProgress(v.S, types.ProgressEvent{
+			Kind: types.PhysicsJobFinished, Job: v.UID, Err: opErr,
+		})
+	}()
+	v.S.Log.Log(ctxt, sblog.VLevel(3), v.formatLogLine("Starting..."))
+
+	var rawData types.RawTimeSeriesData
+	if opErr = barpathtracker.Track(
+		ctxt, v.VideoPath, v.BarTrackerCalcParams, &rawData,
+	); opErr != nil {
+		goto errReturn
+	}
+	v.Results.Value.VideoPath = v.VideoPath
+	v.Results.Value.BarPathTrackerVersion = v.BarTrackerCalcParams.Version
+	v.Results.Value.Time = rawData.TimeData
+	v.Results.Value.Position = rawData.PositionData
+	v.S.Log.Log(
+		ctxt, sblog.VLevel(3),
+		v.formatLogLine("Finished tracking bar path"),
+		"NumFrames", len(rawData.TimeData),
+	)
+
+	if opErr = barpathphysdata.Calc(
+		&v.Results.Value, v.BarPathCalcParams, v.Weight, v.ExpNumReps,
+	); opErr != nil {
+		goto errReturn
+	}
+
+	v.Results.Present = true
+	v.S.Log.Log(
+		ctxt, sblog.VLevel(3),
+		v.formatLogLine("Finished processing physics data"),
+	)
+	return nil
+errReturn:
+	v.S.Log.Error(v.formatLogLine("Encountered error"), "Error", opErr)
+	return sberr.AppendError(types.VideoJobQueueErr, opErr)
+}
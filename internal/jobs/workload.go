@@ -0,0 +1,155 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/internal/models/workload"
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	CalcACWROpts struct {
+		Email  string
+		Start  time.Time
+		End    time.Time
+		Method types.ACWRMethod
+		types.WorkloadOpts
+		Res *[]types.ACWR
+	}
+
+	CalcMonotonyStrainOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		types.WorkloadOpts
+		Res *[]types.MonotonyStrain
+	}
+)
+
+// Calculates the acute chronic workload ratio of every day in the supplied
+// date range for the supplied client.
+func CalcACWR(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts CalcACWROpts,
+) error {
+	var lookback int
+	var calc func(loads []float64, res []types.ACWR)
+	switch opts.Method {
+	case types.RollingAverage:
+		lookback, calc = workload.RollingLookbackDays, workload.RollingACWR
+	case types.EWMA:
+		lookback, calc = workload.EWMALookbackDays, workload.EWMAACWR
+	default:
+		return sberr.AppendError(
+			types.CouldNotCalcACWRErr,
+			sberr.Wrap(types.InvalidACWRMethodErr, "Got: %s", opts.Method),
+		)
+	}
+
+	start, end := util.TruncDate(opts.Start), util.TruncDate(opts.End)
+	loads, err := readDailyLoads(
+		ctxt, state, tx, opts.Email, start, end, lookback, opts.WorkloadOpts,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotCalcACWRErr, err)
+	}
+
+	all := make([]types.ACWR, len(loads))
+	calc(loads, all)
+	*opts.Res = append((*opts.Res)[:0], all[lookback:]...)
+	for i := range *opts.Res {
+		(*opts.Res)[i].Date = start.AddDate(0, 0, i)
+	}
+	return nil
+}
+
+// Calculates Fosters training monotony and strain of every day in the
+// supplied date range for the supplied client.
+func CalcMonotonyStrain(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts CalcMonotonyStrainOpts,
+) error {
+	lookback := workload.MonotonyDays - 1
+	start, end := util.TruncDate(opts.Start), util.TruncDate(opts.End)
+	loads, err := readDailyLoads(
+		ctxt, state, tx, opts.Email, start, end, lookback, opts.WorkloadOpts,
+	)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotCalcMonotonyStrainErr, err)
+	}
+
+	all := make([]types.MonotonyStrain, len(loads))
+	workload.MonotonyStrain(loads, all)
+	*opts.Res = append((*opts.Res)[:0], all[lookback:]...)
+	for i := range *opts.Res {
+		(*opts.Res)[i].Date = start.AddDate(0, 0, i)
+	}
+	return nil
+}
+
+// Reads the load of every day in [start-lookback days, end) with days that
+// have no training log entries having a load of 0. The start and end dates
+// must already be truncated to the start of their days. Days are counted
+// between calendar dates so the supplied dates may be in any location, even
+// across daylight saving time transitions.
+func readDailyLoads(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	email string,
+	start time.Time,
+	end time.Time,
+	lookback int,
+	opts types.WorkloadOpts,
+) ([]float64, error) {
+	if opts.Metric != types.VolumeLoad && opts.Metric != types.ExertionLoad {
+		return nil, sberr.Wrap(
+			types.InvalidWorkloadMetricErr, "Got: %s", opts.Metric,
+		)
+	}
+	if end.Before(start) {
+		return nil, sberr.Wrap(
+			types.CouldNotReadAllTrainingLogsErr,
+			"Start date (%s) must be before end date (%s)", start, end,
+		)
+	}
+
+	first := start.AddDate(0, 0, -lookback)
+	data := []dal.DailyTrainingLoad{}
+	if err := dal.ReadDailyTrainingLoad(
+		ctxt, state, tx, dal.ReadDailyTrainingLoadOpts{
+			Email: email,
+			Start: util.UTCDate(first),
+			End:   util.UTCDate(end),
+			Focus: opts.Focus,
+			Res:   &data,
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	numDays := lookback + util.DaysBetween(start, end)
+	res := make([]float64, numDays)
+	for _, d := range data {
+		idx := util.DaysBetween(first, d.DatePerformed)
+		if idx < 0 || idx >= len(res) {
+			continue
+		}
+		switch opts.Metric {
+		case types.VolumeLoad:
+			res[idx] = float64(d.Volume)
+		case types.ExertionLoad:
+			res[idx] = float64(d.Exertion)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,200 @@
+package jobs
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sblog "code.barbellmath.net/barbell-math/smoothbrain-logging"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	ExportWorkoutsOpts struct {
+		Email string
+		Start time.Time
+		End   time.Time
+		Dir   string
+	}
+)
+
+var (
+	// The columns of an exported workout csv file. They must match the fields
+	// of [rawWorkoutData] so exported files can be loaded.
+	workoutCSVCols = []string{
+		"DatePerformed", "Session", "Exercise", "Weight", "Sets", "Reps",
+		"Effort", "DataDir",
+	}
+)
+
+// Writes the workouts of the supplied client in the supplied date range to the
+// supplied dir in the layout that [BulkUploadData] loads. Workouts are written
+// to <dir>/[types.ExportWorkoutDir]/<email>.csv and the time series data of
+// each set with physics data is written to a SetN.csv file in the
+// providentia time series format under <dir>/[types.ExportPhysDataDir].
+func ExportWorkouts(
+	ctxt context.Context,
+	state *types.State,
+	tx pgx.Tx,
+	opts ExportWorkoutsOpts,
+) (opErr error) {
+	workoutDir := filepath.Join(opts.Dir, types.ExportWorkoutDir)
+	if err := os.MkdirAll(workoutDir, 0o755); err != nil {
+		return sberr.AppendError(types.CouldNotExportWorkoutsErr, err)
+	}
+	f, err := os.Create(filepath.Join(workoutDir, opts.Email+".csv"))
+	if err != nil {
+		return sberr.AppendError(types.CouldNotExportWorkoutsErr, err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil && opErr == nil {
+			opErr = sberr.AppendError(types.CouldNotExportWorkoutsErr, err)
+		}
+	}()
+
+	w := csv.NewWriter(f)
+	if err := w.Write(workoutCSVCols); err != nil {
+		return sberr.AppendError(types.CouldNotExportWorkoutsErr, err)
+	}
+
+	numWorkouts := 0
+	var writeErr error
+	if err := dal.StreamWorkoutsInDateRange(
+		ctxt, state, tx, dal.StreamWorkoutsInDateRangeOpts{
+			Email: opts.Email,
+			Start: opts.Start,
+			End:   opts.End,
+			Yield: func(workout types.Workout) bool {
+				writeErr = exportWorkout(w, opts.Dir, &workout)
+				numWorkouts++
+				return writeErr == nil
+			},
+		},
+	); err != nil {
+		return sberr.AppendError(types.CouldNotExportWorkoutsErr, err)
+	}
+	if writeErr != nil {
+		return sberr.AppendError(types.CouldNotExportWorkoutsErr, writeErr)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return sberr.AppendError(types.CouldNotExportWorkoutsErr, err)
+	}
+
+	state.Log.Log(
+		ctxt, sblog.VLevel(3),
+		formatJobLogLine("ExportWorkouts", 0, "Finished exporting workouts"),
+		"Email", opts.Email,
+		"Dir", opts.Dir,
+		"NumWorkouts", numWorkouts,
+	)
+	return nil
+}
+
+func exportWorkout(w *csv.Writer, dir string, workout *types.Workout) error {
+	date := workout.DatePerformed.Format(types.ExportDateFormat)
+	session := strconv.FormatUint(uint64(workout.Session), 10)
+
+	for i, e := range workout.Exercises {
+		dataDir := ""
+		if hasTimeSeriesData(e.PhysData) {
+			relDir := path.Join(
+				types.ExportPhysDataDir, workout.ClientEmail,
+				fmt.Sprintf("%s.%s.%d", date, session, i),
+			)
+			if err := exportSetData(
+				filepath.Join(dir, filepath.FromSlash(relDir)), e.PhysData,
+			); err != nil {
+				return err
+			}
+			// Data dirs are relative to the dir of the workout file
+			dataDir = path.Join("..", relDir)
+		}
+
+		if err := w.Write([]string{
+			date,
+			session,
+			e.Name,
+			formatFloat(float64(e.Weight)),
+			formatFloat(e.Sets),
+			strconv.FormatInt(int64(e.Reps), 10),
+			formatFloat(float64(e.Effort)),
+			dataDir,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportSetData(
+	dir string,
+	physData []types.Optional[types.PhysicsData],
+) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, p := range physData {
+		if !p.Present || len(p.Value.Time) == 0 {
+			continue
+		}
+		if err := exportTimeSeries(
+			filepath.Join(dir, fmt.Sprintf("Set%d.csv", i+1)), &p.Value,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportTimeSeries(file string, p *types.PhysicsData) (opErr error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil && opErr == nil {
+			opErr = err
+		}
+	}()
+
+	w := csv.NewWriter(f)
+	if err := w.Write(providentiaTimeSeriesCSVFormat.Cols); err != nil {
+		return err
+	}
+	for i := range p.Time {
+		if err := w.Write([]string{
+			formatFloat(float64(p.Time[i])),
+			formatFloat(float64(p.Position[i].X)),
+			formatFloat(float64(p.Position[i].Y)),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func hasTimeSeriesData(physData []types.Optional[types.PhysicsData]) bool {
+	for _, p := range physData {
+		if p.Present && len(p.Value.Time) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Formats floats with the smallest number of digits that will parse back to
+// the exact same value.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
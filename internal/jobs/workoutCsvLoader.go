@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	barpathphysdata "code.barbellmath.net/barbell-math/providentia/internal/models/barPathPhysData"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
 	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
 	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
@@ -55,18 +56,58 @@ type (
 		UID         uint64
 		ClientEmail string
 		FileDir     string
+		File        string
 		FileChunk   *workoutFileChunk
 		Opts        *sbcsv.Opts
+		Report      *uploadReport
+		Rejected    *rejectedRows
+		Header      []string
+		Journal     *journalChunk
+		Creator     dal.CreateFunc[types.Workout]
 		*types.BarPathCalcHyperparams
 		*types.BarPathTrackerHyperparams
 	}
 
+	workoutJSONLLoader struct {
+		B           *sbjobqueue.Batch
+		S           *types.State
+		Tx          pgx.Tx
+		UID         uint64
+		ClientEmail string
+		File        string
+		FileChunk   *jsonlFileChunk
+		Report      *uploadReport
+		Rejected    *rejectedRows
+		Journal     *journalChunk
+		Creator     dal.CreateFunc[types.Workout]
+	}
+
+	// Files with a .jsonl extension are loaded as JSON Lines files where each
+	// line is a single [types.Workout], all other files are loaded as csv
+	// files. The physics data of JSON Lines workouts is checked to be
+	// consistent and then uploaded as is, so the hyperparams are only used for
+	// csv files.
 	CSVWorkoutLoaderOpts struct {
 		*sbcsv.Opts
 		*types.BarPathCalcHyperparams
 		*types.BarPathTrackerHyperparams
 		Files iter.Seq2[string, error]
 		Batch *sbjobqueue.Batch
+		// Controls if workouts that already exist are an error. With
+		// [types.EnsureExists] workouts that already exist are skipped.
+		CreateType types.CreateFuncType
+		// When not nil problems are added to the report rather than returned
+		// and every workout is written in a savepoint. Used for dry runs.
+		Report *uploadReport
+		// When not nil workouts that cannot be uploaded are rejected rather
+		// than stopping the upload. Each workout is written in its own
+		// savepoint. Used by the [types.SkipRejectedRows] error policy.
+		// Ignored when Report is not nil.
+		Rejected *rejectedRows
+		// When not nil each file chunk is committed in its own transaction
+		// and chunks that the import journal records as completed are
+		// skipped. Ignored when Report is not nil.
+		Journal *importJournal
 	}
 )
 
@@ -132,9 +173,22 @@ func UploadWorkoutsFromCSV(
 		opts.Batch, _ = sbjobqueue.BatchWithContext(ctxt)
 	}
 
+	creator := resolveCreateFunc(
+		opts.CreateType, createWorkouts, ensureWorkoutsExist,
+	)
+
+	chunkOpts := state.WorkoutCSVFileChunks
+	if opts.Report != nil || opts.Rejected != nil {
+		chunkOpts = singleChunkOpts(chunkOpts)
+	}
+
 	for file, err := range opts.Files {
 		if err != nil {
-			return err
+			if opts.Report == nil {
+				return err
+			}
+			opts.Report.add(types.BulkUploadProblem{File: file, Err: err})
+			continue
 		}
 		select {
 		case <-ctxt.Done():
@@ -147,38 +201,122 @@ func UploadWorkoutsFromCSV(
 			formatJobLogLine("UploadWorkoutsFromCSV", 0, "Processing data file"),
 			"File", file,
 		)
+		sendProgress(state, types.ProgressEvent{
+			Kind: types.FileDiscovered, File: file,
+		})
 
 		clientEmail := strings.TrimSuffix(path.Base(file), path.Ext(file))
 		if _, err := mail.ParseAddress(clientEmail); err != nil {
-			return sberr.AppendError(
+			err = sberr.AppendError(
 				types.CSVLoaderJobQueueErr,
 				sberr.Wrap(
 					err,
-					"The name of each workout file must follow the format: <client email>.csv\nGot: %s",
+					"The name of each workout file must follow the format: <client email>.<csv|jsonl>\nGot: %s",
 					clientEmail,
 				),
 			)
+			if opts.Report == nil {
+				return err
+			}
+			opts.Report.add(types.BulkUploadProblem{File: file, Err: err})
+			continue
 		}
 
-		fileChunks, err := sbcsv.ChunkFile(
-			file, NewWorkoutFileChunk, state.WorkoutCSVFileChunks,
-		)
+		if opts.Rejected != nil {
+			if err := opts.Rejected.reset(file); err != nil {
+				return err
+			}
+		}
+		var journaled *journaledFile
+		if opts.Journal != nil && opts.Report == nil {
+			if journaled, err = opts.Journal.open(ctxt, state, tx, file); err != nil {
+				return err
+			}
+		}
+
+		if path.Ext(file) == jsonlFileExt {
+			fileChunks, err := chunkJSONLFile(file, chunkOpts)
+			if err != nil {
+				if opts.Report == nil {
+					return err
+				}
+				opts.Report.add(types.BulkUploadProblem{File: file, Err: err})
+				continue
+			}
+			for i, chunk := range fileChunks {
+				chunkJournal, skip, err := journaled.chunk(i, chunk.Data)
+				if err != nil {
+					return err
+				} else if skip {
+					continue
+				}
+				uid := UID_CNTR.Add(1)
+				sendProgress(state, types.ProgressEvent{
+					Kind: types.ChunkScheduled, File: file, Job: uid,
+				})
+				state.CSVLoaderJobQueue.Schedule(&workoutJSONLLoader{
+					S:           state,
+					Tx:          tx,
+					B:           opts.Batch,
+					UID:         uid,
+					ClientEmail: clientEmail,
+					File:        file,
+					FileChunk:   chunk,
+					Report:      opts.Report,
+					Rejected:    opts.Rejected,
+					Journal:     chunkJournal,
+					Creator:     creator,
+				})
+			}
+			continue
+		}
+
+		var header []string
+		if opts.Rejected != nil {
+			if header, err = readCSVHeader(file); err != nil {
+				return err
+			}
+		}
+
+		fileChunks, err := sbcsv.ChunkFile(file, NewWorkoutFileChunk, chunkOpts)
 		if err != nil {
-			return err
+			if opts.Report == nil {
+				return err
+			}
+			opts.Report.add(types.BulkUploadProblem{File: file, Err: err})
+			continue
 		}
-		for _, chunk := range fileChunks {
+		for i, chunk := range fileChunks {
 			if chunk.EndIdx-chunk.StartIdx <= 0 {
 				continue
 			}
+			chunkJournal, skip, err := journaled.chunk(
+				i, chunk.FullFile[chunk.StartIdx:min(chunk.EndIdx, len(chunk.FullFile))],
+			)
+			if err != nil {
+				return err
+			} else if skip {
+				continue
+			}
+			uid := UID_CNTR.Add(1)
+			sendProgress(state, types.ProgressEvent{
+				Kind: types.ChunkScheduled, File: file, Job: uid,
+			})
 			state.CSVLoaderJobQueue.Schedule(&workoutCSVLoader{
 				S:                         state,
 				Tx:                        tx,
 				B:                         opts.Batch,
-				UID:                       UID_CNTR.Add(1),
+				UID:                       uid,
 				ClientEmail:               clientEmail,
+				File:                      file,
 				FileDir:                   path.Dir(file),
 				FileChunk:                 chunk,
 				Opts:                      opts.Opts,
+				Report:                    opts.Report,
+				Rejected:                  opts.Rejected,
+				Header:                    header,
+				Journal:                   chunkJournal,
+				Creator:                   creator,
 				BarPathCalcHyperparams:    opts.BarPathCalcHyperparams,
 				BarPathTrackerHyperparams: opts.BarPathTrackerHyperparams,
 			})
@@ -202,12 +340,28 @@ func (w *workoutCSVLoader) formatLogLine(msg string) string {
 }
 
 func (w *workoutCSVLoader) Run(ctxt context.Context) (opErr error) {
+	defer func() {
+		sendProgress(w.S, types.ProgressEvent{
+			Kind: types.ChunkCompleted, File: w.File, Job: w.UID, Err: opErr,
+		})
+	}()
+	if w.Journal != nil {
+		return w.Journal.run(ctxt, w.S, &w.Tx, w.run)
+	}
+	return w.run(ctxt)
+}
+
+func (w *workoutCSVLoader) run(ctxt context.Context) (opErr error) {
 	w.S.Log.Log(ctxt, sblog.VLevel(3), w.formatLogLine("Starting..."))
 
 	firstWorkoutIdSet, lastWorkoutIdSet := w.FileChunk.StartIdx == 0, false
 	prevWorkoutId, lastWorkoutId := types.WorkoutId{}, types.WorkoutId{}
 
-	params := []types.Workout{}
+	params, workoutRows := []types.Workout{}, []int{}
+	// Only used when rejecting rows, holds the raw rows and the first error
+	// of each workout in params.
+	rawRows, workoutErrs := [][][]string{}, []error{}
+	rowNum := 0
 	if opErr = sbcsv.LoadReader(w.FileChunk, &sbcsv.LoadOpts{
 		Opts:          *w.Opts,
 		RequestedCols: sbcsv.ReqColsForStruct[rawWorkoutData](),
@@ -217,9 +371,15 @@ func (w *workoutCSVLoader) Run(ctxt context.Context) (opErr error) {
 			row []string,
 			reqCols []sbcsv.RequestedCols,
 		) error {
+			rowNum++
 			rawData, err := sbcsv.RowToStruct[rawWorkoutData](o, row, reqCols)
+			if err != nil && w.Report == nil && w.Rejected != nil {
+				return w.rejectUnparsedRow(row, rawRows, workoutErrs, err)
+			}
 			if err != nil {
-				return err
+				return w.rowErr(
+					rowNum, csvErrColumn(row, reqCols, err), row, err,
+				)
 			}
 
 			iterId := types.WorkoutId{
@@ -244,11 +404,21 @@ func (w *workoutCSVLoader) Run(ctxt context.Context) (opErr error) {
 			if iterId != prevWorkoutId {
 				w.FileChunk.Started = true
 				params = append(params, types.Workout{WorkoutId: iterId})
+				workoutRows = append(workoutRows, csvRowLine(rowNum))
+				rawRows, workoutErrs = append(rawRows, nil), append(workoutErrs, nil)
 				prevWorkoutId = iterId
 			}
 			if !w.FileChunk.Started {
 				return nil
 			}
+			if w.Rejected != nil {
+				rawRows[len(rawRows)-1] = append(
+					rawRows[len(rawRows)-1], slices.Clone(row),
+				)
+				if workoutErrs[len(workoutErrs)-1] != nil {
+					return nil
+				}
+			}
 
 			var variants []types.BarPathVariant
 			if rawData.DataDir != "" {
@@ -257,7 +427,7 @@ func (w *workoutCSVLoader) Run(ctxt context.Context) (opErr error) {
 					int(math.Ceil(rawData.Sets)),
 				)
 				if err != nil {
-					return err
+					return w.workoutErr(rowNum, workoutErrs, err)
 				}
 			}
 
@@ -280,7 +450,7 @@ func (w *workoutCSVLoader) Run(ctxt context.Context) (opErr error) {
 					RawData:              variants,
 					ExerciseData:         &iterExerciseData,
 				}); err != nil {
-					return err
+					return w.workoutErr(rowNum, workoutErrs, err)
 				}
 			}
 
@@ -291,17 +461,293 @@ func (w *workoutCSVLoader) Run(ctxt context.Context) (opErr error) {
 
 			return nil
 		},
+	}); opErr != nil && w.Report == nil {
+		goto errReturn
+	}
+
+	if w.Report != nil {
+		if opErr != nil {
+			w.Report.add(types.BulkUploadProblem{File: w.File, Err: opErr})
+		}
+		withTxLock(w.B, func() error {
+			dryRunWrite(
+				ctxt, w.Tx, w.Report, w.File, workoutRows, params,
+				func(tx pgx.Tx, vals []types.Workout) error {
+					return w.Creator(ctxt, w.S, tx, vals)
+				},
+			)
+			return nil
+		})
+		return nil
+	}
+
+	if w.Rejected != nil {
+		accepted := 0
+		for i := range params {
+			err := workoutErrs[i]
+			if err == nil {
+				err = withTxLock(w.B, func() error {
+					return writeInSavepoint(
+						ctxt, w.Tx, params[i:i+1],
+						func(tx pgx.Tx, vals []types.Workout) error {
+							return w.Creator(ctxt, w.S, tx, vals)
+						},
+					)
+				})
+			}
+			if err == nil {
+				w.Rejected.accept(len(rawRows[i]))
+				accepted++
+				continue
+			}
+			for _, row := range rawRows[i] {
+				if opErr = w.Rejected.reject(w.File, w.Header, row, err); opErr != nil {
+					goto errReturn
+				}
+			}
+		}
+		sendProgress(w.S, types.ProgressEvent{
+			Kind: types.RowsUploaded, File: w.File, Job: w.UID, NumRows: accepted,
+		})
+		w.S.Log.Log(
+			ctxt, sblog.VLevel(3),
+			w.formatLogLine("Finished loading workout data"),
+			"NumRows", len(params),
+		)
+		return
+	}
+
+	if opErr = withTxLock(w.B, func() error {
+		return w.Creator(ctxt, w.S, w.Tx, params)
 	}); opErr != nil {
 		goto errReturn
 	}
 
-	// This is unfortunate... but it has to be done because a single transaction
-	// is backed by a single conn which is not thread safe.
-	w.B.Lock()
-	if opErr = dal.CreateWorkouts(ctxt, w.S, w.Tx, params); opErr != nil {
+	sendProgress(w.S, types.ProgressEvent{
+		Kind: types.RowsUploaded, File: w.File, Job: w.UID, NumRows: len(params),
+	})
+
+	w.S.Log.Log(
+		ctxt, sblog.VLevel(3),
+		w.formatLogLine("Finished loading workout data"),
+		"NumRows", len(params),
+	)
+	return
+
+errReturn:
+	w.S.Log.Error(w.formatLogLine("Encountered error"), "Error", opErr)
+	return sberr.AppendError(types.CSVLoaderJobQueueErr, opErr)
+}
+
+// The physics data of JSON Lines workouts is uploaded as is rather than being
+// calculated, so it is checked to be data that could have been calculated.
+func validateJSONLPhysData(w *types.Workout) error {
+	for i := range w.Exercises {
+		e := &w.Exercises[i]
+		for set, p := range e.PhysData {
+			if !p.Present {
+				continue
+			}
+			if err := barpathphysdata.Validate(
+				&p.Value, expNumReps(e, set),
+			); err != nil {
+				return sberr.AppendError(
+					sberr.Wrap(
+						types.MalformedJSONLErr,
+						"Exercise %d (%s), set %d", i, e.Name, set+1,
+					),
+					err,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the supplied error unless the loader is part of a dry run, in which
+// case the error is added to the report and the row is skipped, or the loader
+// is rejecting rows, in which case the row is rejected.
+func (w *workoutCSVLoader) rowErr(
+	rowNum int,
+	col string,
+	row []string,
+	err error,
+) error {
+	switch {
+	case w.Report != nil:
+		w.Report.add(types.BulkUploadProblem{
+			File: w.File, Row: csvRowLine(rowNum), Column: col, Err: err,
+		})
+		return nil
+	case w.Rejected != nil:
+		return w.Rejected.reject(w.File, w.Header, row, err)
+	default:
+		return err
+	}
+}
+
+// Rejects a row that could not be parsed. The workout of the row is not known,
+// so the row is part of the workout whose rows precede it in the file and the
+// whole workout is rejected. When no workout precedes the row in the chunk the
+// row is either rejected alone, when the chunk starts the file, or left to the
+// previous chunk, which reads past its end until the workout changes.
+func (w *workoutCSVLoader) rejectUnparsedRow(
+	row []string,
+	rawRows [][][]string,
+	workoutErrs []error,
+	err error,
+) error {
+	switch {
+	case w.FileChunk.Started:
+		rawRows[len(rawRows)-1] = append(
+			rawRows[len(rawRows)-1], slices.Clone(row),
+		)
+		if workoutErrs[len(workoutErrs)-1] == nil {
+			workoutErrs[len(workoutErrs)-1] = err
+		}
+		return nil
+	case w.FileChunk.StartIdx == 0:
+		return w.Rejected.reject(w.File, w.Header, row, err)
+	default:
+		return nil
+	}
+}
+
+// Handles an error in the data dir of a row. When the loader is rejecting rows
+// the error is recorded against the current workout so that every row of the
+// workout is rejected, otherwise it is handled the same as [rowErr].
+func (w *workoutCSVLoader) workoutErr(
+	rowNum int,
+	workoutErrs []error,
+	err error,
+) error {
+	if w.Report == nil && w.Rejected != nil {
+		workoutErrs[len(workoutErrs)-1] = err
+		return nil
+	}
+	return w.rowErr(rowNum, "DataDir", nil, err)
+}
+
+func (w *workoutJSONLLoader) JobType(_ types.CSVLoaderJob) {}
+
+func (w *workoutJSONLLoader) Batch() *sbjobqueue.Batch {
+	return w.B
+}
+
+func (w *workoutJSONLLoader) formatLogLine(msg string) string {
+	return formatJobLogLine("workoutJSONLLoader", w.UID, msg)
+}
+
+func (w *workoutJSONLLoader) Run(ctxt context.Context) (opErr error) {
+	defer func() {
+		sendProgress(w.S, types.ProgressEvent{
+			Kind: types.ChunkCompleted, File: w.File, Job: w.UID, Err: opErr,
+		})
+	}()
+	if w.Journal != nil {
+		return w.Journal.run(ctxt, w.S, &w.Tx, w.run)
+	}
+	return w.run(ctxt)
+}
+
+func (w *workoutJSONLLoader) run(ctxt context.Context) (opErr error) {
+	w.S.Log.Log(ctxt, sblog.VLevel(3), w.formatLogLine("Starting..."))
+
+	params, rows := []types.Workout{}, []int{}
+	lines := [][]string{}
+	var onErr func(lineNum int, line []byte, err error) error
+	switch {
+	case w.Report != nil:
+		onErr = func(lineNum int, _ []byte, err error) error {
+			w.Report.add(types.BulkUploadProblem{
+				File:   w.File,
+				Row:    lineNum,
+				Column: jsonlErrColumn(err),
+				Err:    err,
+			})
+			return nil
+		}
+	case w.Rejected != nil:
+		onErr = func(lineNum int, line []byte, err error) error {
+			return w.Rejected.reject(
+				w.File, jsonlRejectedHeader,
+				[]string{strconv.Itoa(lineNum), string(line)}, err,
+			)
+		}
+	}
+	if opErr = loadJSONL(
+		w.FileChunk,
+		func(lineNum int, line []byte, v *types.Workout) error {
+			if v.ClientEmail == "" {
+				v.ClientEmail = w.ClientEmail
+			} else if v.ClientEmail != w.ClientEmail {
+				return sberr.Wrap(
+					types.MalformedJSONLErr,
+					"Workout client email (%s) does not match the file name (%s)",
+					v.ClientEmail, w.ClientEmail,
+				)
+			}
+			if err := validateJSONLPhysData(v); err != nil {
+				return err
+			}
+			params, rows = append(params, *v), append(rows, lineNum)
+			if w.Rejected != nil {
+				lines = append(
+					lines, []string{strconv.Itoa(lineNum), string(line)},
+				)
+			}
+			return nil
+		},
+		onErr,
+	); opErr != nil {
+		opErr = sberr.Wrap(opErr, "File: %s", w.File)
+		goto errReturn
+	}
+
+	if w.Report != nil {
+		withTxLock(w.B, func() error {
+			dryRunWrite(
+				ctxt, w.Tx, w.Report, w.File, rows, params,
+				func(tx pgx.Tx, vals []types.Workout) error {
+					return w.Creator(ctxt, w.S, tx, vals)
+				},
+			)
+			return nil
+		})
+		return nil
+	}
+
+	if w.Rejected != nil {
+		var accepted int
+		if accepted, opErr = rejectFailedWrites(
+			ctxt, w.B, w.Tx, w.Rejected, w.File, jsonlRejectedHeader,
+			lines, params,
+			func(tx pgx.Tx, vals []types.Workout) error {
+				return w.Creator(ctxt, w.S, tx, vals)
+			},
+		); opErr != nil {
+			goto errReturn
+		}
+		sendProgress(w.S, types.ProgressEvent{
+			Kind: types.RowsUploaded, File: w.File, Job: w.UID, NumRows: accepted,
+		})
+		w.S.Log.Log(
+			ctxt, sblog.VLevel(3),
+			w.formatLogLine("Finished loading workout data"),
+			"NumRows", len(params),
+		)
+		return
+	}
+
+	if opErr = withTxLock(w.B, func() error {
+		return w.Creator(ctxt, w.S, w.Tx, params)
+	}); opErr != nil {
 		goto errReturn
 	}
-	w.B.Unlock()
+
+	sendProgress(w.S, types.ProgressEvent{
+		Kind: types.RowsUploaded, File: w.File, Job: w.UID, NumRows: len(params),
+	})
 
 	w.S.Log.Log(
 		ctxt, sblog.VLevel(3),
@@ -359,7 +805,7 @@ func (w *workoutCSVLoader) parseWorkoutDataDir(
 		if err != nil {
 			return sberr.AppendError(types.InvalidDataDirErr, err)
 		}
-		if setNum <= 0 || setNum > numSets+1 {
+		if setNum <= 0 || setNum > numSets {
 			return sberr.Wrap(
 				types.InvalidDataDirErr,
 				"Set num (%d) out of allowed range [1, %d]",
@@ -412,48 +858,10 @@ func (w *workoutCSVLoader) parseWorkoutDataDir(
 func (w *workoutCSVLoader) loadTimeSeriesCSVData(
 	path string,
 ) (types.BarPathVariant, error) {
-	reqCols := []sbcsv.RequestedCols{
-		{Name: "Time"}, {Name: "XPos"}, {Name: "YPos"},
-	}
-
-	rawTimeSeriesData := types.RawTimeSeriesData{}
-	if err := sbcsv.LoadFile(path, &sbcsv.LoadOpts{
-		Opts:          *w.Opts,
-		RequestedCols: reqCols,
-		Op: func(
-			o *sbcsv.Opts,
-			rowIdx int,
-			row []string,
-			reqCols []sbcsv.RequestedCols,
-		) error {
-			time, err := strconv.ParseFloat(row[reqCols[0].Idx], 64)
-			if err != nil {
-				return err
-			}
-			xpos, err := strconv.ParseFloat(row[reqCols[1].Idx], 64)
-			if err != nil {
-				return err
-			}
-			ypos, err := strconv.ParseFloat(row[reqCols[2].Idx], 64)
-			if err != nil {
-				return err
-			}
-
-			rawTimeSeriesData.TimeData = append(
-				rawTimeSeriesData.TimeData, types.Second(time),
-			)
-			rawTimeSeriesData.PositionData = append(
-				rawTimeSeriesData.PositionData,
-				types.Vec2[types.Meter, types.Meter]{
-					X: types.Meter(xpos), Y: types.Meter(ypos),
-				},
-			)
-			return nil
-		},
-	}); err != nil {
+	rawTimeSeriesData, err := LoadTimeSeriesCSVFile(w.Opts, path)
+	if err != nil {
 		return types.BarPathVariant{}, err
 	}
-
 	return types.BarPathVariant{
 		Flag:       types.TimeSeriesBarPathData,
 		TimeSeries: rawTimeSeriesData,
@@ -0,0 +1,402 @@
+package jobs
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"io/fs"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	// Watches a dir tree by polling it, uploading each workout file once it
+	// and its data dirs have stopped changing.
+	workoutWatcher struct {
+		state *types.State
+		opts  *types.WatchWorkoutDirOpts
+		files map[string]watchedFile
+	}
+
+	// The state of a single file the last time the dir tree was polled.
+	watchedFile struct {
+		size    int64
+		modTime time.Time
+		since   time.Time // When the file was first seen with its size and mod time
+	}
+
+	// A workout file that a poll tried to upload.
+	watchedUpload struct {
+		file     string
+		dataDirs []string
+		err      error // The error the upload failed with, nil if it succeeded
+	}
+
+	// The [types.WatchClock] used when the options do not supply one.
+	systemClock struct{}
+)
+
+const (
+	// Each poll that moves files puts them in a sub-dir of the done and
+	// failed dirs named with this layout so that files with the same name
+	// never collide.
+	watchBatchDirFormat = "20060102T150405.000000000Z"
+)
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Ticker(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+// Watches the dir of the supplied options, uploading every workout file that
+// is added to the dir tree with [UploadWorkoutsFromCSV]. Each workout file is
+// uploaded in its own transaction and is then moved to the done or failed
+// sub-dir of the watched dir. The data dirs a workout file references are
+// moved along with the last workout file that references them. Returns once
+// the context is cancelled.
+func WatchWorkoutDir(
+	ctxt context.Context,
+	state *types.State,
+	opts *types.WatchWorkoutDirOpts,
+) error {
+	if opts.PollInterval <= 0 {
+		return sberr.Wrap(
+			types.CouldNotWatchWorkoutDirErr,
+			"PollInterval must be >0, got %s", opts.PollInterval,
+		)
+	}
+	if opts.StableFor <= 0 {
+		return sberr.Wrap(
+			types.CouldNotWatchWorkoutDirErr,
+			"StableFor must be >0, got %s", opts.StableFor,
+		)
+	}
+	if opts.BarPathCalcHyperparams == nil || opts.BarPathTrackerHyperparams == nil {
+		return sberr.Wrap(
+			types.CouldNotWatchWorkoutDirErr,
+			"BarPathCalcHyperparams and BarPathTrackerHyperparams must not be nil",
+		)
+	}
+	if fi, err := os.Stat(opts.Dir); err != nil {
+		return sberr.AppendError(types.CouldNotWatchWorkoutDirErr, err)
+	} else if !fi.IsDir() {
+		return sberr.Wrap(
+			types.CouldNotWatchWorkoutDirErr,
+			"'%s' was not a dir but must be", opts.Dir,
+		)
+	}
+
+	var clock types.WatchClock = systemClock{}
+	if opts.Clock != nil {
+		clock = opts.Clock
+	}
+	w := &workoutWatcher{
+		state: state, opts: opts, files: map[string]watchedFile{},
+	}
+	ticks, stop := clock.Ticker(opts.PollInterval)
+	defer stop()
+
+	state.Log.Info("Watching workout dir", "Dir", opts.Dir)
+	for now := clock.Now(); ; {
+		// A poll can fail because of a file that is being written, so the
+		// error is logged and the dir is polled again rather than stopping.
+		if err := w.poll(ctxt, now); err != nil {
+			state.Log.Error(
+				"Could not poll workout dir", "Dir", opts.Dir, "Error", err,
+			)
+		}
+		select {
+		case <-ctxt.Done():
+			return nil
+		case now = <-ticks:
+		}
+	}
+}
+
+// Uploads every stable workout file and then moves them. Nothing is moved
+// until every upload has finished so that a data dir is only moved once no
+// workout file that is left in the dir tree references it.
+func (w *workoutWatcher) poll(ctxt context.Context, now time.Time) error {
+	workoutFiles, err := w.scan(now)
+	if err != nil {
+		return err
+	}
+
+	uploaded, remainingDirs := []watchedUpload{}, []string{}
+	for _, file := range workoutFiles {
+		// The workout file must be stable before it is uploaded so that a
+		// partially written file is not treated as a malformed file. The data
+		// dirs of a file that is not uploaded are still kept, as far as the
+		// file could be read, so they are not moved out from under it.
+		dataDirs, uploadErr := workoutDataDirs(file)
+		if ctxt.Err() != nil || !w.stable(now, file, nil) ||
+			(uploadErr == nil && !w.stable(now, file, dataDirs)) {
+			remainingDirs = append(remainingDirs, dataDirs...)
+			continue
+		}
+		if uploadErr == nil {
+			uploadErr = w.upload(ctxt, file)
+		}
+		if ctxt.Err() != nil {
+			// The upload was cancelled, it is retried the next time the dir
+			// is watched.
+			remainingDirs = append(remainingDirs, dataDirs...)
+			continue
+		}
+		uploaded = append(uploaded, watchedUpload{
+			file: file, dataDirs: dataDirs, err: uploadErr,
+		})
+	}
+
+	batchDir := now.UTC().Format(watchBatchDirFormat)
+	for _, u := range uploaded {
+		w.moveWorkoutFile(batchDir, u)
+	}
+	w.moveDataDirs(batchDir, workoutFiles, uploaded, remainingDirs)
+	return nil
+}
+
+// Walks the watched dir tree, updating the state of every file, and returns
+// all the workout files in it.
+func (w *workoutWatcher) scan(now time.Time) ([]string, error) {
+	files, workoutFiles := map[string]watchedFile{}, []string{}
+	err := filepath.WalkDir(
+		w.opts.Dir,
+		func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if p == filepath.Join(w.opts.Dir, types.WatchDoneDir) ||
+					p == filepath.Join(w.opts.Dir, types.WatchFailedDir) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			f := watchedFile{size: info.Size(), modTime: info.ModTime(), since: now}
+			if prev, ok := w.files[p]; ok &&
+				prev.size == f.size && prev.modTime.Equal(f.modTime) {
+				f.since = prev.since
+			}
+			files[p] = f
+			if isWorkoutFile(p) {
+				workoutFiles = append(workoutFiles, p)
+			}
+			return nil
+		},
+	)
+	w.files = files
+	return workoutFiles, err
+}
+
+// Returns true if the supplied workout file, and every file in the supplied
+// data dirs, has not changed for at least the StableFor duration.
+func (w *workoutWatcher) stable(
+	now time.Time,
+	file string,
+	dataDirs []string,
+) bool {
+	for p, f := range w.files {
+		inDataDir := slices.ContainsFunc(dataDirs, func(dir string) bool {
+			return isInDir(dir, p)
+		})
+		if (p == file || inDataDir) && now.Sub(f.since) < w.opts.StableFor {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *workoutWatcher) upload(ctxt context.Context, file string) error {
+	return pgx.BeginTxFunc(
+		ctxt, w.state.DB, pgx.TxOptions{},
+		func(tx pgx.Tx) error {
+			return UploadWorkoutsFromCSV(ctxt, w.state, tx, &CSVWorkoutLoaderOpts{
+				Opts:                      &w.opts.Opts,
+				BarPathCalcHyperparams:    w.opts.BarPathCalcHyperparams,
+				BarPathTrackerHyperparams: w.opts.BarPathTrackerHyperparams,
+				Files: func(yield func(string, error) bool) {
+					yield(file, nil)
+				},
+			})
+		},
+	)
+}
+
+// Returns the dir of the supplied batch that files are moved to, the done dir
+// or the failed dir if failed is true.
+func (w *workoutWatcher) batchRoot(batchDir string, failed bool) string {
+	if failed {
+		return filepath.Join(w.opts.Dir, types.WatchFailedDir, batchDir)
+	}
+	return filepath.Join(w.opts.Dir, types.WatchDoneDir, batchDir)
+}
+
+// Moves the supplied workout file to the done dir, or the failed dir if the
+// upload failed. When the upload failed an error report is written next to
+// the workout file. Errors are logged rather than returned so that one file
+// that cannot be moved does not stop the others from being moved.
+func (w *workoutWatcher) moveWorkoutFile(batchDir string, u watchedUpload) {
+	dst, err := w.moveInto(w.batchRoot(batchDir, u.err != nil), u.file)
+	if err != nil {
+		w.state.Log.Error(
+			"Could not move workout file", "File", u.file, "Error", err,
+		)
+		return
+	}
+
+	if u.err == nil {
+		w.state.Log.Info("Uploaded workout file", "File", u.file, "MovedTo", dst)
+		return
+	}
+	w.state.Log.Error(
+		"Could not upload workout file",
+		"File", u.file, "MovedTo", dst, "Error", u.err,
+	)
+	report := strings.TrimSuffix(dst, filepath.Ext(dst)) + types.WatchErrReportExt
+	if err := os.WriteFile(
+		report, []byte(u.err.Error()+"\n"), 0644,
+	); err != nil {
+		w.state.Log.Error(
+			"Could not write workout file error report",
+			"File", report, "Error", err,
+		)
+	}
+}
+
+// Moves the data dirs of the supplied uploads that are not in remainingDirs.
+// A data dir is moved to the failed dir if any of the uploads that reference
+// it failed so that it is kept with the workout file that needs it, otherwise
+// it is moved to the done dir. Data dirs outside of the watched dir, data dirs
+// that hold a workout file, and data dirs that do not exist are not moved.
+// Errors are logged rather than returned.
+func (w *workoutWatcher) moveDataDirs(
+	batchDir string,
+	workoutFiles []string,
+	uploaded []watchedUpload,
+	remainingDirs []string,
+) {
+	dirs, failed := []string{}, map[string]bool{}
+	for _, u := range uploaded {
+		for _, dir := range u.dataDirs {
+			if slices.Contains(remainingDirs, dir) {
+				continue
+			}
+			if !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+			failed[dir] = failed[dir] || u.err != nil
+		}
+	}
+
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(w.opts.Dir, dir)
+		if err != nil || rel == "." || !filepath.IsLocal(rel) ||
+			slices.ContainsFunc(workoutFiles, func(file string) bool {
+				return isInDir(dir, file)
+			}) {
+			continue
+		}
+		// A data dir that does not exist fails the upload of the workout
+		// files that reference it, there is nothing to move.
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if _, err := w.moveInto(w.batchRoot(batchDir, failed[dir]), dir); err != nil {
+			w.state.Log.Error(
+				"Could not move workout data dir", "Dir", dir, "Error", err,
+			)
+		}
+	}
+}
+
+// Moves the supplied path into the supplied root dir, keeping its path
+// relative to the watched dir. Returns the path it was moved to.
+func (w *workoutWatcher) moveInto(root string, p string) (string, error) {
+	rel, err := filepath.Rel(w.opts.Dir, p)
+	if err != nil {
+		return "", err
+	}
+	dst := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	return dst, os.Rename(p, dst)
+}
+
+// Returns true if the supplied file is a workout file: a csv or JSON Lines
+// file named with a client email.
+func isWorkoutFile(file string) bool {
+	ext := filepath.Ext(file)
+	if (ext != csvFileExt && ext != jsonlFileExt) || isRejectedFile(file) {
+		return false
+	}
+	_, err := mail.ParseAddress(
+		strings.TrimSuffix(filepath.Base(file), ext),
+	)
+	return err == nil
+}
+
+// Returns the data dirs referenced by the DataDir column of the supplied
+// workout file, relative to the dir of the workout file. JSON Lines workout
+// files do not reference data dirs. If the file cannot be read the data dirs
+// that were found before the error are returned along with the error.
+func workoutDataDirs(file string) ([]string, error) {
+	if filepath.Ext(file) == jsonlFileExt {
+		return nil, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := slices.Index(header, "DataDir")
+	if col < 0 {
+		return nil, nil
+	}
+
+	res := []string{}
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		} else if err != nil {
+			return res, err
+		}
+		if col >= len(row) || row[col] == "" {
+			continue
+		}
+		dir := filepath.Join(filepath.Dir(file), row[col])
+		if !slices.Contains(res, dir) {
+			res = append(res, dir)
+		}
+	}
+}
+
+// Returns true if the supplied path is in the supplied dir tree.
+func isInDir(dir string, p string) bool {
+	rel, err := filepath.Rel(dir, p)
+	return err == nil && filepath.IsLocal(rel)
+}
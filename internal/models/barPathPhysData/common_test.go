@@ -296,3 +296,90 @@ func TestRepsExtendToStartAndEnd(t *testing.T) {
 		{StartIdx: 5, EndIdx: 13},
 	})
 }
+
+func TestRepVbtStats(t *testing.T) {
+	// Two squat reps that each descend 0.5m over 1s and then ascend over 1s,
+	// sampled at 100Hz.
+	rawData := types.PhysicsData{
+		Time:     make([]types.Second, 400),
+		Position: make([]types.Vec2[types.Meter, types.Meter], 400),
+	}
+	for i := range rawData.Time {
+		rawData.Time[i] = types.Second(float64(i) * 0.01)
+		rawData.Position[i].Y = types.Meter(
+			-0.25 * (1 - math.Cos(math.Pi*float64(rawData.Time[i]))),
+		)
+	}
+	params := types.BarPathCalcHyperparams{
+		MinNumSamples:   10,
+		TimeDeltaEps:    1e-6,
+		ApproxErr:       types.SecondOrder,
+		NoiseFilter:     3,
+		NearZeroFilter:  0.1,
+		SmootherWeight3: 1,
+	}
+	err := Calc(&rawData, &params, 100, 2)
+	sbtest.Nil(t, err)
+
+	for i := range 2 {
+		// The mean of 0.25*pi*sin(pi*t) over the concentric half of the rep
+		sbtest.EqFloat(t, 0.5, float64(rawData.MeanConcentricVel[i]), 1e-2)
+		// The deceleration never exceeds gravity so the whole concentric
+		// phase is propulsive
+		sbtest.EqFloat(
+			t, float64(rawData.MeanConcentricVel[i]),
+			float64(rawData.MeanPropulsiveVel[i]), 1e-9,
+		)
+		sbtest.EqFloat(t, 0.5, float64(rawData.TimeToPeakVel[i]), 2e-2)
+		sbtest.EqFloat(t, 1, float64(rawData.ConcentricDur[i]), 2e-2)
+		sbtest.EqFloat(t, 1, float64(rawData.EccentricDur[i]), 2e-2)
+		sbtest.EqFloat(t, 0.5, float64(rawData.RangeOfMotion[i]), 1e-6)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	calcRawData := func() types.PhysicsData {
+		rawData := getBasicRawData()
+		params := types.BarPathCalcHyperparams{
+			ApproxErr:     types.FourthOrder,
+			MinNumSamples: 5,
+			TimeDeltaEps:  1e-6,
+		}
+		sbtest.Nil(t, Calc(&rawData, &params, 1, 1))
+		return rawData
+	}
+
+	rawData := calcRawData()
+	sbtest.Nil(t, Validate(&rawData, 1))
+	sbtest.ContainsError(
+		t, types.InvalidRawDataLenErr, Validate(&rawData, 2),
+		`Expected rep split slice of len 2, got len 1`,
+	)
+
+	rawData.Jerk = rawData.Jerk[:len(rawData.Jerk)-1]
+	sbtest.ContainsError(
+		t, types.InvalidRawDataLenErr, Validate(&rawData, 1),
+		`Expected jerk slice of len 7, got len 6`,
+	)
+
+	rawData = calcRawData()
+	rawData.Time[3] = 1
+	sbtest.ContainsError(
+		t, types.TimeSeriesDecreaseErr, Validate(&rawData, 1),
+		`Time decreased from 2.000000 to 1.000000 at idx 3`,
+	)
+
+	rawData = calcRawData()
+	rawData.RepSplits[0].EndIdx = 8
+	sbtest.ContainsError(
+		t, types.InvalidRepSplitErr, Validate(&rawData, 1),
+		`Rep 0: split \[0, 8\) is not within the 7 samples`,
+	)
+
+	rawData = calcRawData()
+	rawData.Power[2] = types.Watt(math.Inf(1))
+	sbtest.ContainsError(
+		t, types.NonFinitePhysicsDataErr, Validate(&rawData, 1),
+		`power at idx 2`,
+	)
+}
@@ -240,6 +240,64 @@ void setRepAvgVal(
 	*avgVal=valueTransform(tot)/subSlice.Len();
 }
 
+// Standard gravity. The propulsive phase of a rep ends once the bar is
+// decelerating faster than gravity alone would decelerate it.
+constexpr double gravity=9.80665;
+
+void setRepVbtStats(
+	barPathData_t* data,
+	size_t rep,
+	split_t repSplit
+) {
+	double h=data->time[1]-data->time[0];
+	double concentricVel=0, propulsiveVel=0;
+	double minPos=data->pos[repSplit.StartIdx].Y;
+	double maxPos=data->pos[repSplit.StartIdx].Y;
+	size_t numConcentric=0, numPropulsive=0, numEccentric=0;
+	int64_t peakIdx=repSplit.StartIdx;
+
+	for (int64_t j=repSplit.StartIdx; j<repSplit.EndIdx; j++) {
+		minPos=std::min(minPos, data->pos[j].Y);
+		maxPos=std::max(maxPos, data->pos[j].Y);
+		if (data->vel[j].Y>data->vel[peakIdx].Y) {
+			peakIdx=j;
+		}
+
+		if (data->vel[j].Y<0) {
+			numEccentric++;
+		} else if (data->vel[j].Y>0) {
+			numConcentric++;
+			concentricVel+=data->vel[j].Y;
+			if (data->acc[j].Y>=-gravity) {
+				numPropulsive++;
+				propulsiveVel+=data->vel[j].Y;
+			}
+		}
+	}
+
+	// The concentric phase that contains the peak velocity starts at the last
+	// point the bar started moving up before the peak.
+	int64_t concentricStart=peakIdx;
+	while (
+		concentricStart>repSplit.StartIdx &&
+		data->vel[concentricStart-1].Y>0
+	) {
+		concentricStart--;
+	}
+
+	// The samples are evenly spaced so the sample mean is also the time
+	// weighted mean. The load-velocity profile reads this value directly.
+	data->meanConcentricVel[rep]=(numConcentric>0?
+		concentricVel/numConcentric: 0);
+	data->meanPropulsiveVel[rep]=(numPropulsive>0?
+		propulsiveVel/numPropulsive: 0);
+	data->timeToPeakVel[rep]=(data->vel[peakIdx].Y>0?
+		data->time[peakIdx]-data->time[concentricStart]: 0);
+	data->concentricDur[rep]=numConcentric*h;
+	data->eccentricDur[rep]=numEccentric*h;
+	data->rangeOfMotion[rep]=maxPos-minPos;
+}
+
 enum BarPathCalcErrCode_t calcRepStats(
 	barPathData_t* data,
 	barPathCalcHyperparams_t* opts
@@ -294,8 +352,10 @@ enum BarPathCalcErrCode_t calcRepStats(
 			(PointInTime<double>*)&data->maxWork[i]
 		);
 
-		setRepAvgVal(power, repSplit, data->avgPower);
-		setRepAvgVal(work, repSplit, data->avgWork);
+		setRepAvgVal(power, repSplit, &data->avgPower[i]);
+		setRepAvgVal(work, repSplit, &data->avgWork[i]);
+
+		setRepVbtStats(data, i, repSplit);
 	}
 	return NoErr;
 }
@@ -54,6 +54,13 @@ type (
 		avgPower   *types.Watt
 		minPower   *types.PointInTime[types.Second, types.Watt]
 		maxPower   *types.PointInTime[types.Second, types.Watt]
+
+		meanConcentricVel *types.MeterPerSec
+		meanPropulsiveVel *types.MeterPerSec
+		timeToPeakVel     *types.Second
+		concentricDur     *types.Second
+		eccentricDur      *types.Second
+		rangeOfMotion     *types.Meter
 	}
 )
 
@@ -113,6 +120,12 @@ func Calc(
 	rawData.AvgPower = util.SliceClamp(rawData.AvgPower, expNumReps)
 	rawData.MinPower = util.SliceClamp(rawData.MinPower, expNumReps)
 	rawData.MaxPower = util.SliceClamp(rawData.MaxPower, expNumReps)
+	rawData.MeanConcentricVel = util.SliceClamp(rawData.MeanConcentricVel, expNumReps)
+	rawData.MeanPropulsiveVel = util.SliceClamp(rawData.MeanPropulsiveVel, expNumReps)
+	rawData.TimeToPeakVel = util.SliceClamp(rawData.TimeToPeakVel, expNumReps)
+	rawData.ConcentricDur = util.SliceClamp(rawData.ConcentricDur, expNumReps)
+	rawData.EccentricDur = util.SliceClamp(rawData.EccentricDur, expNumReps)
+	rawData.RangeOfMotion = util.SliceClamp(rawData.RangeOfMotion, expNumReps)
 
 	baseData := CData{
 		timeLen:    int64(len(rawData.Time)),
@@ -142,6 +155,13 @@ func Calc(
 		avgPower:   &rawData.AvgPower[0],
 		minPower:   &rawData.MinPower[0],
 		maxPower:   &rawData.MaxPower[0],
+
+		meanConcentricVel: &rawData.MeanConcentricVel[0],
+		meanPropulsiveVel: &rawData.MeanPropulsiveVel[0],
+		timeToPeakVel:     &rawData.TimeToPeakVel[0],
+		concentricDur:     &rawData.ConcentricDur[0],
+		eccentricDur:      &rawData.EccentricDur[0],
+		rangeOfMotion:     &rawData.RangeOfMotion[0],
 	}
 
 	pinner := runtime.Pinner{}
@@ -169,6 +189,12 @@ func Calc(
 	pinner.Pin(baseData.avgPower)
 	pinner.Pin(baseData.minPower)
 	pinner.Pin(baseData.maxPower)
+	pinner.Pin(baseData.meanConcentricVel)
+	pinner.Pin(baseData.meanPropulsiveVel)
+	pinner.Pin(baseData.timeToPeakVel)
+	pinner.Pin(baseData.concentricDur)
+	pinner.Pin(baseData.eccentricDur)
+	pinner.Pin(baseData.rangeOfMotion)
 
 	err := C.CalcBarPathPhysData(
 		(*C.barPathData_t)(unsafe.Pointer(&baseData)),
@@ -138,6 +138,12 @@ func loadAndTestCsv(a *args) {
 		"MinPower",
 		"MaxPowerTime",
 		"MaxPower",
+		"MeanConcentricVel",
+		"MeanPropulsiveVel",
+		"TimeToPeakVel",
+		"ConcentricDur",
+		"EccentricDur",
+		"RangeOfMotion",
 	})
 	for i := range a.numReps {
 		repSeriesWriter.Write([]string{
@@ -173,6 +179,13 @@ func loadAndTestCsv(a *args) {
 			fmt.Sprintf("%f", inputData.MinPower[i].Value),
 			fmt.Sprintf("%f", inputData.MaxPower[i].Time),
 			fmt.Sprintf("%f", inputData.MaxPower[i].Value),
+
+			fmt.Sprintf("%f", inputData.MeanConcentricVel[i]),
+			fmt.Sprintf("%f", inputData.MeanPropulsiveVel[i]),
+			fmt.Sprintf("%f", inputData.TimeToPeakVel[i]),
+			fmt.Sprintf("%f", inputData.ConcentricDur[i]),
+			fmt.Sprintf("%f", inputData.EccentricDur[i]),
+			fmt.Sprintf("%f", inputData.RangeOfMotion[i]),
 		})
 	}
 	repSeriesWriter.Flush()
@@ -0,0 +1,182 @@
+package barpathphysdata
+
+import (
+	"math"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+type (
+	namedLen struct {
+		Name string
+		Len  int
+	}
+)
+
+// Checks that physics data that was not calculated by [Calc], such as physics
+// data that was uploaded as is, could have been. Every per sample slice must
+// have the same length as the time slice, every per rep slice must have a
+// value for each of the expected number of reps, every rep split must be
+// within the time slice, time must not decrease, and every value must be
+// finite.
+//
+// The hyperparams used to calculate the data are not known so the minimum
+// number of samples and the time delta between samples are not checked.
+func Validate(data *types.PhysicsData, expNumReps int32) error {
+	expLen := len(data.Time)
+	if err := checkLens(expLen, []namedLen{
+		{"position", len(data.Position)},
+		{"velocity", len(data.Velocity)},
+		{"acceleration", len(data.Acceleration)},
+		{"jerk", len(data.Jerk)},
+		{"force", len(data.Force)},
+		{"impulse", len(data.Impulse)},
+		{"work", len(data.Work)},
+		{"power", len(data.Power)},
+	}); err != nil {
+		return err
+	}
+	if err := checkLens(int(expNumReps), []namedLen{
+		{"rep split", len(data.RepSplits)},
+		{"min velocity", len(data.MinVel)},
+		{"max velocity", len(data.MaxVel)},
+		{"min acceleration", len(data.MinAcc)},
+		{"max acceleration", len(data.MaxAcc)},
+		{"min force", len(data.MinForce)},
+		{"max force", len(data.MaxForce)},
+		{"min impulse", len(data.MinImpulse)},
+		{"max impulse", len(data.MaxImpulse)},
+		{"avg work", len(data.AvgWork)},
+		{"min work", len(data.MinWork)},
+		{"max work", len(data.MaxWork)},
+		{"avg power", len(data.AvgPower)},
+		{"min power", len(data.MinPower)},
+		{"max power", len(data.MaxPower)},
+		{"mean concentric vel", len(data.MeanConcentricVel)},
+		{"mean propulsive vel", len(data.MeanPropulsiveVel)},
+		{"time to peak vel", len(data.TimeToPeakVel)},
+		{"concentric duration", len(data.ConcentricDur)},
+		{"eccentric duration", len(data.EccentricDur)},
+		{"range of motion", len(data.RangeOfMotion)},
+	}); err != nil {
+		return err
+	}
+
+	for i := 1; i < expLen; i++ {
+		if data.Time[i] < data.Time[i-1] {
+			return sberr.Wrap(
+				types.TimeSeriesDecreaseErr,
+				"Time decreased from %f to %f at idx %d",
+				data.Time[i-1], data.Time[i], i,
+			)
+		}
+	}
+	for i, s := range data.RepSplits {
+		if s.StartIdx < 0 || s.StartIdx > s.EndIdx || s.EndIdx > int64(expLen) {
+			return sberr.Wrap(
+				types.InvalidRepSplitErr,
+				"Rep %d: split [%d, %d) is not within the %d samples",
+				i, s.StartIdx, s.EndIdx, expLen,
+			)
+		}
+	}
+
+	return firstErr(
+		checkFinite("time", data.Time),
+		checkFiniteVec2("position", data.Position),
+		checkFiniteVec2("velocity", data.Velocity),
+		checkFiniteVec2("acceleration", data.Acceleration),
+		checkFiniteVec2("jerk", data.Jerk),
+		checkFiniteVec2("force", data.Force),
+		checkFiniteVec2("impulse", data.Impulse),
+		checkFinite("work", data.Work),
+		checkFinite("power", data.Power),
+		checkFinitePoints("min velocity", data.MinVel),
+		checkFinitePoints("max velocity", data.MaxVel),
+		checkFinitePoints("min acceleration", data.MinAcc),
+		checkFinitePoints("max acceleration", data.MaxAcc),
+		checkFinitePoints("min force", data.MinForce),
+		checkFinitePoints("max force", data.MaxForce),
+		checkFinitePoints("min impulse", data.MinImpulse),
+		checkFinitePoints("max impulse", data.MaxImpulse),
+		checkFinite("avg work", data.AvgWork),
+		checkFinitePoints("min work", data.MinWork),
+		checkFinitePoints("max work", data.MaxWork),
+		checkFinite("avg power", data.AvgPower),
+		checkFinitePoints("min power", data.MinPower),
+		checkFinitePoints("max power", data.MaxPower),
+		checkFinite("mean concentric vel", data.MeanConcentricVel),
+		checkFinite("mean propulsive vel", data.MeanPropulsiveVel),
+		checkFinite("time to peak vel", data.TimeToPeakVel),
+		checkFinite("concentric duration", data.ConcentricDur),
+		checkFinite("eccentric duration", data.EccentricDur),
+		checkFinite("range of motion", data.RangeOfMotion),
+	)
+}
+
+func checkLens(expLen int, lens []namedLen) error {
+	for _, l := range lens {
+		if l.Len != expLen {
+			return sberr.Wrap(
+				types.InvalidRawDataLenErr,
+				"Expected %s slice of len %d, got len %d", l.Name, expLen, l.Len,
+			)
+		}
+	}
+	return nil
+}
+
+func firstErr(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+func checkFinite[T ~float64](name string, vals []T) error {
+	for i, v := range vals {
+		if !isFinite(float64(v)) {
+			return sberr.Wrap(
+				types.NonFinitePhysicsDataErr, "%s at idx %d: got %f", name, i, v,
+			)
+		}
+	}
+	return nil
+}
+
+func checkFiniteVec2[T ~float64, U ~float64](
+	name string,
+	vals []types.Vec2[T, U],
+) error {
+	for i, v := range vals {
+		if !isFinite(float64(v.X)) || !isFinite(float64(v.Y)) {
+			return sberr.Wrap(
+				types.NonFinitePhysicsDataErr,
+				"%s at idx %d: got (%f, %f)", name, i, v.X, v.Y,
+			)
+		}
+	}
+	return nil
+}
+
+func checkFinitePoints[T ~float64, U ~float64](
+	name string,
+	vals []types.PointInTime[T, U],
+) error {
+	for i, v := range vals {
+		if !isFinite(float64(v.Time)) || !isFinite(float64(v.Value)) {
+			return sberr.Wrap(
+				types.NonFinitePhysicsDataErr,
+				"%s at idx %d: got (%f, %f)", name, i, v.Time, v.Value,
+			)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package barpathtracker
+
+import (
+	"context"
+	"iter"
+	"math"
+	"os"
+	"slices"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+type (
+	// The location and size of the marker in a single frame.
+	marker struct {
+		found bool
+		x     float64
+		y     float64
+		area  float64
+	}
+)
+
+const (
+	// The brightness a pixel must have to be considered part of the marker.
+	MarkerThreshold = 200
+	// The real world diameter of the marker. The marker is expected to cover
+	// the end of the barbell sleeve, so this is the diameter of a standard
+	// olympic sleeve. It is used to convert from pixels to meters.
+	MarkerDiameter types.Meter = 0.05
+)
+
+// Tracks the bar path in the supplied video, placing the time and position
+// data in `res`. The bar is tracked by following a bright circular marker that
+// covers the end of the barbell sleeve. The marker is assumed to be the only
+// object in the video with a brightness >= [MarkerThreshold].
+//
+// Position data is relative to the markers position in the first frame where
+// the marker was visible, with positive Y being up. Frames where the marker is
+// not visible will have their position linearly interpolated from the
+// surrounding frames.
+//
+// The following must be true for the video to be processed:
+//   - MinFileSize <= file size <= MaxFileSize
+//   - The video must be at least MinLength seconds long
+//   - The marker must be visible in at least one frame
+func Track(
+	ctxt context.Context,
+	videoPath string,
+	params *types.BarPathTrackerHyperparams,
+	res *types.RawTimeSeriesData,
+) error {
+	stat, err := os.Stat(videoPath)
+	if err != nil {
+		return sberr.AppendError(types.CouldNotDecodeVideoErr, err)
+	}
+	if uint64(stat.Size()) < params.MinFileSize ||
+		uint64(stat.Size()) > params.MaxFileSize {
+		return sberr.Wrap(
+			types.InvalidVideoFileSizeErr,
+			"File '%s' has size %d, must be in range [%d, %d]",
+			videoPath, stat.Size(), params.MinFileSize, params.MaxFileSize,
+		)
+	}
+
+	info, err := probeVideo(ctxt, videoPath)
+	if err != nil {
+		return err
+	}
+	if err := trackFrames(info, decodeVideo(ctxt, videoPath, info), res); err != nil {
+		return err
+	}
+
+	if length := videoLength(info, len(res.TimeData)); length < params.MinLength {
+		return sberr.Wrap(
+			types.VideoTooShortErr,
+			"Video '%s' is %f seconds long, must be at least %f seconds",
+			videoPath, length, params.MinLength,
+		)
+	}
+	return nil
+}
+
+func videoLength(info VideoInfo, numFrames int) types.Second {
+	return types.Second(float64(numFrames) / info.FPS)
+}
+
+func trackFrames(
+	info VideoInfo,
+	frames iter.Seq2[[]byte, error],
+	res *types.RawTimeSeriesData,
+) error {
+	markers := []marker{}
+	for frame, err := range frames {
+		if err != nil {
+			return err
+		}
+		if len(frame) != info.Width*info.Height {
+			return sberr.Wrap(
+				types.CouldNotDecodeVideoErr,
+				"Expected frame of len %d, got len %d",
+				info.Width*info.Height, len(frame),
+			)
+		}
+		markers = append(markers, findMarker(info, frame))
+	}
+
+	first := slices.IndexFunc(markers, func(m marker) bool { return m.found })
+	if first < 0 {
+		return sberr.Wrap(
+			types.BarPathMarkerNotFoundErr,
+			"The marker was not visible in any of the %d frames", len(markers),
+		)
+	}
+	fillMissingMarkers(markers)
+	metersPerPixel := markerScale(markers)
+
+	res.TimeData = slices.Grow(res.TimeData[:0], len(markers))[:len(markers)]
+	res.PositionData = slices.Grow(
+		res.PositionData[:0], len(markers),
+	)[:len(markers)]
+	for i, m := range markers {
+		res.TimeData[i] = types.Second(float64(i) / info.FPS)
+		res.PositionData[i] = types.Vec2[types.Meter, types.Meter]{
+			X: types.Meter((m.x - markers[first].x) * metersPerPixel),
+			Y: types.Meter((markers[first].y - m.y) * metersPerPixel),
+		}
+	}
+	return nil
+}
+
+// Finds the centroid and area of all pixels that are bright enough to be
+// considered part of the marker.
+func findMarker(info VideoInfo, frame []byte) marker {
+	var sumX, sumY, n float64
+	for y := range info.Height {
+		row := frame[y*info.Width : (y+1)*info.Width]
+		for x, pix := range row {
+			if pix >= MarkerThreshold {
+				sumX += float64(x)
+				sumY += float64(y)
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return marker{}
+	}
+	return marker{found: true, x: sumX / n, y: sumY / n, area: n}
+}
+
+// Linearly interpolates the position of the marker in any frames where it was
+// not found. Frames before the first and after the last found marker take the
+// position of the nearest found marker. At least one marker must be found.
+func fillMissingMarkers(markers []marker) {
+	prev := -1
+	for i := range markers {
+		if !markers[i].found {
+			continue
+		}
+		if prev < 0 {
+			for j := range i {
+				markers[j] = marker{x: markers[i].x, y: markers[i].y}
+			}
+		} else {
+			span := float64(i - prev)
+			for j := prev + 1; j < i; j++ {
+				t := float64(j-prev) / span
+				markers[j] = marker{
+					x: markers[prev].x + t*(markers[i].x-markers[prev].x),
+					y: markers[prev].y + t*(markers[i].y-markers[prev].y),
+				}
+			}
+		}
+		prev = i
+	}
+	for j := prev + 1; j < len(markers); j++ {
+		markers[j] = marker{x: markers[prev].x, y: markers[prev].y}
+	}
+}
+
+// Calculates the number of meters per pixel using the median area of all the
+// found markers and the known [MarkerDiameter]. The median is used so a few
+// partially occluded frames do not skew the scale.
+func markerScale(markers []marker) float64 {
+	areas := []float64{}
+	for _, m := range markers {
+		if m.found {
+			areas = append(areas, m.area)
+		}
+	}
+	slices.Sort(areas)
+	medianArea := areas[len(areas)/2]
+	pixelDiameter := 2 * math.Sqrt(medianArea/math.Pi)
+	return float64(MarkerDiameter) / pixelDiameter
+}
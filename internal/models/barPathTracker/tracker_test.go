@@ -0,0 +1,229 @@
+package barpathtracker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+const (
+	testRadius = 5
+)
+
+// Draws a white disc on a black background for each of the supplied centers.
+func syntheticFrames(
+	info VideoInfo,
+	centers []types.Vec2[float64, float64],
+) [][]byte {
+	res := make([][]byte, len(centers))
+	for i, c := range centers {
+		res[i] = make([]byte, info.Width*info.Height)
+		if math.IsNaN(c.X) {
+			continue
+		}
+		for y := range info.Height {
+			for x := range info.Width {
+				dx, dy := float64(x)-c.X, float64(y)-c.Y
+				if dx*dx+dy*dy <= testRadius*testRadius {
+					res[i][y*info.Width+x] = 255
+				}
+			}
+		}
+	}
+	return res
+}
+
+func framesSeq(frames [][]byte) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		for _, f := range frames {
+			if !yield(f, nil) {
+				return
+			}
+		}
+	}
+}
+
+// A marker that moves straight down and back up over the supplied number of
+// frames. Positions are rounded to whole pixels so the marker area is constant.
+func squatCenters(numFrames int) []types.Vec2[float64, float64] {
+	res := make([]types.Vec2[float64, float64], numFrames)
+	for i := range res {
+		res[i] = types.Vec2[float64, float64]{
+			X: 32,
+			Y: math.Round(16 + 30*math.Sin(math.Pi*float64(i)/float64(numFrames-1))),
+		}
+	}
+	return res
+}
+
+func metersPerPixel() float64 {
+	area := 0.0
+	for y := -testRadius; y <= testRadius; y++ {
+		for x := -testRadius; x <= testRadius; x++ {
+			if x*x+y*y <= testRadius*testRadius {
+				area++
+			}
+		}
+	}
+	return float64(MarkerDiameter) / (2 * math.Sqrt(area/math.Pi))
+}
+
+func TestTrackSyntheticFrames(t *testing.T) {
+	info := VideoInfo{Width: 64, Height: 64, FPS: 30}
+	centers := squatCenters(31)
+
+	var res types.RawTimeSeriesData
+	err := trackFrames(info, framesSeq(syntheticFrames(info, centers)), &res)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, len(centers), len(res.TimeData))
+	sbtest.Eq(t, len(centers), len(res.PositionData))
+
+	scale := metersPerPixel()
+	for i, c := range centers {
+		sbtest.EqFloat(t, float64(i)/info.FPS, float64(res.TimeData[i]), 1e-9)
+		sbtest.EqFloat(t, 0, float64(res.PositionData[i].X), 1e-2*scale)
+		sbtest.EqFloat(
+			t, (centers[0].Y-c.Y)*scale, float64(res.PositionData[i].Y),
+			1e-9,
+		)
+	}
+}
+
+func TestTrackMissingMarkerFrames(t *testing.T) {
+	info := VideoInfo{Width: 64, Height: 64, FPS: 10}
+	centers := []types.Vec2[float64, float64]{
+		{X: math.NaN()},
+		{X: 20, Y: 20},
+		{X: math.NaN()},
+		{X: math.NaN()},
+		{X: 20, Y: 32},
+		{X: math.NaN()},
+	}
+
+	var res types.RawTimeSeriesData
+	err := trackFrames(info, framesSeq(syntheticFrames(info, centers)), &res)
+	sbtest.Nil(t, err)
+
+	scale := metersPerPixel()
+	expY := []float64{0, 0, -4, -8, -12, -12}
+	for i, y := range expY {
+		sbtest.EqFloat(t, y*scale, float64(res.PositionData[i].Y), 1e-9)
+		sbtest.EqFloat(t, 0, float64(res.PositionData[i].X), 1e-9)
+	}
+}
+
+func TestTrackNoMarker(t *testing.T) {
+	info := VideoInfo{Width: 8, Height: 8, FPS: 10}
+	centers := []types.Vec2[float64, float64]{{X: math.NaN()}, {X: math.NaN()}}
+
+	var res types.RawTimeSeriesData
+	err := trackFrames(info, framesSeq(syntheticFrames(info, centers)), &res)
+	sbtest.ContainsError(
+		t, types.BarPathMarkerNotFoundErr, err,
+		`The marker was not visible in any of the 2 frames`,
+	)
+}
+
+func TestTrackInvalidFrameSize(t *testing.T) {
+	info := VideoInfo{Width: 8, Height: 8, FPS: 10}
+
+	var res types.RawTimeSeriesData
+	err := trackFrames(info, framesSeq([][]byte{make([]byte, 10)}), &res)
+	sbtest.ContainsError(
+		t, types.CouldNotDecodeVideoErr, err,
+		`Expected frame of len 64, got len 10`,
+	)
+}
+
+func TestParseFrameRate(t *testing.T) {
+	fps, err := parseFrameRate("30000/1001")
+	sbtest.Nil(t, err)
+	sbtest.EqFloat(t, 30000.0/1001.0, fps, 1e-9)
+
+	fps, err = parseFrameRate("25")
+	sbtest.Nil(t, err)
+	sbtest.EqFloat(t, 25, fps, 1e-9)
+
+	_, err = parseFrameRate("a/1")
+	sbtest.True(t, err != nil)
+}
+
+func TestTrackInvalidFileSize(t *testing.T) {
+	dir := t.TempDir()
+	videoPath := filepath.Join(dir, "Set1.mp4")
+	sbtest.Nil(t, os.WriteFile(videoPath, make([]byte, 10), 0644))
+
+	var res types.RawTimeSeriesData
+	err := Track(
+		context.Background(), videoPath,
+		&types.BarPathTrackerHyperparams{MinFileSize: 100, MaxFileSize: 200},
+		&res,
+	)
+	sbtest.ContainsError(
+		t, types.InvalidVideoFileSizeErr, err,
+		`has size 10, must be in range \[100, 200\]`,
+	)
+}
+
+// Encodes a synthetic video with ffmpeg and tracks it. Skipped when ffmpeg is
+// not available.
+func TestTrackSyntheticVideo(t *testing.T) {
+	if _, err := exec.LookPath(FFMpeg); err != nil {
+		t.Skip("ffmpeg not available")
+	}
+	if _, err := exec.LookPath(FFProbe); err != nil {
+		t.Skip("ffprobe not available")
+	}
+
+	info := VideoInfo{Width: 64, Height: 64, FPS: 30}
+	centers := squatCenters(60)
+	frames := syntheticFrames(info, centers)
+
+	videoPath := filepath.Join(t.TempDir(), "Set1.mp4")
+	cmd := exec.Command(
+		FFMpeg, "-v", "error",
+		"-f", "rawvideo", "-pix_fmt", "gray",
+		"-s", fmt.Sprintf("%dx%d", info.Width, info.Height),
+		"-r", fmt.Sprintf("%f", info.FPS),
+		"-i", "-",
+		"-c:v", "mpeg4", "-q:v", "1",
+		videoPath,
+	)
+	cmd.Stdin = bytes.NewReader(bytes.Join(frames, nil))
+	out, err := cmd.CombinedOutput()
+	sbtest.Nil(t, err)
+	if err != nil {
+		t.Log(string(out))
+	}
+
+	params := types.BarPathTrackerHyperparams{
+		MinLength:   1,
+		MinFileSize: 0,
+		MaxFileSize: 1e9,
+	}
+	var res types.RawTimeSeriesData
+	err = Track(context.Background(), videoPath, &params, &res)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, len(centers), len(res.TimeData))
+
+	scale := metersPerPixel()
+	for i, c := range centers {
+		sbtest.EqFloat(
+			t, (centers[0].Y-c.Y)*scale, float64(res.PositionData[i].Y),
+			2*scale,
+		)
+	}
+
+	params.MinLength = 10
+	err = Track(context.Background(), videoPath, &params, &res)
+	sbtest.ContainsError(t, types.VideoTooShortErr, err)
+}
@@ -0,0 +1,162 @@
+package barpathtracker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"iter"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+type (
+	// The basic properties of a video that are needed to track the bar.
+	VideoInfo struct {
+		Width  int
+		Height int
+		FPS    float64
+	}
+)
+
+var (
+	// The ffprobe executable used to get the properties of a video.
+	FFProbe = "ffprobe"
+	// The ffmpeg executable used to decode a video into raw frames.
+	FFMpeg = "ffmpeg"
+)
+
+// Gets the width, height, and frame rate of the first video stream in the
+// supplied file using ffprobe.
+func probeVideo(ctxt context.Context, videoPath string) (VideoInfo, error) {
+	var res VideoInfo
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(
+		ctxt, FFProbe,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height,avg_frame_rate",
+		"-of", "csv=p=0",
+		videoPath,
+	)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return res, sberr.Wrap(
+			types.CouldNotDecodeVideoErr,
+			"ffprobe failed on '%s': %s: %s",
+			videoPath, err, strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	parts := strings.Split(strings.TrimSpace(string(out)), ",")
+	if len(parts) != 3 {
+		return res, sberr.Wrap(
+			types.CouldNotDecodeVideoErr,
+			"Unexpected ffprobe output for '%s': %s", videoPath, out,
+		)
+	}
+	if res.Width, err = strconv.Atoi(parts[0]); err != nil {
+		return res, sberr.AppendError(types.CouldNotDecodeVideoErr, err)
+	}
+	if res.Height, err = strconv.Atoi(parts[1]); err != nil {
+		return res, sberr.AppendError(types.CouldNotDecodeVideoErr, err)
+	}
+	if res.FPS, err = parseFrameRate(parts[2]); err != nil {
+		return res, sberr.AppendError(types.CouldNotDecodeVideoErr, err)
+	}
+	if res.Width <= 0 || res.Height <= 0 || res.FPS <= 0 {
+		return res, sberr.Wrap(
+			types.CouldNotDecodeVideoErr,
+			"Invalid video properties for '%s': %dx%d @ %f fps",
+			videoPath, res.Width, res.Height, res.FPS,
+		)
+	}
+	return res, nil
+}
+
+// Parses a frame rate in the form ffprobe reports it, either as a ratio
+// (ex: 30000/1001) or a plain number.
+func parseFrameRate(rate string) (float64, error) {
+	num, den, ok := strings.Cut(rate, "/")
+	if !ok {
+		return strconv.ParseFloat(rate, 64)
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, err
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil {
+		return 0, err
+	}
+	if d == 0 {
+		return 0, nil
+	}
+	return n / d, nil
+}
+
+// Decodes the supplied video into a sequence of 8 bit grayscale frames using
+// ffmpeg. Each frame is info.Width*info.Height bytes in row major order. The
+// returned frame buffer is reused between iterations and must not be retained.
+func decodeVideo(
+	ctxt context.Context,
+	videoPath string,
+	info VideoInfo,
+) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(
+			ctxt, FFMpeg,
+			"-v", "error",
+			"-i", videoPath,
+			"-f", "rawvideo",
+			"-pix_fmt", "gray",
+			"-",
+		)
+		cmd.Stderr = &stderr
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			yield(nil, sberr.AppendError(types.CouldNotDecodeVideoErr, err))
+			return
+		}
+		if err = cmd.Start(); err != nil {
+			yield(nil, sberr.AppendError(types.CouldNotDecodeVideoErr, err))
+			return
+		}
+
+		r := bufio.NewReader(stdout)
+		frame := make([]byte, info.Width*info.Height)
+		for {
+			if _, err = io.ReadFull(r, frame); err == io.EOF {
+				break
+			} else if err != nil {
+				cmd.Process.Kill()
+				cmd.Wait()
+				yield(nil, sberr.Wrap(
+					types.CouldNotDecodeVideoErr,
+					"Could not read frame from '%s': %s", videoPath, err,
+				))
+				return
+			}
+			if !yield(frame, nil) {
+				cmd.Process.Kill()
+				cmd.Wait()
+				return
+			}
+		}
+
+		if err = cmd.Wait(); err != nil {
+			yield(nil, sberr.Wrap(
+				types.CouldNotDecodeVideoErr,
+				"ffmpeg failed on '%s': %s: %s",
+				videoPath, err, strings.TrimSpace(stderr.String()),
+			))
+		}
+	}
+}
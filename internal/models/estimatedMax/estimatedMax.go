@@ -0,0 +1,112 @@
+package estimatedmax
+
+import (
+	"math"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+const (
+	// The largest number of reps the Brzycki formula can be used with, the
+	// formula has a singularity at 37 reps.
+	MaxBrzyckiReps = 36
+	// The smallest effort the RPE table covers.
+	MinRPETableEffort = 6.5
+)
+
+var (
+	// The percent of a one rep max that can be lifted for a given number of
+	// reps and RPE. Each entry is a half step of reps+reps in reserve, so the
+	// first entry is a single at RPE 10 and the last entry is 12 reps at RPE
+	// 6.5.
+	rpeTable = [...]float64{
+		100, 97.8, 95.5, 93.9, 92.2, 90.7, 89.2, 87.8, 86.3, 85.0,
+		83.7, 82.4, 81.1, 79.9, 78.6, 77.4, 76.2, 75.1, 73.9, 72.3,
+		70.7, 69.4, 68.0, 66.7, 65.3, 64.0, 62.6, 61.3, 59.9, 58.6,
+	}
+)
+
+// Calculates an estimated max with the supplied formula. False is returned if
+// the formula cannot be applied to the supplied values.
+func Calc(
+	formula types.EstimatedMaxFormula,
+	weight types.Kilogram,
+	reps int32,
+	effort types.RPE,
+) (types.Kilogram, bool) {
+	switch formula {
+	case types.Epley:
+		return Epley(weight, reps)
+	case types.Brzycki:
+		return Brzycki(weight, reps)
+	case types.RPETable:
+		return RPETable(weight, reps, effort)
+	default:
+		return 0, false
+	}
+}
+
+// Calculates the weight that can be lifted for the supplied reps and effort
+// given an estimated max. This is the inverse of [Calc], every formula is
+// linear in the weight so the estimated max of a unit weight is the ratio
+// between the two. False is returned if the formula cannot be applied to the
+// supplied values.
+func Weight(
+	formula types.EstimatedMaxFormula,
+	max types.Kilogram,
+	reps int32,
+	effort types.RPE,
+) (types.Kilogram, bool) {
+	ratio, ok := Calc(formula, 1, reps, effort)
+	if !ok || ratio <= 0 {
+		return 0, false
+	}
+	return max / ratio, true
+}
+
+// Calculates an estimated max with the Epley formula, weight*(1+reps/30). A
+// single rep is its own max. False is returned if reps is <=0.
+func Epley(weight types.Kilogram, reps int32) (types.Kilogram, bool) {
+	if reps <= 0 {
+		return 0, false
+	}
+	if reps == 1 {
+		return weight, true
+	}
+	return weight * types.Kilogram(1+float64(reps)/30), true
+}
+
+// Calculates an estimated max with the Brzycki formula, weight*36/(37-reps).
+// False is returned if reps is <=0 or >[MaxBrzyckiReps].
+func Brzycki(weight types.Kilogram, reps int32) (types.Kilogram, bool) {
+	if reps <= 0 || reps > MaxBrzyckiReps {
+		return 0, false
+	}
+	return weight * types.Kilogram(36/(37-float64(reps))), true
+}
+
+// Calculates an estimated max from an RPE percentage table that covers 1-12
+// reps at RPE 6.5-10. Efforts between the half steps of the table are linearly
+// interpolated. False is returned if the reps and effort fall outside of the
+// table, including efforts below 6.5 even when the reps+reps in reserve would
+// land on an entry of the table.
+func RPETable(
+	weight types.Kilogram,
+	reps int32,
+	effort types.RPE,
+) (types.Kilogram, bool) {
+	if reps <= 0 || effort < MinRPETableEffort || effort > 10 {
+		return 0, false
+	}
+	halfSteps := 2 * (float64(reps-1) + 10 - float64(effort))
+	if halfSteps > float64(len(rpeTable)-1) {
+		return 0, false
+	}
+
+	lower := int(math.Floor(halfSteps))
+	percent := rpeTable[lower]
+	if frac := halfSteps - float64(lower); frac > 0 {
+		percent += frac * (rpeTable[lower+1] - rpeTable[lower])
+	}
+	return weight * types.Kilogram(100/percent), true
+}
@@ -0,0 +1,106 @@
+package estimatedmax
+
+import (
+	"testing"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestEpley(t *testing.T) {
+	res, ok := Epley(100, 1)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100, float64(res), 1e-9)
+
+	res, ok = Epley(100, 3)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 110, float64(res), 1e-9)
+
+	_, ok = Epley(100, 0)
+	sbtest.False(t, ok)
+}
+
+func TestBrzycki(t *testing.T) {
+	res, ok := Brzycki(100, 1)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100, float64(res), 1e-9)
+
+	res, ok = Brzycki(100, 10)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100*36.0/27, float64(res), 1e-9)
+
+	_, ok = Brzycki(100, 0)
+	sbtest.False(t, ok)
+	_, ok = Brzycki(100, MaxBrzyckiReps+1)
+	sbtest.False(t, ok)
+}
+
+func TestRPETable(t *testing.T) {
+	res, ok := RPETable(100, 1, 10)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100, float64(res), 1e-9)
+
+	// 5 reps at RPE 8 is 81.1%
+	res, ok = RPETable(81.1, 5, 8)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100, float64(res), 1e-9)
+
+	// 12 reps at RPE 6.5 is 58.6%, the last entry in the table
+	res, ok = RPETable(58.6, 12, 6.5)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100, float64(res), 1e-9)
+
+	// Efforts between half steps are interpolated, 3 reps at RPE 8.75 is
+	// between 87.8% (RPE 8.5) and 89.2% (RPE 9)
+	res, ok = RPETable(88.5, 3, 8.75)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100, float64(res), 1e-9)
+
+	_, ok = RPETable(100, 0, 10)
+	sbtest.False(t, ok)
+	_, ok = RPETable(100, 13, 10)
+	sbtest.False(t, ok)
+	_, ok = RPETable(100, 12, 6)
+	sbtest.False(t, ok)
+	// A single at RPE 6 would land on the table but is below its effort range
+	_, ok = RPETable(100, 1, 6)
+	sbtest.False(t, ok)
+}
+
+func TestCalc(t *testing.T) {
+	res, ok := Calc(types.Epley, 100, 3, 8)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 110, float64(res), 1e-9)
+
+	res, ok = Calc(types.Brzycki, 100, 1, 8)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100, float64(res), 1e-9)
+
+	res, ok = Calc(types.RPETable, 81.1, 5, 8)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 100, float64(res), 1e-9)
+
+	_, ok = Calc(types.EstimatedMaxFormula(-1), 100, 3, 8)
+	sbtest.False(t, ok)
+}
+
+func TestWeight(t *testing.T) {
+	for _, formula := range []types.EstimatedMaxFormula{
+		types.Epley, types.Brzycki, types.RPETable,
+	} {
+		for reps := int32(1); reps <= 12; reps++ {
+			for effort := types.RPE(6.5); effort <= 10; effort += 0.25 {
+				max, ok := Calc(formula, 100, reps, effort)
+				sbtest.True(t, ok)
+				res, ok := Weight(formula, max, reps, effort)
+				sbtest.True(t, ok)
+				sbtest.EqFloat(t, 100, float64(res), 1e-9)
+			}
+		}
+	}
+
+	_, ok := Weight(types.RPETable, 100, 1, 6)
+	sbtest.False(t, ok)
+	_, ok = Weight(types.Epley, 100, 0, 10)
+	sbtest.False(t, ok)
+}
@@ -0,0 +1,262 @@
+package fitnessfatigue
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	estimatedmax "code.barbellmath.net/barbell-math/providentia/internal/models/estimatedMax"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+type (
+	// A single training log entry used to fit the model.
+	Sample struct {
+		DatePerformed time.Time
+		Weight        types.Kilogram
+		Reps          int32
+		Effort        types.RPE
+		Volume        types.Kilogram
+	}
+
+	// The fitted state of the model. A clients estimated max on a given day is
+	// modeled as:
+	//
+	//	Baseline + Fitness*fitness - Fatigue*fatigue
+	//
+	// where fitness and fatigue are the exponentially decayed sums of the
+	// volume performed before that day. All coefficients are >=0.
+	State struct {
+		Baseline float64
+		Fitness  float64
+		Fatigue  float64
+		MSE      float64
+	}
+)
+
+const (
+	numCoeffs   = 3
+	singularEps = 1e-12
+)
+
+// Calculates the fitness and fatigue on the supplied date from all samples that
+// were performed before the date and within the time frame. The history must be
+// sorted by date.
+func FitnessAndFatigue(
+	params *types.FitnessFatigueHyperparams,
+	history []Sample,
+	date time.Time,
+) (fitness float64, fatigue float64) {
+	for _, s := range window(params, history, date, 1) {
+		days, ok := daysBefore(params, s.DatePerformed, date)
+		if !ok {
+			continue
+		}
+		fitness += float64(s.Volume) * math.Exp(-days/params.FitnessDecay)
+		fatigue += float64(s.Volume) * math.Exp(-days/params.FatigueDecay)
+	}
+	return
+}
+
+// Returns the samples in the sorted history that fall in the supplied number of
+// time frames before the supplied date.
+func window(
+	params *types.FitnessFatigueHyperparams,
+	history []Sample,
+	date time.Time,
+	numTimeFrames int,
+) []Sample {
+	start := date.AddDate(0, 0, -numTimeFrames*int(params.TimeFrame))
+	startIdx, _ := slices.BinarySearchFunc(
+		history, start, func(s Sample, t time.Time) int {
+			return s.DatePerformed.Compare(t)
+		},
+	)
+	endIdx, _ := slices.BinarySearchFunc(
+		history, date, func(s Sample, t time.Time) int {
+			return s.DatePerformed.Compare(t)
+		},
+	)
+	return history[startIdx:endIdx]
+}
+
+// Returns the number of days between the sample date and the supplied date and
+// true if the sample falls within the time frame before the supplied date.
+func daysBefore(
+	params *types.FitnessFatigueHyperparams,
+	sampleDate time.Time,
+	date time.Time,
+) (float64, bool) {
+	days := date.Sub(sampleDate).Hours() / 24
+	return days, days > 0 && days <= float64(params.TimeFrame)
+}
+
+// Fits the model to all samples in the history that were performed before the
+// supplied date and within the time frame. The estimated max of each sample is
+// calculated with the supplied formula and samples the formula cannot be
+// applied to are not used. Samples on the supplied date are not used so the
+// fitted state can be used to predict the supplied date. False will be
+// returned if there were not enough samples to fit the model. The history must
+// be sorted by date.
+func Fit(
+	params *types.FitnessFatigueHyperparams,
+	formula types.EstimatedMaxFormula,
+	history []Sample,
+	date time.Time,
+) (State, bool) {
+	// Each sample in the time frame depends on the samples in the time frame
+	// before it, so only two time frames of history are needed.
+	history = window(params, history, date, 2)
+
+	x := [][numCoeffs]float64{}
+	y := []float64{}
+	for _, s := range history {
+		if _, ok := daysBefore(params, s.DatePerformed, date); !ok {
+			continue
+		}
+		e1rm, ok := estimatedmax.Calc(formula, s.Weight, s.Reps, s.Effort)
+		if !ok {
+			continue
+		}
+		fitness, fatigue := FitnessAndFatigue(params, history, s.DatePerformed)
+		x = append(x, [numCoeffs]float64{1, fitness, -fatigue})
+		y = append(y, float64(e1rm))
+	}
+	if len(y) < int(params.MinNumSamples) {
+		return State{}, false
+	}
+
+	coeffs, sse := nonNegativeLeastSquares(x, y)
+	return State{
+		Baseline: coeffs[0],
+		Fitness:  coeffs[1],
+		Fatigue:  coeffs[2],
+		MSE:      sse / float64(len(y)),
+	}, true
+}
+
+// Returns the estimated max on the supplied date.
+func (s State) EstimatedMax(
+	params *types.FitnessFatigueHyperparams,
+	history []Sample,
+	date time.Time,
+) types.Kilogram {
+	fitness, fatigue := FitnessAndFatigue(params, history, date)
+	return types.Kilogram(max(
+		0, s.Baseline+s.Fitness*fitness-s.Fatigue*fatigue,
+	))
+}
+
+// Returns the weight that can be lifted for the supplied reps and effort on the
+// supplied date. The formula must be the one the state was fit with. False is
+// returned if the formula cannot be applied to the supplied reps and effort.
+func (s State) PredictWeight(
+	params *types.FitnessFatigueHyperparams,
+	formula types.EstimatedMaxFormula,
+	history []Sample,
+	date time.Time,
+	reps int32,
+	effort types.RPE,
+) (types.Kilogram, bool) {
+	return estimatedmax.Weight(
+		formula, s.EstimatedMax(params, history, date), reps, effort,
+	)
+}
+
+// Solves the least squares problem with the constraint that all coefficients
+// are >=0. With so few coefficients every subset of active coefficients is
+// solved and the feasible solution with the smallest error is returned.
+func nonNegativeLeastSquares(
+	x [][numCoeffs]float64,
+	y []float64,
+) (best [numCoeffs]float64, bestSSE float64) {
+	bestSSE = sumSquaredErr(x, y, best)
+	for mask := 1; mask < 1<<numCoeffs; mask++ {
+		coeffs, ok := leastSquares(x, y, mask)
+		if !ok {
+			continue
+		}
+		if sse := sumSquaredErr(x, y, coeffs); sse < bestSSE {
+			best, bestSSE = coeffs, sse
+		}
+	}
+	return
+}
+
+// Solves the normal equations for the coefficients selected by the mask. False
+// is returned if the system is singular or any coefficient is negative.
+func leastSquares(
+	x [][numCoeffs]float64,
+	y []float64,
+	mask int,
+) (res [numCoeffs]float64, ok bool) {
+	idxs := []int{}
+	for i := range numCoeffs {
+		if mask&(1<<i) != 0 {
+			idxs = append(idxs, i)
+		}
+	}
+
+	n := len(idxs)
+	a := make([][]float64, n)
+	for i := range n {
+		a[i] = make([]float64, n+1)
+		for r := range y {
+			for j := range n {
+				a[i][j] += x[r][idxs[i]] * x[r][idxs[j]]
+			}
+			a[i][n] += x[r][idxs[i]] * y[r]
+		}
+	}
+
+	scale := 0.0
+	for i := range n {
+		scale = max(scale, math.Abs(a[i][i]))
+	}
+	for col := range n {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) <= singularEps*scale {
+			return res, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := range n {
+			if r == col {
+				continue
+			}
+			f := a[r][col] / a[col][col]
+			for c := col; c <= n; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	for i := range n {
+		v := a[i][n] / a[i][i]
+		if v < 0 {
+			return res, false
+		}
+		res[idxs[i]] = v
+	}
+	return res, true
+}
+
+func sumSquaredErr(
+	x [][numCoeffs]float64,
+	y []float64,
+	coeffs [numCoeffs]float64,
+) float64 {
+	sse := 0.0
+	for r := range y {
+		pred := 0.0
+		for i := range numCoeffs {
+			pred += x[r][i] * coeffs[i]
+		}
+		sse += (y[r] - pred) * (y[r] - pred)
+	}
+	return sse
+}
@@ -0,0 +1,143 @@
+package fitnessfatigue
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+var (
+	testParams = types.FitnessFatigueHyperparams{
+		TimeFrame:     180,
+		FitnessDecay:  42,
+		FatigueDecay:  7,
+		MinNumSamples: 5,
+	}
+	// The RPE table is used so the effort of each sample affects its
+	// estimated max.
+	testFormula = types.RPETable
+	testStart   = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Generates a history where every estimated max exactly follows the supplied
+// state so the fitted state can be compared against it.
+func syntheticHistory(s State, numDays int) []Sample {
+	res := []Sample{}
+	for i := 0; i < numDays; i += 2 {
+		date := testStart.AddDate(0, 0, i)
+		// Vary the volume so the fitness and fatigue are not colinear
+		volume := types.Kilogram(1000 + 500*math.Sin(float64(i)/5))
+		reps := int32(5)
+		effort := types.RPE(8)
+
+		weight, _ := s.PredictWeight(
+			&testParams, testFormula, res, date, reps, effort,
+		)
+		res = append(res, Sample{
+			DatePerformed: date,
+			Weight:        weight,
+			Reps:          reps,
+			Effort:        effort,
+			Volume:        volume,
+		})
+	}
+	return res
+}
+
+func TestFitnessAndFatigue(t *testing.T) {
+	history := []Sample{
+		{DatePerformed: testStart, Volume: 100},
+		{DatePerformed: testStart.AddDate(0, 0, 7), Volume: 100},
+	}
+
+	fitness, fatigue := FitnessAndFatigue(&testParams, history, testStart)
+	sbtest.EqFloat(t, 0, fitness, 1e-9)
+	sbtest.EqFloat(t, 0, fatigue, 1e-9)
+
+	fitness, fatigue = FitnessAndFatigue(
+		&testParams, history, testStart.AddDate(0, 0, 7),
+	)
+	sbtest.EqFloat(t, 100*math.Exp(-7.0/42), fitness, 1e-9)
+	sbtest.EqFloat(t, 100*math.Exp(-1), fatigue, 1e-9)
+
+	fitness, _ = FitnessAndFatigue(
+		&testParams, history, testStart.AddDate(0, 0, 181),
+	)
+	sbtest.EqFloat(t, 100*math.Exp(-174.0/42), fitness, 1e-9)
+}
+
+func TestFitSynthetic(t *testing.T) {
+	exp := State{Baseline: 150, Fitness: 0.02, Fatigue: 0.03}
+	history := syntheticHistory(exp, 120)
+	date := testStart.AddDate(0, 0, 120)
+
+	res, ok := Fit(&testParams, testFormula, history, date)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, exp.Baseline, res.Baseline, 1e-6)
+	sbtest.EqFloat(t, exp.Fitness, res.Fitness, 1e-6)
+	sbtest.EqFloat(t, exp.Fatigue, res.Fatigue, 1e-6)
+	sbtest.EqFloat(t, 0, res.MSE, 1e-6)
+
+	expWeight, ok := exp.PredictWeight(
+		&testParams, testFormula, history, date, 3, 9,
+	)
+	sbtest.True(t, ok)
+	resWeight, ok := res.PredictWeight(
+		&testParams, testFormula, history, date, 3, 9,
+	)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, float64(expWeight), float64(resWeight), 1e-6)
+
+	// Efforts below the RPE table cannot be predicted
+	_, ok = res.PredictWeight(&testParams, testFormula, history, date, 3, 5)
+	sbtest.False(t, ok)
+}
+
+func TestFitNonNegative(t *testing.T) {
+	// A max that only ever decreases with training would need a negative
+	// fitness coefficient, which is not allowed.
+	history := []Sample{}
+	for i := range 20 {
+		history = append(history, Sample{
+			DatePerformed: testStart.AddDate(0, 0, i),
+			Weight:        types.Kilogram(200 - 5*i),
+			Reps:          1,
+			Effort:        10,
+			Volume:        1000,
+		})
+	}
+
+	res, ok := Fit(
+		&testParams, testFormula, history, testStart.AddDate(0, 0, 20),
+	)
+	sbtest.True(t, ok)
+	sbtest.True(t, res.Baseline >= 0)
+	sbtest.True(t, res.Fitness >= 0)
+	sbtest.True(t, res.Fatigue >= 0)
+}
+
+func TestFitNotEnoughSamples(t *testing.T) {
+	history := syntheticHistory(State{Baseline: 100}, 8)
+
+	_, ok := Fit(&testParams, testFormula, history, testStart.AddDate(0, 0, 8))
+	sbtest.False(t, ok)
+
+	// Samples on the date being fit are not used
+	_, ok = Fit(
+		&testParams, testFormula, history,
+		history[len(history)-1].DatePerformed,
+	)
+	sbtest.False(t, ok)
+}
+
+func TestFitOutsideTimeFrame(t *testing.T) {
+	history := syntheticHistory(State{Baseline: 100}, 20)
+
+	_, ok := Fit(&testParams, testFormula, history, testStart.AddDate(0, 0, 20))
+	sbtest.True(t, ok)
+	_, ok = Fit(&testParams, testFormula, history, testStart.AddDate(0, 0, 200))
+	sbtest.False(t, ok)
+}
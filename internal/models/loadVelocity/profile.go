@@ -0,0 +1,109 @@
+package loadvelocity
+
+import (
+	"math"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+type (
+	// The velocity data of a single set.
+	Set struct {
+		Weight types.Kilogram
+		// The mean concentric velocity of each rep, as calculated when the
+		// physics data of the set was calculated. A rep with no concentric
+		// portion has a mean concentric velocity of 0.
+		MeanConcentricVel []types.MeterPerSec
+	}
+)
+
+// Returns the mean concentric velocity of the fastest and slowest rep in the
+// supplied set. Reps without a concentric portion are ignored. False is
+// returned if no rep has a concentric portion.
+func RepVelRange(s *Set) (fastest types.MeterPerSec, slowest types.MeterPerSec, ok bool) {
+	for _, vel := range s.MeanConcentricVel {
+		if vel <= 0 {
+			continue
+		}
+		if !ok {
+			fastest, slowest, ok = vel, vel, true
+			continue
+		}
+		fastest = max(fastest, vel)
+		slowest = min(slowest, vel)
+	}
+	return
+}
+
+// Builds a load-velocity profile from the supplied sets. The mean concentric
+// velocity of the fastest rep of each set is regressed against the weight of
+// the set. The minimum velocity threshold is the slowest mean concentric
+// velocity of any rep in any set, and the estimated max is the weight at which
+// the profile predicts the minimum velocity threshold.
+//
+// The following must be true for a profile to be calculated:
+//   - At least two sets with concentric velocity data must be supplied
+//   - The sets must have been performed with at least two different weights
+//   - Velocity must decrease as weight increases
+func Calc(sets []Set, res *types.LoadVelocityProfile) error {
+	var n, sumX, sumY, sumXX, sumXY float64
+	minVel := math.Inf(1)
+	weights := map[types.Kilogram]struct{}{}
+	for i := range sets {
+		fastest, slowest, ok := RepVelRange(&sets[i])
+		if !ok {
+			continue
+		}
+		x, y := float64(sets[i].Weight), float64(fastest)
+		n++
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+		minVel = min(minVel, float64(slowest))
+		weights[sets[i].Weight] = struct{}{}
+	}
+	if len(weights) < 2 {
+		return sberr.Wrap(
+			types.NotEnoughVelocityDataErr,
+			"Need sets with at least 2 different weights, got %d (from %d sets with velocity data)",
+			len(weights), int(n),
+		)
+	}
+
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / n
+	if slope >= 0 {
+		return sberr.Wrap(
+			types.InvalidLoadVelocitySlopeErr,
+			"Velocity must decrease as weight increases. Got slope: %f",
+			slope,
+		)
+	}
+
+	var ssRes, ssTot float64
+	meanY := sumY / n
+	for i := range sets {
+		fastest, _, ok := RepVelRange(&sets[i])
+		if !ok {
+			continue
+		}
+		pred := intercept + slope*float64(sets[i].Weight)
+		ssRes += (float64(fastest) - pred) * (float64(fastest) - pred)
+		ssTot += (float64(fastest) - meanY) * (float64(fastest) - meanY)
+	}
+
+	*res = types.LoadVelocityProfile{
+		Intercept:       types.MeterPerSec(intercept),
+		Slope:           slope,
+		MinVelThreshold: types.MeterPerSec(minVel),
+		EstimatedMax:    types.Kilogram((minVel - intercept) / slope),
+		RSquared:        1,
+		NumSets:         int64(n),
+	}
+	if ssTot > 0 {
+		res.RSquared = 1 - ssRes/ssTot
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package loadvelocity
+
+import (
+	"testing"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+// Creates a set where each rep has the supplied mean concentric velocity.
+func syntheticSet(weight types.Kilogram, repVels ...float64) Set {
+	res := Set{Weight: weight}
+	for _, v := range repVels {
+		res.MeanConcentricVel = append(
+			res.MeanConcentricVel, types.MeterPerSec(v),
+		)
+	}
+	return res
+}
+
+// Creates a set with a single rep that has no concentric portion.
+func eccentricOnlySet(weight types.Kilogram) Set {
+	return syntheticSet(weight, 0)
+}
+
+func TestRepVelRange(t *testing.T) {
+	s := syntheticSet(100, 0.8, 0, 0.5)
+	fastest, slowest, ok := RepVelRange(&s)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 0.8, float64(fastest), 1e-9)
+	sbtest.EqFloat(t, 0.5, float64(slowest), 1e-9)
+
+	s = eccentricOnlySet(100)
+	_, _, ok = RepVelRange(&s)
+	sbtest.False(t, ok)
+
+	s = syntheticSet(100)
+	_, _, ok = RepVelRange(&s)
+	sbtest.False(t, ok)
+}
+
+func TestCalcPerfectFit(t *testing.T) {
+	// velocity = 1.5 - 0.01*weight
+	sets := []Set{
+		syntheticSet(60, 0.9, 0.85),
+		syntheticSet(80, 0.7, 0.6),
+		syntheticSet(100, 0.5, 0.4),
+		syntheticSet(120, 0.3, 0.2),
+	}
+
+	var res types.LoadVelocityProfile
+	err := Calc(sets, &res)
+	sbtest.Nil(t, err)
+	sbtest.EqFloat(t, 1.5, float64(res.Intercept), 1e-9)
+	sbtest.EqFloat(t, -0.01, res.Slope, 1e-9)
+	sbtest.EqFloat(t, 0.2, float64(res.MinVelThreshold), 1e-9)
+	sbtest.EqFloat(t, 130, float64(res.EstimatedMax), 1e-6)
+	sbtest.EqFloat(t, 1, res.RSquared, 1e-9)
+	sbtest.Eq(t, 4, res.NumSets)
+}
+
+func TestCalcNoisyFit(t *testing.T) {
+	sets := []Set{
+		syntheticSet(60, 0.92),
+		syntheticSet(80, 0.68),
+		syntheticSet(100, 0.52),
+		syntheticSet(120, 0.28),
+	}
+
+	var res types.LoadVelocityProfile
+	err := Calc(sets, &res)
+	sbtest.Nil(t, err)
+	sbtest.True(t, res.RSquared < 1)
+	sbtest.True(t, res.RSquared > 0.95)
+}
+
+func TestCalcNotEnoughData(t *testing.T) {
+	var res types.LoadVelocityProfile
+	err := Calc([]Set{syntheticSet(100, 0.5), syntheticSet(100, 0.6)}, &res)
+	sbtest.ContainsError(
+		t, types.NotEnoughVelocityDataErr, err,
+		`Need sets with at least 2 different weights, got 1 \(from 2 sets with velocity data\)`,
+	)
+
+	err = Calc([]Set{syntheticSet(100, 0.5), eccentricOnlySet(120)}, &res)
+	sbtest.ContainsError(t, types.NotEnoughVelocityDataErr, err)
+}
+
+func TestCalcInvalidSlope(t *testing.T) {
+	var res types.LoadVelocityProfile
+	err := Calc([]Set{syntheticSet(100, 0.5), syntheticSet(120, 0.6)}, &res)
+	sbtest.ContainsError(t, types.InvalidLoadVelocitySlopeErr, err)
+}
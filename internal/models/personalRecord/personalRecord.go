@@ -0,0 +1,200 @@
+package personalrecord
+
+import (
+	"time"
+
+	estimatedmax "code.barbellmath.net/barbell-math/providentia/internal/models/estimatedMax"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+type (
+	// A single training log entry along with the fastest peak velocity of any
+	// of its sets that have physics data.
+	Entry struct {
+		DatePerformed time.Time
+		Session       uint16
+		Exercise      string
+		Weight        types.Kilogram
+		Reps          int32
+		Effort        types.RPE
+		PeakVel       types.Optional[types.MeterPerSec]
+	}
+
+	key struct {
+		Exercise string
+		Kind     types.PersonalRecordKind
+		Reps     int32
+		Weight   types.Kilogram
+	}
+)
+
+// Appends every personal record set by the supplied entries to res. The
+// entries must belong to a single client and be sorted in the order they were
+// performed. A record is set when an entry strictly beats the best value of
+// all prior entries with the same identifying fields, so the first entry with
+// a given set of identifying fields always sets a record. Entries with no reps
+// are ignored, as are values that are <=0. Estimated maxes are calculated with
+// the supplied formula, entries the formula cannot be applied to never set an
+// estimated max record.
+func Detect(
+	email string,
+	entries []Entry,
+	formula types.EstimatedMaxFormula,
+	res *[]types.PersonalRecord,
+) {
+	best := map[key]float64{}
+	for i := range entries {
+		e := &entries[i]
+		entryValues(e, formula, func(k key, v float64) {
+			prev, ok := best[k]
+			if ok && v <= prev {
+				return
+			}
+			best[k] = v
+			*res = append(*res, newRecord(email, e, k, v, prev, ok))
+		})
+	}
+}
+
+// Appends the personal records set by the supplied new entries to res. The new
+// entries must belong to a single client and be sorted in the order they were
+// performed. other holds every other entry of the client for the exercises of
+// the new entries, in any order, including entries performed after the new
+// entries. A new entry only sets a record when it strictly beats every other
+// entry and every earlier new entry with the same identifying fields, so only
+// records that are still current are appended. The Previous value of each
+// record is the best value of the other entries. The same rules as [Detect]
+// apply to entries without reps, values that are <=0, and estimated maxes.
+func DetectNew(
+	email string,
+	newEntries []Entry,
+	other []Entry,
+	formula types.EstimatedMaxFormula,
+	res *[]types.PersonalRecord,
+) {
+	otherBest := map[key]float64{}
+	for i := range other {
+		entryValues(&other[i], formula, func(k key, v float64) {
+			otherBest[k] = max(otherBest[k], v)
+		})
+	}
+
+	type newBest struct {
+		idx int
+		v   float64
+	}
+	best := map[key]newBest{}
+	for i := range newEntries {
+		entryValues(&newEntries[i], formula, func(k key, v float64) {
+			if prev, ok := best[k]; !ok || v > prev.v {
+				best[k] = newBest{idx: i, v: v}
+			}
+		})
+	}
+
+	for i := range newEntries {
+		e := &newEntries[i]
+		entryValues(e, formula, func(k key, v float64) {
+			if best[k].idx != i || best[k].v != v {
+				return
+			}
+			prev, ok := otherBest[k]
+			if ok && v <= prev {
+				return
+			}
+			*res = append(*res, newRecord(email, e, k, v, prev, ok))
+		})
+	}
+}
+
+// Calls yield with every record value of the supplied entry along with the
+// key that identifies it. Values that are <=0 are not yielded.
+func entryValues(
+	e *Entry,
+	formula types.EstimatedMaxFormula,
+	yield func(k key, v float64),
+) {
+	if e.Reps <= 0 {
+		return
+	}
+	check := func(k key, v float64) {
+		if v > 0 {
+			yield(k, v)
+		}
+	}
+
+	check(
+		key{Exercise: e.Exercise, Kind: types.HeaviestWeight, Reps: e.Reps},
+		float64(e.Weight),
+	)
+	if e1rm, ok := estimatedmax.Calc(
+		formula, e.Weight, e.Reps, e.Effort,
+	); ok {
+		check(
+			key{Exercise: e.Exercise, Kind: types.BestEstimatedMax},
+			float64(e1rm),
+		)
+	}
+	check(
+		key{Exercise: e.Exercise, Kind: types.BestVolumeSet},
+		float64(e.Weight)*float64(e.Reps),
+	)
+	if e.PeakVel.Present {
+		check(
+			key{Exercise: e.Exercise, Kind: types.FastestPeakVel, Weight: e.Weight},
+			float64(e.PeakVel.Value),
+		)
+	}
+}
+
+func newRecord(
+	email string,
+	e *Entry,
+	k key,
+	v float64,
+	prev float64,
+	prevOk bool,
+) types.PersonalRecord {
+	return types.PersonalRecord{
+		WorkoutId: types.WorkoutId{
+			ClientEmail:   email,
+			Session:       e.Session,
+			DatePerformed: e.DatePerformed,
+		},
+		Exercise: e.Exercise,
+		Kind:     k.Kind,
+		Weight:   e.Weight,
+		Reps:     e.Reps,
+		Effort:   e.Effort,
+		Value:    v,
+		Previous: types.Optional[float64]{Present: prevOk, Value: prev},
+	}
+}
+
+// Returns the most recent record for each set of identifying fields in the
+// supplied history, preserving the order of the history.
+func Current(history []types.PersonalRecord) []types.PersonalRecord {
+	latest := map[key]int{}
+	for i, r := range history {
+		latest[recordKey(&r)] = i
+	}
+
+	res := make([]types.PersonalRecord, 0, len(latest))
+	for i, r := range history {
+		if latest[recordKey(&r)] == i {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+func recordKey(r *types.PersonalRecord) key {
+	res := key{Exercise: r.Exercise, Kind: r.Kind}
+	switch r.Kind {
+	case types.HeaviestWeight:
+		res.Reps = r.Reps
+	case types.FastestPeakVel:
+		res.Weight = r.Weight
+	}
+	return res
+}
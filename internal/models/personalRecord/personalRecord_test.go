@@ -0,0 +1,98 @@
+package personalrecord
+
+import (
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestDetect(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	entries := []Entry{
+		{DatePerformed: day(1), Session: 1, Exercise: "Squat", Weight: 100, Reps: 3},
+		{DatePerformed: day(2), Session: 1, Exercise: "Squat", Weight: 0, Reps: 0},
+		{
+			DatePerformed: day(3), Session: 1, Exercise: "Squat", Weight: 90, Reps: 3,
+			PeakVel: types.Optional[types.MeterPerSec]{Present: true, Value: 1},
+		},
+		{
+			DatePerformed: day(4), Session: 2, Exercise: "Squat", Weight: 90, Reps: 4,
+			PeakVel: types.Optional[types.MeterPerSec]{Present: true, Value: 1.1},
+		},
+	}
+	res := []types.PersonalRecord{}
+	Detect("email@email.com", entries, types.Epley, &res)
+
+	sbtest.Eq(t, 7, len(res))
+	sbtest.Eq(t, "email@email.com", res[0].ClientEmail)
+
+	// Day 1 sets every weight based record
+	sbtest.Eq(t, types.HeaviestWeight, res[0].Kind)
+	sbtest.EqFloat(t, 100, res[0].Value, 1e-9)
+	sbtest.False(t, res[0].Previous.Present)
+	sbtest.Eq(t, types.BestEstimatedMax, res[1].Kind)
+	sbtest.EqFloat(t, 110, res[1].Value, 1e-9)
+	sbtest.Eq(t, types.BestVolumeSet, res[2].Kind)
+	sbtest.EqFloat(t, 300, res[2].Value, 1e-9)
+
+	// Day 3 only sets a velocity record, day 2 has no reps
+	sbtest.Eq(t, types.FastestPeakVel, res[3].Kind)
+	sbtest.True(t, res[3].DatePerformed.Equal(day(3)))
+	sbtest.EqFloat(t, 1, res[3].Value, 1e-9)
+
+	// Day 4 sets a new rep count record, beats the volume and velocity
+	// records, but not the estimated max
+	sbtest.Eq(t, types.HeaviestWeight, res[4].Kind)
+	sbtest.Eq(t, 4, res[4].Reps)
+	sbtest.False(t, res[4].Previous.Present)
+	sbtest.Eq(t, types.BestVolumeSet, res[5].Kind)
+	sbtest.EqFloat(t, 360, res[5].Value, 1e-9)
+	sbtest.True(t, res[5].Previous.Present)
+	sbtest.EqFloat(t, 300, res[5].Previous.Value, 1e-9)
+	sbtest.Eq(t, types.FastestPeakVel, res[6].Kind)
+	sbtest.EqFloat(t, 1.1, res[6].Value, 1e-9)
+	sbtest.EqFloat(t, 1, res[6].Previous.Value, 1e-9)
+}
+
+func TestDetectNew(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	other := []Entry{
+		{DatePerformed: day(5), Session: 1, Exercise: "Squat", Weight: 120, Reps: 3},
+		{
+			DatePerformed: day(1), Session: 1, Exercise: "Squat", Weight: 100, Reps: 5,
+			PeakVel: types.Optional[types.MeterPerSec]{Present: true, Value: 1},
+		},
+	}
+	newEntries := []Entry{
+		// Backdated, every value is beaten by the day 5 entry
+		{DatePerformed: day(2), Session: 1, Exercise: "Squat", Weight: 110, Reps: 3},
+		{
+			DatePerformed: day(3), Session: 1, Exercise: "Squat", Weight: 100, Reps: 6,
+			PeakVel: types.Optional[types.MeterPerSec]{Present: true, Value: 0.9},
+		},
+		{DatePerformed: day(4), Session: 1, Exercise: "Squat", Weight: 105, Reps: 6},
+	}
+	res := []types.PersonalRecord{}
+	DetectNew("email@email.com", newEntries, other, types.Epley, &res)
+
+	// Day 3 is beaten by day 4 in the same kinds, its velocity record is
+	// beaten by day 1, and the day 4 estimated max is beaten by day 5
+	sbtest.Eq(t, 2, len(res))
+	for _, r := range res {
+		sbtest.True(t, r.DatePerformed.Equal(day(4)))
+	}
+	sbtest.Eq(t, types.HeaviestWeight, res[0].Kind)
+	sbtest.Eq(t, 6, res[0].Reps)
+	sbtest.EqFloat(t, 105, res[0].Value, 1e-9)
+	sbtest.False(t, res[0].Previous.Present)
+	sbtest.Eq(t, types.BestVolumeSet, res[1].Kind)
+	sbtest.EqFloat(t, 630, res[1].Value, 1e-9)
+	sbtest.True(t, res[1].Previous.Present)
+	sbtest.EqFloat(t, 500, res[1].Previous.Value, 1e-9)
+}
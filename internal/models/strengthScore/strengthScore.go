@@ -0,0 +1,127 @@
+package strengthscore
+
+import (
+	"math"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+type (
+	// The coefficients of a polynomial based score and the bodyweight range
+	// the polynomial is defined over. Coefficients are ordered from the
+	// constant term to the highest order term.
+	polyCoeffs struct {
+		Coeffs        []float64
+		MinBodyweight types.Kilogram
+		MaxBodyweight types.Kilogram
+	}
+
+	// The coefficients of the IPF GL formula, A-B*e^(-C*bodyweight).
+	ipfGLCoeffs struct {
+		A, B, C float64
+	}
+)
+
+var (
+	dotsCoeffs = map[types.Sex]polyCoeffs{
+		types.Male: {
+			Coeffs: []float64{
+				-307.75076, 24.0900756, -0.1918759221, 0.0007391293,
+				-0.000001093,
+			},
+			MinBodyweight: 40,
+			MaxBodyweight: 210,
+		},
+		types.Female: {
+			Coeffs: []float64{
+				-57.96288, 13.6175032, -0.1126655495, 0.0005158568,
+				-0.0000010706,
+			},
+			MinBodyweight: 40,
+			MaxBodyweight: 150,
+		},
+	}
+
+	wilksCoeffs = map[types.Sex]polyCoeffs{
+		types.Male: {
+			Coeffs: []float64{
+				-216.0475144, 16.2606339, -0.002388645, -0.00113732,
+				7.01863e-06, -1.291e-08,
+			},
+			MinBodyweight: 40,
+			MaxBodyweight: 201.9,
+		},
+		types.Female: {
+			Coeffs: []float64{
+				594.31747775582, -27.23842536447, 0.82112226871,
+				-0.00930733913, 4.731582e-05, -9.054e-08,
+			},
+			MinBodyweight: 26.51,
+			MaxBodyweight: 154.53,
+		},
+	}
+
+	// The classic (unequipped) three lift coefficients.
+	ipfGLClassicCoeffs = map[types.Sex]ipfGLCoeffs{
+		types.Male:   {A: 1199.72839, B: 1025.18162, C: 0.00921},
+		types.Female: {A: 610.32796, B: 1045.59282, C: 0.03048},
+	}
+)
+
+// Calculates the DOTS points of the supplied total. Bodyweights outside of the
+// range the DOTS polynomial is defined over are clamped to that range. False
+// is returned if the sex is unknown or the bodyweight is <=0.
+func Dots(
+	sex types.Sex,
+	bodyweight types.Kilogram,
+	total types.Kilogram,
+) (float64, bool) {
+	return polyScore(dotsCoeffs, sex, bodyweight, total)
+}
+
+// Calculates the Wilks points of the supplied total using the original, pre
+// 2020, coefficients. Bodyweights outside of the range the Wilks polynomial is
+// defined over are clamped to that range. False is returned if the sex is
+// unknown or the bodyweight is <=0.
+func Wilks(
+	sex types.Sex,
+	bodyweight types.Kilogram,
+	total types.Kilogram,
+) (float64, bool) {
+	return polyScore(wilksCoeffs, sex, bodyweight, total)
+}
+
+// Calculates the IPF GL points of the supplied total using the classic three
+// lift coefficients. False is returned if the sex is unknown or the bodyweight
+// is <=0.
+func IPFGL(
+	sex types.Sex,
+	bodyweight types.Kilogram,
+	total types.Kilogram,
+) (float64, bool) {
+	c, ok := ipfGLClassicCoeffs[sex]
+	if !ok || bodyweight <= 0 {
+		return 0, false
+	}
+	denom := c.A - c.B*math.Exp(-c.C*float64(bodyweight))
+	return float64(total) * 100 / denom, true
+}
+
+func polyScore(
+	coeffs map[types.Sex]polyCoeffs,
+	sex types.Sex,
+	bodyweight types.Kilogram,
+	total types.Kilogram,
+) (float64, bool) {
+	c, ok := coeffs[sex]
+	if !ok || bodyweight <= 0 {
+		return 0, false
+	}
+	bw := float64(min(max(bodyweight, c.MinBodyweight), c.MaxBodyweight))
+
+	denom := 0.0
+	for i := len(c.Coeffs) - 1; i >= 0; i-- {
+		denom = denom*bw + c.Coeffs[i]
+	}
+	return float64(total) * 500 / denom, true
+}
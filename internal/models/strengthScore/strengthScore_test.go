@@ -0,0 +1,55 @@
+package strengthscore
+
+import (
+	"testing"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestDots(t *testing.T) {
+	res, ok := Dots(types.Male, 100, 700)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 430.861, res, 1e-3)
+
+	res, ok = Dots(types.Female, 60, 400)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 443.418, res, 1e-3)
+
+	// Bodyweights are clamped to the range of the polynomial
+	clamped, ok := Dots(types.Male, 250, 700)
+	sbtest.True(t, ok)
+	res, _ = Dots(types.Male, 210, 700)
+	sbtest.EqFloat(t, res, clamped, 1e-9)
+}
+
+func TestWilks(t *testing.T) {
+	res, ok := Wilks(types.Male, 100, 700)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 426.012, res, 1e-3)
+
+	res, ok = Wilks(types.Female, 60, 400)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 445.955, res, 1e-3)
+}
+
+func TestIPFGL(t *testing.T) {
+	res, ok := IPFGL(types.Male, 100, 700)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 88.430, res, 1e-3)
+
+	res, ok = IPFGL(types.Female, 60, 400)
+	sbtest.True(t, ok)
+	sbtest.EqFloat(t, 90.416, res, 1e-3)
+}
+
+func TestInvalidArgs(t *testing.T) {
+	for _, f := range []func(
+		types.Sex, types.Kilogram, types.Kilogram,
+	) (float64, bool){Dots, Wilks, IPFGL} {
+		_, ok := f(types.UnknownSex, 100, 700)
+		sbtest.False(t, ok)
+		_, ok = f(types.Male, 0, 700)
+		sbtest.False(t, ok)
+	}
+}
@@ -0,0 +1,74 @@
+package velocityloss
+
+import "code.barbellmath.net/barbell-math/providentia/lib/types"
+
+// Calculates the velocity based fatigue indicators of a set from the mean
+// concentric velocity of each of its reps. Reps with a mean concentric velocity
+// <=0 had no concentric portion and are ignored. The threshold is the velocity
+// loss, as a percent of the best reps velocity, that marks the threshold rep.
+//
+// False is returned if no rep has a concentric portion.
+func Calc(
+	mcv []types.MeterPerSec,
+	threshold float64,
+	res *types.SetFatigue,
+) bool {
+	*res = types.SetFatigue{}
+
+	var n, sumX, sumY, sumXX, sumXY float64
+	for i, v := range mcv {
+		if v <= 0 {
+			continue
+		}
+		if n == 0 || v > res.BestVel {
+			res.BestRep = int32(i + 1)
+			res.BestVel = v
+		}
+		res.LastVel = v
+
+		x, y := float64(i+1), float64(v)
+		n++
+		sumX += x
+		sumY += y
+		sumXX += x * x
+		sumXY += x * y
+	}
+	if n == 0 {
+		return false
+	}
+
+	res.VelocityLoss = float64((res.BestVel - res.LastVel) / res.BestVel * 100)
+	thresholdVel := res.BestVel * types.MeterPerSec(1-threshold/100)
+	for i := int(res.BestRep); i < len(mcv); i++ {
+		if mcv[i] <= 0 {
+			continue
+		}
+		if mcv[i] <= thresholdVel {
+			res.ThresholdRep = int32(i + 1)
+			break
+		}
+	}
+	if n > 1 {
+		res.FatigueSlope = (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	}
+	return true
+}
+
+// Calculates the velocity based fatigue indicators of every set in the
+// supplied exercise that has physics data. The returned slice will have the
+// same length as the exercises physics data.
+func CalcForExercise(
+	e *types.ExerciseData,
+	threshold float64,
+) []types.Optional[types.SetFatigue] {
+	res := make([]types.Optional[types.SetFatigue], len(e.PhysData))
+	for i, p := range e.PhysData {
+		if !p.Present {
+			continue
+		}
+		res[i].Present = Calc(
+			p.Value.MeanConcentricVel, threshold, &res[i].Value,
+		)
+	}
+	return res
+}
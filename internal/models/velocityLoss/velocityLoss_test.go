@@ -0,0 +1,84 @@
+package velocityloss
+
+import (
+	"testing"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestCalc(t *testing.T) {
+	var res types.SetFatigue
+	ok := Calc([]types.MeterPerSec{0.8, 1, 0.9, 0.8, 0.7}, 20, &res)
+	sbtest.True(t, ok)
+	sbtest.Eq(t, 2, res.BestRep)
+	sbtest.EqFloat(t, 1, float64(res.BestVel), 1e-9)
+	sbtest.EqFloat(t, 0.7, float64(res.LastVel), 1e-9)
+	sbtest.EqFloat(t, 30, res.VelocityLoss, 1e-9)
+	sbtest.Eq(t, 4, res.ThresholdRep)
+	sbtest.EqFloat(t, -0.04, res.FatigueSlope, 1e-9)
+}
+
+func TestCalcThresholdNotReached(t *testing.T) {
+	var res types.SetFatigue
+	ok := Calc([]types.MeterPerSec{1, 0.9, 0.85}, 20, &res)
+	sbtest.True(t, ok)
+	sbtest.Eq(t, 1, res.BestRep)
+	sbtest.EqFloat(t, 15, res.VelocityLoss, 1e-9)
+	sbtest.Eq(t, 0, res.ThresholdRep)
+	sbtest.EqFloat(t, -0.075, res.FatigueSlope, 1e-9)
+}
+
+func TestCalcSkipsRepsWithoutConcentric(t *testing.T) {
+	var res types.SetFatigue
+	ok := Calc([]types.MeterPerSec{0, 1, 0.5, 0}, 50, &res)
+	sbtest.True(t, ok)
+	sbtest.Eq(t, 2, res.BestRep)
+	sbtest.EqFloat(t, 0.5, float64(res.LastVel), 1e-9)
+	sbtest.EqFloat(t, 50, res.VelocityLoss, 1e-9)
+	sbtest.Eq(t, 3, res.ThresholdRep)
+	sbtest.EqFloat(t, -0.5, res.FatigueSlope, 1e-9)
+}
+
+func TestCalcSingleRep(t *testing.T) {
+	var res types.SetFatigue
+	ok := Calc([]types.MeterPerSec{0.6}, 20, &res)
+	sbtest.True(t, ok)
+	sbtest.Eq(t, 1, res.BestRep)
+	sbtest.EqFloat(t, 0, res.VelocityLoss, 1e-9)
+	sbtest.Eq(t, 0, res.ThresholdRep)
+	sbtest.EqFloat(t, 0, res.FatigueSlope, 1e-9)
+}
+
+func TestCalcNoConcentric(t *testing.T) {
+	var res types.SetFatigue
+	sbtest.False(t, Calc([]types.MeterPerSec{}, 20, &res))
+	sbtest.False(t, Calc([]types.MeterPerSec{0, -1}, 20, &res))
+}
+
+func TestCalcForExercise(t *testing.T) {
+	e := types.ExerciseData{
+		PhysData: []types.Optional[types.PhysicsData]{
+			{
+				Present: true,
+				Value: types.PhysicsData{
+					MeanConcentricVel: []types.MeterPerSec{1, 0.5},
+				},
+			},
+			{},
+			{
+				Present: true,
+				Value: types.PhysicsData{
+					MeanConcentricVel: []types.MeterPerSec{},
+				},
+			},
+		},
+	}
+	res := CalcForExercise(&e, 20)
+	sbtest.Eq(t, 3, len(res))
+	sbtest.True(t, res[0].Present)
+	sbtest.EqFloat(t, 50, res[0].Value.VelocityLoss, 1e-9)
+	sbtest.Eq(t, 2, res[0].Value.ThresholdRep)
+	sbtest.False(t, res[1].Present)
+	sbtest.False(t, res[2].Present)
+}
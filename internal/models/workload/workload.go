@@ -0,0 +1,111 @@
+package workload
+
+import (
+	"math"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+const (
+	// The number of days in the acute window, including the current day.
+	AcuteDays = 7
+	// The number of days in the chronic window, including the current day.
+	ChronicDays = 28
+	// The number of days in the window used for monotony and strain,
+	// including the current day.
+	MonotonyDays = 7
+
+	// The number of days of load that must precede the first day of a rolling
+	// average series for every window to be full.
+	RollingLookbackDays = ChronicDays - 1
+	// The number of days of load that should precede the first day of an EWMA
+	// series. The EWMAs are seeded with 0, after this many days the seed makes
+	// up <1% of the chronic EWMA.
+	EWMALookbackDays = 3 * ChronicDays
+
+	stdDevEps = 1e-9
+)
+
+// Calculates the acute chronic workload ratio of each day in the supplied
+// loads using rolling averages. Each element of loads is the load of a single
+// day and the days must be consecutive. Days before the first supplied day are
+// treated as having no load. The date of each result is not set.
+func RollingACWR(loads []float64, res []types.ACWR) {
+	var acuteSum, chronicSum float64
+	for i, l := range loads {
+		acuteSum += l
+		chronicSum += l
+		if i >= AcuteDays {
+			acuteSum -= loads[i-AcuteDays]
+		}
+		if i >= ChronicDays {
+			chronicSum -= loads[i-ChronicDays]
+		}
+		setACWR(&res[i], l, acuteSum/AcuteDays, chronicSum/ChronicDays)
+	}
+}
+
+// Calculates the acute chronic workload ratio of each day in the supplied
+// loads using exponentially weighted moving averages. The decay of each
+// average is 2/(N+1), where N is the number of days in the window. Each
+// element of loads is the load of a single day and the days must be
+// consecutive. Both averages are seeded with 0. The date of each result is not
+// set.
+func EWMAACWR(loads []float64, res []types.ACWR) {
+	const (
+		acuteDecay   = 2.0 / (AcuteDays + 1)
+		chronicDecay = 2.0 / (ChronicDays + 1)
+	)
+	var acute, chronic float64
+	for i, l := range loads {
+		acute = acuteDecay*l + (1-acuteDecay)*acute
+		chronic = chronicDecay*l + (1-chronicDecay)*chronic
+		setACWR(&res[i], l, acute, chronic)
+	}
+}
+
+func setACWR(res *types.ACWR, load float64, acute float64, chronic float64) {
+	res.Load = load
+	res.AcuteLoad = acute
+	res.ChronicLoad = chronic
+	res.Ratio = 0
+	if chronic > 0 {
+		res.Ratio = acute / chronic
+	}
+}
+
+// Calculates Fosters training monotony and strain over the week ending on each
+// day in the supplied loads. Each element of loads is the load of a single day
+// and the days must be consecutive. Days before the first supplied day are
+// treated as having no load. The standard deviation is the population standard
+// deviation of the daily loads. Monotony is undefined when every daily load in
+// the week is equal, so monotony and strain are not present for those days. The
+// date of each result is not set.
+func MonotonyStrain(loads []float64, res []types.MonotonyStrain) {
+	for i, l := range loads {
+		window := loads[max(i-MonotonyDays+1, 0) : i+1]
+		var sum, sqDiff float64
+		for _, w := range window {
+			sum += w
+		}
+		mean := sum / MonotonyDays
+		// Days before the first supplied day have no load
+		sqDiff = float64(MonotonyDays-len(window)) * mean * mean
+		for _, w := range window {
+			sqDiff += (w - mean) * (w - mean)
+		}
+		stdDev := math.Sqrt(sqDiff / MonotonyDays)
+
+		res[i] = types.MonotonyStrain{Load: l, WeeklyLoad: sum}
+		// Guards against rounding error when every load in the week is equal
+		if stdDev > mean*stdDevEps {
+			monotony := mean / stdDev
+			res[i].Monotony = types.Optional[float64]{
+				Present: true, Value: monotony,
+			}
+			res[i].Strain = types.Optional[float64]{
+				Present: true, Value: sum * monotony,
+			}
+		}
+	}
+}
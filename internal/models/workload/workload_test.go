@@ -0,0 +1,108 @@
+package workload
+
+import (
+	"math"
+	"testing"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestRollingACWR(t *testing.T) {
+	loads := make([]float64, ChronicDays+1)
+	loads[0] = 70
+	res := make([]types.ACWR, len(loads))
+	RollingACWR(loads, res)
+
+	sbtest.EqFloat(t, 70, res[0].Load, 1e-9)
+	sbtest.EqFloat(t, 10, res[0].AcuteLoad, 1e-9)
+	sbtest.EqFloat(t, 2.5, res[0].ChronicLoad, 1e-9)
+	sbtest.EqFloat(t, 4, res[0].Ratio, 1e-9)
+
+	sbtest.EqFloat(t, 10, res[AcuteDays-1].AcuteLoad, 1e-9)
+	sbtest.EqFloat(t, 0, res[AcuteDays].AcuteLoad, 1e-9)
+	sbtest.EqFloat(t, 2.5, res[AcuteDays].ChronicLoad, 1e-9)
+	sbtest.EqFloat(t, 0, res[AcuteDays].Ratio, 1e-9)
+
+	sbtest.EqFloat(t, 2.5, res[ChronicDays-1].ChronicLoad, 1e-9)
+	sbtest.EqFloat(t, 0, res[ChronicDays].ChronicLoad, 1e-9)
+	sbtest.EqFloat(t, 0, res[ChronicDays].Ratio, 1e-9)
+}
+
+func TestRollingACWRConstantLoad(t *testing.T) {
+	loads := make([]float64, ChronicDays)
+	for i := range loads {
+		loads[i] = 10
+	}
+	res := make([]types.ACWR, len(loads))
+	RollingACWR(loads, res)
+
+	sbtest.EqFloat(t, 10, res[ChronicDays-1].AcuteLoad, 1e-9)
+	sbtest.EqFloat(t, 10, res[ChronicDays-1].ChronicLoad, 1e-9)
+	sbtest.EqFloat(t, 1, res[ChronicDays-1].Ratio, 1e-9)
+}
+
+func TestEWMAACWR(t *testing.T) {
+	loads := []float64{10, 0}
+	res := make([]types.ACWR, len(loads))
+	EWMAACWR(loads, res)
+
+	sbtest.EqFloat(t, 2.5, res[0].AcuteLoad, 1e-9)
+	sbtest.EqFloat(t, 20.0/29, res[0].ChronicLoad, 1e-9)
+	sbtest.EqFloat(t, 29.0/8, res[0].Ratio, 1e-9)
+
+	sbtest.EqFloat(t, 2.5*0.75, res[1].AcuteLoad, 1e-9)
+	sbtest.EqFloat(t, 20.0/29*27/29, res[1].ChronicLoad, 1e-9)
+}
+
+func TestEWMAACWRConverges(t *testing.T) {
+	loads := make([]float64, EWMALookbackDays)
+	for i := range loads {
+		loads[i] = 10
+	}
+	res := make([]types.ACWR, len(loads))
+	EWMAACWR(loads, res)
+
+	sbtest.EqFloat(t, 10, res[len(res)-1].AcuteLoad, 1e-3)
+	sbtest.EqFloat(t, 10, res[len(res)-1].ChronicLoad, 1e-1)
+	sbtest.EqFloat(t, 1, res[len(res)-1].Ratio, 1e-2)
+}
+
+func TestMonotonyStrain(t *testing.T) {
+	loads := []float64{7, 1, 2, 3, 4, 5, 6, 7}
+	res := make([]types.MonotonyStrain, len(loads))
+	MonotonyStrain(loads, res)
+
+	// The days before the first day have no load
+	sbtest.EqFloat(t, 7, res[0].WeeklyLoad, 1e-9)
+	sbtest.True(t, res[0].Monotony.Present)
+	sbtest.EqFloat(t, 1/math.Sqrt(6), res[0].Monotony.Value, 1e-9)
+	sbtest.True(t, res[0].Strain.Present)
+	sbtest.EqFloat(t, 7/math.Sqrt(6), res[0].Strain.Value, 1e-9)
+
+	sbtest.EqFloat(t, 7, res[7].Load, 1e-9)
+	sbtest.EqFloat(t, 28, res[7].WeeklyLoad, 1e-9)
+	sbtest.True(t, res[7].Monotony.Present)
+	sbtest.EqFloat(t, 2, res[7].Monotony.Value, 1e-9)
+	sbtest.True(t, res[7].Strain.Present)
+	sbtest.EqFloat(t, 56, res[7].Strain.Value, 1e-9)
+}
+
+func TestMonotonyStrainEqualLoads(t *testing.T) {
+	loads := []float64{0.1, 0.1, 0.1, 0.1, 0.1, 0.1, 0.1}
+	res := make([]types.MonotonyStrain, len(loads))
+	MonotonyStrain(loads, res)
+
+	// The standard deviation is 0 so monotony is undefined
+	sbtest.EqFloat(t, 0.7, res[6].WeeklyLoad, 1e-9)
+	sbtest.False(t, res[6].Monotony.Present)
+	sbtest.False(t, res[6].Strain.Present)
+	// The days before the first day have no load so the week is not equal
+	sbtest.True(t, res[5].Monotony.Present)
+
+	empty := []float64{0, 0}
+	emptyRes := make([]types.MonotonyStrain, len(empty))
+	MonotonyStrain(empty, emptyRes)
+	sbtest.False(t, emptyRes[1].Monotony.Present)
+	sbtest.False(t, emptyRes[1].Strain.Present)
+}
@@ -5,6 +5,8 @@ import (
 	"iter"
 	"os"
 	"path"
+	"slices"
+	"strings"
 	"time"
 
 	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
@@ -58,9 +60,32 @@ func DateEqual(date1 time.Time, date2 time.Time) bool {
 	return y1 == y2 && m1 == m2 && d1 == d2
 }
 
+// Returns the start of the day of the supplied time in its own location.
+func TruncDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Returns the calendar date of the supplied time in its own location as
+// midnight UTC. Consecutive UTC dates are always exactly 24 hours apart, unlike
+// dates in a location that observes daylight saving time.
+func UTCDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Returns the number of calendar days from start to end. The calendar date of
+// each time is taken in its own location, so the times do not need to share a
+// location.
+func DaysBetween(start time.Time, end time.Time) int {
+	return int(UTCDate(end).Sub(UTCDate(start)) / (24 * time.Hour))
+}
+
+// Returns the files in the supplied dir that have one of the supplied
+// extensions. Extensions must include the leading dot.
 func FilesWithExtInDir(
 	dir string,
-	ext string,
+	exts []string,
 	opts FilesWithExtInDirOpts,
 ) iter.Seq2[string, error] {
 	var err error
@@ -88,12 +113,12 @@ func FilesWithExtInDir(
 			}
 
 			name := entry.Name()
-			if path.Ext(name) != ext {
+			if !slices.Contains(exts, path.Ext(name)) {
 				if !opts.OtherFileTypesAllowed {
 					yield("", sberr.Wrap(
 						FilesWithExtInDirErr,
-						"Supplied dir (%s) contained non-csv files",
-						dir,
+						"Supplied dir (%s) contained non-%s files",
+						dir, formatExts(exts),
 					))
 					return
 				}
@@ -106,3 +131,13 @@ func FilesWithExtInDir(
 		}
 	}
 }
+
+// Formats the supplied extensions as a '/' separated list without the leading
+// dots, ie: [".csv", ".jsonl"] is formatted as "csv/jsonl".
+func formatExts(exts []string) string {
+	res := make([]string, len(exts))
+	for i, e := range exts {
+		res[i] = strings.TrimPrefix(e, ".")
+	}
+	return strings.Join(res, "/")
+}
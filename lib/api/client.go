@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+func (s *server) registerClientRoutes() {
+	s.handle("POST /clients", createClients)
+	s.handle("PUT /clients", updateClients)
+	s.handle("GET /clients/count", readNumClients)
+	s.handle("GET /clients/{email}", readClient)
+	s.handle("DELETE /clients/{email}", deleteClient)
+}
+
+func createClients(w http.ResponseWriter, r *http.Request) error {
+	ensure, err := ensureExists(r)
+	if err != nil {
+		return err
+	}
+	clients := []types.Client{}
+	if err := decodeBody(w, r, &clients); err != nil {
+		return err
+	}
+
+	if ensure {
+		err = logic.EnsureClientsExist(r.Context(), clients...)
+	} else {
+		err = logic.CreateClients(r.Context(), clients...)
+	}
+	if err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusCreated)
+}
+
+func updateClients(w http.ResponseWriter, r *http.Request) error {
+	clients := []types.Client{}
+	if err := decodeBody(w, r, &clients); err != nil {
+		return err
+	}
+	if err := logic.UpdateClients(r.Context(), clients...); err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusNoContent)
+}
+
+func readNumClients(w http.ResponseWriter, r *http.Request) error {
+	n, err := logic.ReadNumClients(r.Context())
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, CountResponse{Count: n})
+}
+
+func readClient(w http.ResponseWriter, r *http.Request) error {
+	res, err := logic.ReadClientsByEmail(r.Context(), r.PathValue("email"))
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, res[0])
+}
+
+func deleteClient(w http.ResponseWriter, r *http.Request) error {
+	if err := logic.DeleteClients(r.Context(), r.PathValue("email")); err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusNoContent)
+}
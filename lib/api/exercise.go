@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+func (s *server) registerExerciseRoutes() {
+	s.handle("POST /exercises", createExercises)
+	s.handle("GET /exercises/count", readNumExercises)
+	s.handle("GET /exercises/{name}", readExercise)
+	s.handle("DELETE /exercises/{name}", deleteExercise)
+}
+
+func createExercises(w http.ResponseWriter, r *http.Request) error {
+	ensure, err := ensureExists(r)
+	if err != nil {
+		return err
+	}
+	exercises := []types.Exercise{}
+	if err := decodeBody(w, r, &exercises); err != nil {
+		return err
+	}
+
+	if ensure {
+		err = logic.EnsureExercisesExist(r.Context(), exercises...)
+	} else {
+		err = logic.CreateExercises(r.Context(), exercises...)
+	}
+	if err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusCreated)
+}
+
+func readNumExercises(w http.ResponseWriter, r *http.Request) error {
+	n, err := logic.ReadNumExercises(r.Context())
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, CountResponse{Count: n})
+}
+
+func readExercise(w http.ResponseWriter, r *http.Request) error {
+	res, err := logic.ReadExercisesByName(r.Context(), r.PathValue("name"))
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, res[0])
+}
+
+func deleteExercise(w http.ResponseWriter, r *http.Request) error {
+	if err := logic.DeleteExercises(r.Context(), r.PathValue("name")); err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusNoContent)
+}
@@ -0,0 +1,264 @@
+// Package api exposes the [logic] package over http as a json REST api.
+//
+// All request and response bodies are json and request bodies may be at most
+// [MaxBodySize] bytes. The json encoding of every type is the default encoding
+// provided by the encoding/json package, so field names match the field names
+// of the structs in the [types] package. Dates that are part of a url path or
+// query must follow the [DateFormat] layout.
+//
+// The following endpoints are available:
+//
+//	POST   /clients                                   [logic.CreateClients], [logic.EnsureClientsExist]
+//	PUT    /clients                                   [logic.UpdateClients]
+//	GET    /clients/count                             [logic.ReadNumClients]
+//	GET    /clients/{email}                           [logic.ReadClientsByEmail]
+//	DELETE /clients/{email}                           [logic.DeleteClients]
+//
+//	POST   /exercises                                 [logic.CreateExercises], [logic.EnsureExercisesExist]
+//	GET    /exercises/count                           [logic.ReadNumExercises]
+//	GET    /exercises/{name}                          [logic.ReadExercisesByName]
+//	DELETE /exercises/{name}                          [logic.DeleteExercises]
+//
+//	GET    /hyperparams/count                         [logic.ReadNumHyperparams]
+//	POST   /hyperparams/{type}                        [logic.CreateHyperparams], [logic.EnsureHyperparamsExist]
+//	GET    /hyperparams/{type}/count                  [logic.ReadNumHyperparamsFor]
+//	GET    /hyperparams/{type}/default                [logic.ReadDefaultHyperparamsFor]
+//	GET    /hyperparams/{type}/{version}              [logic.ReadHyperparamsByVersionFor]
+//	DELETE /hyperparams/{type}/{version}              [logic.DeleteHyperparams]
+//
+//	POST   /workouts                                  [logic.CreateWorkouts]
+//	PUT    /workouts                                  [logic.UpdateWorkouts]
+//	GET    /clients/{email}/workouts/count            [logic.ReadNumWorkoutsForClient]
+//	GET    /clients/{email}/workouts?start=&end=      [logic.FindWorkoutsInDateRange]
+//	DELETE /clients/{email}/workouts?start=&end=      [logic.DeleteWorkoutsInDateRange]
+//	GET    /clients/{email}/workouts/{date}/{session} [logic.ReadWorkoutsById]
+//	DELETE /clients/{email}/workouts/{date}/{session} [logic.DeleteWorkouts]
+//
+//	GET    /clients/{email}/personal-records          [logic.ReadPersonalRecords]
+//	GET    /clients/{email}/personal-records/history  [logic.ReadPersonalRecordHistory]
+//
+// The hyperparams {type} path value is one of [types.BarPathCalcFileExt],
+// [types.BarPathTrackerFileExt], or [types.FitnessFatigueFileExt]. The POST
+// endpoints that create clients, exercises, and hyperparams accept an
+// `ensureExists=true` query parameter that selects the ensure exists variant of
+// the create function. POST /workouts responds with the personal records that
+// were set by the created workouts. The personal records endpoints accept any
+// number of `exercise` query parameters to restrict the returned records.
+//
+// Errors are returned as a json object with a single Error field. The status
+// code of an error response is selected by [StatusCode].
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type (
+	// The body of every error response.
+	ErrorResponse struct {
+		Error string
+	}
+
+	// The body of every response that returns a count.
+	CountResponse struct {
+		Count int64
+	}
+
+	server struct {
+		state *types.State
+		mux   *http.ServeMux
+	}
+
+	handlerFunc func(w http.ResponseWriter, r *http.Request) error
+)
+
+const (
+	// The format that all dates supplied in a url path or query must follow.
+	DateFormat = time.DateOnly
+	// The maximum number of bytes a request body may have. Larger bodies are
+	// rejected with [http.StatusRequestEntityTooLarge]. Workouts can carry
+	// raw physics data so this is much larger than any other body needs.
+	MaxBodySize = 64 << 20
+)
+
+var (
+	// The status codes that errors are mapped to. The first entry that
+	// matches an error is used, so more specific errors must come first. Only
+	// errors that describe why an operation failed are listed. The errors that
+	// only name the failed operation, such as
+	// [types.CouldNotReadAllClientsErr], are also returned when the database
+	// is unavailable so they must not select a status code.
+	errStatusCodes = []struct {
+		Err    error
+		Status int
+	}{
+		{Err: types.RequestTooLargeErr, Status: http.StatusRequestEntityTooLarge},
+		{Err: types.InvalidRequestErr, Status: http.StatusBadRequest},
+		{Err: context.Canceled, Status: http.StatusServiceUnavailable},
+		{Err: context.DeadlineExceeded, Status: http.StatusGatewayTimeout},
+
+		{Err: types.NotFoundErr, Status: http.StatusNotFound},
+
+		{Err: types.InvalidBarPathCalcErr, Status: http.StatusBadRequest},
+		{Err: types.InvalidBarPathTrackerErr, Status: http.StatusBadRequest},
+		{Err: types.InvalidFitnessFatigueErr, Status: http.StatusBadRequest},
+		{Err: types.InvalidSexErr, Status: http.StatusBadRequest},
+
+		{Err: types.InvalidRawDataLenErr, Status: http.StatusUnprocessableEntity},
+		{Err: types.InvalidExpNumRepsErr, Status: http.StatusUnprocessableEntity},
+		{Err: types.TimeSeriesDecreaseErr, Status: http.StatusUnprocessableEntity},
+		{Err: types.TimeSeriesNotMonotonicErr, Status: http.StatusUnprocessableEntity},
+	}
+
+	// The postgres error classes that are caused by the data in a request
+	// rather than by the database. See
+	// https://www.postgresql.org/docs/current/errcodes-appendix.html
+	requestPgErrClasses = []string{
+		"22", // Data exception
+		"23", // Integrity constraint violation
+	}
+)
+
+// Creates a new [http.Handler] that serves the endpoints outlined in the
+// package documentation. The supplied state is added to the context of every
+// request with [logic.WithStateValue] before any library functions are called.
+//
+// The supplied state will be validated with [logic.ValidateState] and an
+// error will be returned if it is not valid.
+func NewHandler(state *types.State) (http.Handler, error) {
+	if state == nil {
+		return nil, sberr.Wrap(types.InvalidCtxtErr, "State must not be nil")
+	}
+	if err := logic.ValidateState(state); err != nil {
+		return nil, err
+	}
+
+	s := &server{state: state, mux: http.NewServeMux()}
+	s.registerClientRoutes()
+	s.registerExerciseRoutes()
+	s.registerHyperparamsRoutes()
+	s.registerWorkoutRoutes()
+	return s.mux, nil
+}
+
+// Returns the http status code that the supplied error maps to. Errors that
+// are caused by the request map to 4xx status codes:
+//   - Malformed requests and invalid values map to [http.StatusBadRequest]
+//   - Request bodies larger than [MaxBodySize] ([types.RequestTooLargeErr])
+//     map to [http.StatusRequestEntityTooLarge]
+//   - Entries that do not exist ([types.NotFoundErr]) map to
+//     [http.StatusNotFound]
+//   - Data that violates a database constraint or cannot be processed maps to
+//     [http.StatusUnprocessableEntity]
+//
+// All other errors, including database and transaction failures, map to
+// [http.StatusInternalServerError].
+func StatusCode(err error) int {
+	for _, iterErr := range errStatusCodes {
+		if errors.Is(err, iterErr.Err) {
+			return iterErr.Status
+		}
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) &&
+		slices.Contains(requestPgErrClasses, pgErr.Code[:min(2, len(pgErr.Code))]) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+func (s *server) handle(pattern string, h handlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(logic.WithStateValue(r.Context(), s.state))
+		if err := h(w, r); err != nil {
+			s.writeErr(w, r, err)
+		}
+	})
+}
+
+func (s *server) writeErr(w http.ResponseWriter, r *http.Request, err error) {
+	status := StatusCode(err)
+	if status >= http.StatusInternalServerError {
+		s.state.Log.Error(
+			"API: Request failed",
+			"Method", r.Method,
+			"Path", r.URL.Path,
+			"Error", err,
+		)
+	}
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+// Marshals the supplied value before writing any part of the response so an
+// encoding error can still be reported with an error response.
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+	return nil
+}
+
+func writeNoContent(w http.ResponseWriter, status int) error {
+	w.WriteHeader(status)
+	return nil
+}
+
+func decodeBody[T any](w http.ResponseWriter, r *http.Request, v *T) error {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBodySize)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+			return sberr.Wrap(
+				types.RequestTooLargeErr,
+				"Request body must be at most %d bytes", maxErr.Limit,
+			)
+		}
+		return sberr.AppendError(
+			types.InvalidRequestErr,
+			sberr.Wrap(err, "Could not decode request body"),
+		)
+	}
+	return nil
+}
+
+func ensureExists(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("ensureExists") {
+	case "", "false":
+		return false, nil
+	case "true":
+		return true, nil
+	default:
+		return false, sberr.Wrap(
+			types.InvalidRequestErr,
+			"ensureExists must be one of true or false",
+		)
+	}
+}
+
+func parseDate(name string, s string) (time.Time, error) {
+	res, err := time.Parse(DateFormat, s)
+	if err != nil {
+		return res, sberr.AppendError(
+			types.InvalidRequestErr,
+			sberr.Wrap(
+				err, "%s must be a date with the format %s", name, DateFormat,
+			),
+		)
+	}
+	return res, nil
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+func (s *server) registerHyperparamsRoutes() {
+	s.handle("GET /hyperparams/count", readNumHyperparams)
+	registerHyperparamsRoutesFor[types.BarPathCalcHyperparams](
+		s, types.BarPathCalcFileExt,
+	)
+	registerHyperparamsRoutesFor[types.BarPathTrackerHyperparams](
+		s, types.BarPathTrackerFileExt,
+	)
+	registerHyperparamsRoutesFor[types.FitnessFatigueHyperparams](
+		s, types.FitnessFatigueFileExt,
+	)
+}
+
+func registerHyperparamsRoutesFor[T types.Hyperparams](
+	s *server,
+	hyperparamsType string,
+) {
+	prefix := "/hyperparams/" + hyperparamsType
+	s.handle("POST "+prefix, createHyperparams[T])
+	s.handle("GET "+prefix+"/count", readNumHyperparamsFor[T])
+	s.handle("GET "+prefix+"/default", readDefaultHyperparams[T])
+	s.handle("GET "+prefix+"/{version}", readHyperparams[T])
+	s.handle("DELETE "+prefix+"/{version}", deleteHyperparams[T])
+}
+
+func readNumHyperparams(w http.ResponseWriter, r *http.Request) error {
+	n, err := logic.ReadNumHyperparams(r.Context())
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, CountResponse{Count: n})
+}
+
+func createHyperparams[T types.Hyperparams](
+	w http.ResponseWriter,
+	r *http.Request,
+) error {
+	ensure, err := ensureExists(r)
+	if err != nil {
+		return err
+	}
+	params := []T{}
+	if err := decodeBody(w, r, &params); err != nil {
+		return err
+	}
+
+	if ensure {
+		err = logic.EnsureHyperparamsExist(r.Context(), params...)
+	} else {
+		err = logic.CreateHyperparams(r.Context(), params...)
+	}
+	if err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusCreated)
+}
+
+func readNumHyperparamsFor[T types.Hyperparams](
+	w http.ResponseWriter,
+	r *http.Request,
+) error {
+	n, err := logic.ReadNumHyperparamsFor[T](r.Context())
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, CountResponse{Count: n})
+}
+
+func readDefaultHyperparams[T types.Hyperparams](
+	w http.ResponseWriter,
+	r *http.Request,
+) error {
+	res, err := logic.ReadDefaultHyperparamsFor[T](r.Context())
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, res)
+}
+
+func readHyperparams[T types.Hyperparams](
+	w http.ResponseWriter,
+	r *http.Request,
+) error {
+	version, err := hyperparamsVersion(r)
+	if err != nil {
+		return err
+	}
+	res, err := logic.ReadHyperparamsByVersionFor[T](r.Context(), version)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, res[0])
+}
+
+func deleteHyperparams[T types.Hyperparams](
+	w http.ResponseWriter,
+	r *http.Request,
+) error {
+	version, err := hyperparamsVersion(r)
+	if err != nil {
+		return err
+	}
+	if err := logic.DeleteHyperparams[T](r.Context(), version); err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusNoContent)
+}
+
+func hyperparamsVersion(r *http.Request) (int32, error) {
+	version, err := strconv.ParseInt(r.PathValue("version"), 10, 32)
+	if err != nil {
+		return 0, sberr.AppendError(
+			types.InvalidRequestErr,
+			sberr.Wrap(err, "Invalid hyperparams version"),
+		)
+	}
+	return int32(version), nil
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+)
+
+func (s *server) registerWorkoutRoutes() {
+	s.handle("POST /workouts", createWorkouts)
+	s.handle("PUT /workouts", updateWorkouts)
+	s.handle("GET /clients/{email}/workouts/count", readNumWorkouts)
+	s.handle("GET /clients/{email}/workouts", findWorkoutsInDateRange)
+	s.handle("DELETE /clients/{email}/workouts", deleteWorkoutsInDateRange)
+	s.handle("GET /clients/{email}/workouts/{date}/{session}", readWorkout)
+	s.handle("DELETE /clients/{email}/workouts/{date}/{session}", deleteWorkout)
+	s.handle("GET /clients/{email}/personal-records", readPersonalRecords)
+	s.handle("GET /clients/{email}/personal-records/history", readPersonalRecordHistory)
+}
+
+func createWorkouts(w http.ResponseWriter, r *http.Request) error {
+	workouts := []types.Workout{}
+	if err := decodeBody(w, r, &workouts); err != nil {
+		return err
+	}
+	res, err := logic.CreateWorkouts(r.Context(), workouts...)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusCreated, res)
+}
+
+func readPersonalRecords(w http.ResponseWriter, r *http.Request) error {
+	res, err := logic.ReadPersonalRecords(
+		r.Context(), r.PathValue("email"), r.URL.Query()["exercise"]...,
+	)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, res)
+}
+
+func readPersonalRecordHistory(w http.ResponseWriter, r *http.Request) error {
+	res, err := logic.ReadPersonalRecordHistory(
+		r.Context(), r.PathValue("email"), r.URL.Query()["exercise"]...,
+	)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, res)
+}
+
+func updateWorkouts(w http.ResponseWriter, r *http.Request) error {
+	workouts := []types.Workout{}
+	if err := decodeBody(w, r, &workouts); err != nil {
+		return err
+	}
+	if err := logic.UpdateWorkouts(r.Context(), workouts...); err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusNoContent)
+}
+
+func readNumWorkouts(w http.ResponseWriter, r *http.Request) error {
+	n, err := logic.ReadNumWorkoutsForClient(r.Context(), r.PathValue("email"))
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, CountResponse{Count: n})
+}
+
+func findWorkoutsInDateRange(w http.ResponseWriter, r *http.Request) error {
+	start, end, err := workoutDateRange(r)
+	if err != nil {
+		return err
+	}
+	res, err := logic.FindWorkoutsInDateRange(
+		r.Context(), r.PathValue("email"), start, end,
+	)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, res)
+}
+
+func deleteWorkoutsInDateRange(w http.ResponseWriter, r *http.Request) error {
+	start, end, err := workoutDateRange(r)
+	if err != nil {
+		return err
+	}
+	n, err := logic.DeleteWorkoutsInDateRange(
+		r.Context(), r.PathValue("email"), start, end,
+	)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, CountResponse{Count: n})
+}
+
+func readWorkout(w http.ResponseWriter, r *http.Request) error {
+	id, err := workoutId(r)
+	if err != nil {
+		return err
+	}
+	res, err := logic.ReadWorkoutsById(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, res[0])
+}
+
+func deleteWorkout(w http.ResponseWriter, r *http.Request) error {
+	id, err := workoutId(r)
+	if err != nil {
+		return err
+	}
+	if err := logic.DeleteWorkouts(r.Context(), id); err != nil {
+		return err
+	}
+	return writeNoContent(w, http.StatusNoContent)
+}
+
+func workoutId(r *http.Request) (types.WorkoutId, error) {
+	date, err := parseDate("date", r.PathValue("date"))
+	if err != nil {
+		return types.WorkoutId{}, err
+	}
+	session, err := strconv.ParseUint(r.PathValue("session"), 10, 16)
+	if err != nil {
+		return types.WorkoutId{}, sberr.AppendError(
+			types.InvalidRequestErr,
+			sberr.Wrap(err, "Invalid workout session"),
+		)
+	}
+	return types.WorkoutId{
+		ClientEmail:   r.PathValue("email"),
+		Session:       uint16(session),
+		DatePerformed: date,
+	}, nil
+}
+
+func workoutDateRange(
+	r *http.Request,
+) (start time.Time, end time.Time, err error) {
+	if start, err = parseDate("start", r.URL.Query().Get("start")); err != nil {
+		return
+	}
+	end, err = parseDate("end", r.URL.Query().Get("end"))
+	return
+}
@@ -0,0 +1,101 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Adds the supplied bodyweight entries to the database. Each entry must
+// reference a client that exists and must have a weight >0. A client can have
+// at most one entry per day, including the entries that are already in the
+// database.
+//
+// The context must have a [types.State] variable.
+//
+// Entries will be uploaded in batches that respect the size set in the
+// [State.BatchSize] variable.
+//
+// If any error occurs no changes will be made to the database.
+func CreateBodyweights(
+	ctxt context.Context,
+	entries ...types.BodyweightEntry,
+) (opErr error) {
+	if len(entries) == 0 {
+		return
+	}
+	return runOp(ctxt, dal.CreateBodyweights, entries)
+}
+
+// Gets the bodyweight entries for the supplied client in the supplied date
+// range. The returned entries are ordered by the date they were measured on.
+// If `start` is after `end` an error will be returned.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func ReadBodyweightsInDateRange(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+) (res []types.BodyweightEntry, opErr error) {
+	opErr = runOp(
+		ctxt, dal.ReadBodyweightsInDateRange,
+		dal.ReadBodyweightsInDateRangeOpts{
+			Email: clientEmail,
+			Start: start,
+			End:   end,
+			Res:   &res,
+		},
+	)
+	return
+}
+
+// Updates the weight of the supplied bodyweight entries, as identified by
+// their client email and date measured. If an entry does not exist in the
+// database an error will be returned.
+//
+// The context must have a [types.State] variable.
+//
+// If any error occurs no changes will be made to the database.
+func UpdateBodyweights(
+	ctxt context.Context,
+	entries ...types.BodyweightEntry,
+) (opErr error) {
+	if len(entries) == 0 {
+		return
+	}
+	return runOp(ctxt, dal.UpdateBodyweights, entries)
+}
+
+// Deletes the bodyweight entries for the supplied client in the supplied date
+// range returning the number of deleted entries. If `start` is after `end` no
+// entries will be deleted and an error will be returned.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// If any error occurs no changes will be made to the database.
+func DeleteBodyweightsInDateRange(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+) (res int64, opErr error) {
+	opErr = runOp(
+		ctxt, dal.DeleteBodyweightsInDateRange,
+		dal.DeleteBodyweightsInDateRangeOpts{
+			Email: clientEmail,
+			Start: start,
+			End:   end,
+			Res:   &res,
+		},
+	)
+	return
+}
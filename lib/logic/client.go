@@ -7,7 +7,6 @@ import (
 	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
 	"code.barbellmath.net/barbell-math/providentia/internal/util"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
-	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
 )
 
 // Adds the supplied clients to the database. The supplied first name, last
@@ -61,26 +60,29 @@ func EnsureClientsExist(
 //   - Email (string): the email of the client
 //
 // For performance it is recommended to set the `ReuseRecord` variable to `true`
-// in the [sbcsv.Opts] struct. This will reduce the number of allocations made.
+// in the [types.FromCSVOpts] struct. This will reduce the number of allocations
+// made.
 //
 // The context must have a [types.State] variable.
 //
 // Clients will be uploaded in batches that respect the size set in the
 // [State.BatchSize] variable.
 //
-// If any error occurs no changes will be made to the database.
+// Rows that cannot be uploaded are handled according to the ErrorPolicy of
+// opts, see [types.FromCSVOpts]. With the default [types.AbortOnError] policy
+// no changes will be made to the database if any error occurs.
 func CreateClientsFromCSV(
 	ctxt context.Context,
-	opts *sbcsv.Opts,
+	opts *types.FromCSVOpts,
 	files ...string,
 ) (opErr error) {
 	if len(files) == 0 {
 		return
 	}
-	return runOp(ctxt, jobs.UploadFromCSV, &jobs.CSVLoaderOpts[types.Client]{
-		Opts:    opts,
-		Files:   util.SliceSeq2Err(files),
-		Creator: dal.CreateClients,
+	return runOp(ctxt, jobs.UploadFromCSVFiles, &jobs.CSVFilesOpts[types.Client]{
+		FromCSVOpts: opts,
+		Files:       util.SliceSeq2Err(files),
+		Creator:     dal.CreateClients,
 	})
 }
 
@@ -100,19 +102,21 @@ func CreateClientsFromCSV(
 // Clients will be uploaded in batches that respect the size set in the
 // [State.BatchSize] variable.
 //
-// If any error occurs no changes will be made to the database.
+// Rows that cannot be uploaded are handled according to the ErrorPolicy of
+// opts, see [types.FromCSVOpts]. With the default [types.AbortOnError] policy
+// no changes will be made to the database if any error occurs.
 func EnsureClientsExistFromCSV(
 	ctxt context.Context,
-	opts *sbcsv.Opts,
+	opts *types.FromCSVOpts,
 	files ...string,
 ) (opErr error) {
 	if len(files) == 0 {
 		return
 	}
-	return runOp(ctxt, jobs.UploadFromCSV, &jobs.CSVLoaderOpts[types.Client]{
-		Opts:    opts,
-		Files:   util.SliceSeq2Err(files),
-		Creator: dal.EnsureClientsExist,
+	return runOp(ctxt, jobs.UploadFromCSVFiles, &jobs.CSVFilesOpts[types.Client]{
+		FromCSVOpts: opts,
+		Files:       util.SliceSeq2Err(files),
+		Creator:     dal.EnsureClientsExist,
 	})
 }
 
@@ -199,3 +203,44 @@ func DeleteClients(ctxt context.Context, emails ...string) (opErr error) {
 	}
 	return runOp(ctxt, dal.DeleteClients, emails)
 }
+
+// Sets the optional attributes of the supplied clients, as identified by their
+// email, replacing any attributes that were previously set. If a client does
+// not exist in the database an error will be returned.
+//
+// The context must have a [types.State] variable.
+//
+// If any error occurs no changes will be made to the database.
+func SetClientAttributes(
+	ctxt context.Context,
+	attrs ...types.ClientAttributes,
+) (opErr error) {
+	if len(attrs) == 0 {
+		return
+	}
+	return runOp(ctxt, dal.SetClientAttributes, attrs)
+}
+
+// Gets the optional attributes of the clients associated with the supplied
+// emails. Clients that never had their attributes set will have zero valued
+// attributes. If a client does not exist an error will be returned. The order
+// of the returned attributes will match the order of the supplied emails.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func ReadClientAttributes(
+	ctxt context.Context,
+	emails ...string,
+) (res []types.ClientAttributes, opErr error) {
+	if len(emails) == 0 {
+		return
+	}
+	opErr = runOp(
+		ctxt, dal.ReadClientAttributes, dal.ReadClientAttributesOpts{
+			Emails: emails,
+			Res:    &res,
+		},
+	)
+	return
+}
@@ -2,6 +2,7 @@ package logic
 
 import (
 	"context"
+	"iter"
 
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
 	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
@@ -42,3 +43,25 @@ func runOp[T any](
 		func(tx pgx.Tx) error { return op(ctxt, state, tx, opts) },
 	)
 }
+
+// Runs the supplied op in a transaction that is held open while the returned
+// iterator is being consumed. The opts function is given the function the op
+// must call with each value it reads. Returning false from the iterator stops
+// the op and ends the transaction.
+func runStreamOp[T any, U any](
+	ctxt context.Context,
+	op func(ctxt context.Context, state *types.State, tx pgx.Tx, opts T) error,
+	opts func(yield func(v U) bool) T,
+) iter.Seq2[U, error] {
+	return func(yield func(U, error) bool) {
+		stopped := false
+		err := runOp(ctxt, op, opts(func(v U) bool {
+			stopped = !yield(v, nil)
+			return !stopped
+		}))
+		if err != nil && !stopped {
+			var zero U
+			yield(zero, err)
+		}
+	}
+}
@@ -33,7 +33,9 @@ func ConfDefaults() *types.Conf {
 			Port: 5432,
 		},
 		Global: types.GlobalConf{
-			BatchSize: 1e3,
+			BatchSize:             1e3,
+			VelocityLossThreshold: 20,
+			EstimatedMaxFormula:   types.Epley,
 		},
 		PhysicsJobQueue: sbjobqueue.Opts{
 			QueueLen:       10,
@@ -110,6 +112,8 @@ func ConfDefaultRequiredArgs() []string {
 //   - <longArgStart>.DB.Name
 //   - <longArgStart>.Global.BatchSize
 //   - <longArgStart>.Global.PerRequestIdCacheSize
+//   - <longArgStart>.Global.VelocityLossThreshold
+//   - <longArgStart>.Global.EstimatedMaxFormula
 //   - <longArgStart>.PhysicsData.TimeDeltaEps
 //   - <longArgStart>.PhysicsJobQueue.QueueLen
 //   - <longArgStart>.PhysicsJobQueue.MaxNumWorkers
@@ -167,6 +171,24 @@ func ConfParser(
 			10,
 		),
 	)
+	fs.Func(
+		startStr("Global", "VelocityLossThreshold"),
+		"The percent of velocity lost from the best rep of a set that marks the threshold rep of the set. Must be in the range (0, 100]",
+		sbargp.Uint(
+			&val.Global.VelocityLossThreshold,
+			_default.Global.VelocityLossThreshold,
+			10,
+		),
+	)
+	fs.TextVar(
+		&val.Global.EstimatedMaxFormula,
+		startStr("Global", "EstimatedMaxFormula"),
+		_default.Global.EstimatedMaxFormula,
+		fmt.Sprintf(
+			"The formula used to calculate estimated maxes. Must be one of: %s",
+			strings.Join(types.EstimatedMaxFormulaNames(), ", "),
+		),
+	)
 
 	jobQueueArguments(
 		fs, startStr, "Physics",
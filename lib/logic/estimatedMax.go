@@ -0,0 +1,50 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Gets the estimated max of every training log entry of the supplied exercise
+// for the supplied client in the supplied date range. The estimated maxes are
+// calculated with the supplied formula, which does not need to match the
+// [types.GlobalConf.EstimatedMaxFormula]:
+//   - [types.Epley]: weight*(1+reps/30), a single rep is its own max
+//   - [types.Brzycki]: weight*36/(37-reps), only defined for <37 reps
+//   - [types.RPETable]: weight divided by the percent of a max that can be
+//     lifted for the reps and effort of the entry, taken from an RPE table
+//     covering 1-12 reps at RPE 6.5-10
+//
+// Epley and Brzycki ignore the effort of each entry. Entries with no reps and
+// entries the formula cannot be applied to are not returned. The returned
+// entries are ordered by the date, session, and order they were performed in.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func ReadEstimatedMaxesInDateRange(
+	ctxt context.Context,
+	clientEmail string,
+	exercise string,
+	start time.Time,
+	end time.Time,
+	formula types.EstimatedMaxFormula,
+) (res []types.EstimatedMaxEntry, opErr error) {
+	opErr = runOp(
+		ctxt, dal.ReadEstimatedMaxesInDateRange,
+		dal.ReadEstimatedMaxesInDateRangeOpts{
+			Email:    clientEmail,
+			Exercise: exercise,
+			Start:    start,
+			End:      end,
+			Formula:  formula,
+			Res:      &res,
+		},
+	)
+	return
+}
@@ -7,7 +7,6 @@ import (
 	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
 	"code.barbellmath.net/barbell-math/providentia/internal/util"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
-	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
 )
 
 // Adds the supplied exercises to the database. The supplied name for each
@@ -72,19 +71,21 @@ func EnsureExercisesExist(
 // Exercises will be uploaded in batches that respect the size set in the
 // [State.BatchSize] variable.
 //
-// If any error occurs no changes will be made to the database.
+// Rows that cannot be uploaded are handled according to the ErrorPolicy of
+// opts, see [types.FromCSVOpts]. With the default [types.AbortOnError] policy
+// no changes will be made to the database if any error occurs.
 func CreateExercisesFromCSV(
 	ctxt context.Context,
-	opts *sbcsv.Opts,
+	opts *types.FromCSVOpts,
 	files ...string,
 ) (opErr error) {
 	if len(files) == 0 {
 		return
 	}
-	return runOp(ctxt, jobs.UploadFromCSV, &jobs.CSVLoaderOpts[types.Exercise]{
-		Opts:    opts,
-		Files:   util.SliceSeq2Err(files),
-		Creator: dal.CreateExercises,
+	return runOp(ctxt, jobs.UploadFromCSVFiles, &jobs.CSVFilesOpts[types.Exercise]{
+		FromCSVOpts: opts,
+		Files:       util.SliceSeq2Err(files),
+		Creator:     dal.CreateExercises,
 	})
 }
 
@@ -104,19 +105,21 @@ func CreateExercisesFromCSV(
 // Exercises will be uploaded in batches that respect the size set in the
 // [State.BatchSize] variable.
 //
-// If any error occurs no changes will be made to the database.
+// Rows that cannot be uploaded are handled according to the ErrorPolicy of
+// opts, see [types.FromCSVOpts]. With the default [types.AbortOnError] policy
+// no changes will be made to the database if any error occurs.
 func EnsureExercisesExistFromCSV(
 	ctxt context.Context,
-	opts *sbcsv.Opts,
+	opts *types.FromCSVOpts,
 	files ...string,
 ) (opErr error) {
 	if len(files) == 0 {
 		return
 	}
-	return runOp(ctxt, jobs.UploadFromCSV, &jobs.CSVLoaderOpts[types.Exercise]{
-		Opts:    opts,
-		Files:   util.SliceSeq2Err(files),
-		Creator: dal.EnsureExercisesExist,
+	return runOp(ctxt, jobs.UploadFromCSVFiles, &jobs.CSVFilesOpts[types.Exercise]{
+		FromCSVOpts: opts,
+		Files:       util.SliceSeq2Err(files),
+		Creator:     dal.EnsureExercisesExist,
 	})
 }
 
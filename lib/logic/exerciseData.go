@@ -5,6 +5,7 @@ import (
 
 	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
 )
 
 // Returns a [types.BarPathVariant] initialized with a video path as the data
@@ -25,10 +26,47 @@ func BarPathTimeSeriesData(data types.RawTimeSeriesData) types.BarPathVariant {
 	}
 }
 
+// Returns a [types.BarPathVariant] initialized with time series data loaded
+// from the supplied csv file. The file is loaded with the first registered
+// time series csv format that has all of its columns present in the file. See
+// [RegisterTimeSeriesCSVFormat] for the available formats.
+func BarPathTimeSeriesCSV(
+	opts *sbcsv.Opts,
+	path string,
+) (types.BarPathVariant, error) {
+	data, err := jobs.LoadTimeSeriesCSVFile(opts, path)
+	if err != nil {
+		return types.BarPathVariant{}, err
+	}
+	return BarPathTimeSeriesData(data), nil
+}
+
+// Registers a time series csv format so that time series csv files in a
+// workouts data dir can be loaded from the format. A format must have a unique
+// name, at least one column, and a ParseRow function. Formats are checked in
+// the order they were registered after the default formats, which are:
+//   - providentia: Time, XPos, and YPos columns in seconds and meters
+//   - metricVBT: the csv export of the Metric VBT app, displacement is in
+//     centimeters
+//
+// Formats should be registered before any data is uploaded.
+func RegisterTimeSeriesCSVFormat(f types.TimeSeriesCSVFormat) error {
+	return jobs.RegisterTimeSeriesCSVFormat(f)
+}
+
+// Removes a time series csv format that was added with
+// [RegisterTimeSeriesCSVFormat]. The default formats cannot be removed. An
+// error is returned if no format with the supplied name is registered.
+func UnregisterTimeSeriesCSVFormat(name string) error {
+	return jobs.UnregisterTimeSeriesCSVFormat(name)
+}
+
 // Calculates the physics data for the supplied exercise using the supplied
 // raw data. The `Weight`, `Sets`, and `Reps` fields of exercise data must be
 // populated with accurate values. The `PhysicsData` field will be populated
 // with the results. The length of the raw data must match the number of sets.
+// A [types.PhysicsJobFinished] event is sent to the progress sink of the state
+// as each set is finished.
 //
 // If an error occurs the state of the `PhysicsData` field in the supplied
 // `exerciseData` struct will not be deterministic and should not be used. All
@@ -7,7 +7,6 @@ import (
 	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
 	"code.barbellmath.net/barbell-math/providentia/internal/util"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
-	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
 )
 
 // Adds the supplied hyperparameters to the database. The supplied hyperparams
@@ -23,6 +22,12 @@ import (
 //   - MinLength > 0
 //   - MaxFileSize > MinFileSize
 //
+// For [types.FitnessFatigueHyperparams] the following must be true:
+//   - TimeFrame > 0
+//   - FatigueDecay > 0
+//   - FitnessDecay > FatigueDecay
+//   - MinNumSamples >= 3
+//
 // The pairing of the type of hyperparameter and version number must be unique,
 // including the set of pairs of hyperparmeter type and version number already
 // in the database.
@@ -80,19 +85,21 @@ func EnsureHyperparamsExist[T types.Hyperparams](
 // Clients will be uploaded in batches that respect the size set in the
 // [State.BatchSize] variable.
 //
-// If any error occurs no changes will be made to the database.
+// Rows that cannot be uploaded are handled according to the ErrorPolicy of
+// opts, see [types.FromCSVOpts]. With the default [types.AbortOnError] policy
+// no changes will be made to the database if any error occurs.
 func CreateHyperparamsFromCSV[T types.Hyperparams](
 	ctxt context.Context,
-	opts *sbcsv.Opts,
+	opts *types.FromCSVOpts,
 	files ...string,
 ) (opErr error) {
 	if len(files) == 0 {
 		return
 	}
-	return runOp(ctxt, jobs.UploadFromCSV, &jobs.CSVLoaderOpts[T]{
-		Opts:    opts,
-		Files:   util.SliceSeq2Err(files),
-		Creator: dal.CreateHyperparams[T],
+	return runOp(ctxt, jobs.UploadFromCSVFiles, &jobs.CSVFilesOpts[T]{
+		FromCSVOpts: opts,
+		Files:       util.SliceSeq2Err(files),
+		Creator:     dal.CreateHyperparams[T],
 	})
 }
 
@@ -112,19 +119,21 @@ func CreateHyperparamsFromCSV[T types.Hyperparams](
 // Hyperparams will be uploaded in batches that respect the size set in the
 // [State.BatchSize] variable.
 //
-// If any error occurs no changes will be made to the database.
+// Rows that cannot be uploaded are handled according to the ErrorPolicy of
+// opts, see [types.FromCSVOpts]. With the default [types.AbortOnError] policy
+// no changes will be made to the database if any error occurs.
 func EnsureHyperparamsExistFromCSV[T types.Hyperparams](
 	ctxt context.Context,
-	opts *sbcsv.Opts,
+	opts *types.FromCSVOpts,
 	files ...string,
 ) (opErr error) {
 	if len(files) == 0 {
 		return
 	}
-	return runOp(ctxt, jobs.UploadFromCSV, &jobs.CSVLoaderOpts[T]{
-		Opts:    opts,
-		Files:   util.SliceSeq2Err(files),
-		Creator: dal.EnsureHyperparamsExist[T],
+	return runOp(ctxt, jobs.UploadFromCSVFiles, &jobs.CSVFilesOpts[T]{
+		FromCSVOpts: opts,
+		Files:       util.SliceSeq2Err(files),
+		Creator:     dal.EnsureHyperparamsExist[T],
 	})
 }
 
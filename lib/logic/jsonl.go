@@ -0,0 +1,28 @@
+package logic
+
+import (
+	"io"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Writes the supplied values to the supplied writer as JSON Lines, one JSON
+// object per line. The fields of each object are named after the fields of
+// the type and enums are written with their text value. Files written with
+// this function can be loaded by [BulkUploadData] and the *FromCSV functions
+// when they have a .jsonl extension.
+//
+// Workouts are written with all of their data. The AbstractData, SetFatigue,
+// and EstimatedMax fields are ignored when workouts are loaded because they
+// are calculated when read from the database.
+func WriteJSONL[T types.JSONLTypes](w io.Writer, vals ...T) error {
+	return jobs.WriteJSONL(w, vals)
+}
+
+// Reads all the values from the supplied JSON Lines reader. Each non-blank
+// line must hold a single JSON object with no unknown fields. Errors will
+// contain the line number of the malformed line.
+func ReadJSONL[T types.JSONLTypes](r io.Reader) ([]T, error) {
+	return jobs.ReadJSONL[T](r)
+}
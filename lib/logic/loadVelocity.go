@@ -0,0 +1,43 @@
+package logic
+
+import (
+	"context"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Calculates a load-velocity profile for the supplied client and exercise from
+// every set of the exercise that has physics data. The mean concentric velocity
+// of the fastest rep of each set is regressed against the weight of the set.
+// The mean concentric velocity of a rep is the average upward velocity of the
+// bar over the portion of the rep where the bar is moving up.
+//
+// The minimum velocity threshold of the returned profile is the slowest mean
+// concentric velocity of any rep, and the estimated max is the weight at which
+// the profile predicts the minimum velocity threshold. The quality of the
+// estimate depends on the sets that were recorded, sets taken close to failure
+// give a more representative minimum velocity threshold.
+//
+// The following must be true for a profile to be calculated:
+//   - Physics data must be present for sets performed with at least two
+//     different weights
+//   - Velocity must decrease as weight increases
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func CalcLoadVelocityProfile(
+	ctxt context.Context,
+	email string,
+	exercise string,
+) (res types.LoadVelocityProfile, opErr error) {
+	opErr = runOp(
+		ctxt, jobs.CalcLoadVelocityProfile, jobs.CalcLoadVelocityProfileOpts{
+			Email:    email,
+			Exercise: exercise,
+			Res:      &res,
+		},
+	)
+	return
+}
@@ -2,7 +2,10 @@ package logic
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
 	"code.barbellmath.net/barbell-math/providentia/internal/dal/migrations"
 	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
@@ -19,9 +22,184 @@ func RunMigrations(ctxt context.Context) (opErr error) {
 	return migrations.RunMigrations(ctxt, state)
 }
 
+// Uploads all the clients, exercises, hyperparams, and workouts in the dirs
+// of the supplied options. Every file in a dir must either be a csv file or a
+// JSON Lines file with a .jsonl extension, and both kinds can be mixed in the
+// same dir. JSON Lines files hold one object per line in the format written by
+// [WriteJSONL]. The physics data of JSON Lines workouts is uploaded as is
+// rather than being calculated from a data dir.
+//
+// When DryRun is set in the supplied options every file is validated and the
+// physics data is calculated but no changes are made to the database. Every
+// problem that is found, including rows that the database rejects, is added
+// to the Report of the supplied options rather than stopping at the first
+// error. An error is only returned for problems that prevent the dry run from
+// running.
+//
+// When the ErrorPolicy of the supplied options is [types.SkipRejectedRows]
+// rows that cannot be uploaded are skipped rather than stopping the upload.
+// Each rejected row is written, along with its error, to a <file
+// name>.rejected.csv file next to the file it came from and the number of
+// accepted and rejected rows is added to the Counts of the supplied options.
+// Errors that are not tied to a row, such as a missing column, still stop the
+// upload.
+//
+// When Resume is set in the supplied options each file chunk is committed in
+// its own transaction and recorded in the import journal. If the upload fails
+// the chunks that were committed stay in the database, and running the same
+// upload again with Resume set skips them and uploads the rest. The journal
+// entries of every file are removed once the upload succeeds. If a file
+// changes between the failed and the resumed upload an error is returned,
+// use [ClearImportJournal] to start the upload of that file over.
+//
+// Progress events are sent to the progress sink of the state as files are
+// found, file chunks are scheduled and completed, rows are uploaded, and the
+// physics data of each set is calculated. See [types.ProgressSink].
+//
+// The context must have a [types.State] variable.
+//
+// If any error occurs no changes will be made to the database, unless Resume
+// is set in which case the chunks that were committed remain.
 func BulkUploadData(
 	ctxt context.Context,
 	opts *types.BulkUploadDataOpts,
 ) (opErr error) {
 	return runOp(ctxt, jobs.BulkUploadData, opts)
 }
+
+// Removes the import journal entries of the supplied files, as identified by
+// the paths they were uploaded with, so that a resumable [BulkUploadData] will
+// upload them from the start. Data that was already uploaded from the files is
+// not removed.
+//
+// The context must have a [types.State] variable.
+//
+// If any error occurs no changes will be made to the database.
+func ClearImportJournal(ctxt context.Context, files ...string) (opErr error) {
+	if len(files) == 0 {
+		return
+	}
+	return runOp(ctxt, dal.DeleteImportJournal, files)
+}
+
+// Watches the dir of the supplied options for new workout files and uploads
+// them as they are added. Files are uploaded in the same formats that
+// [BulkUploadData] loads from its workout dir. Sub-dirs of the watched dir are
+// also watched, so a workout file and its data dir can be dropped into the
+// watched dir as a single folder.
+//
+// A workout file is only uploaded once it, and every file in the data dirs it
+// references, has not changed for the StableFor duration of the supplied
+// options. This prevents partially copied files from being uploaded. Each
+// workout file is uploaded in its own transaction. Once uploaded the workout
+// file is moved to the [types.WatchDoneDir] sub-dir of the watched dir. If the
+// upload fails it is moved to the [types.WatchFailedDir] sub-dir instead and
+// the error is written next to the workout file in a
+// <file name>[types.WatchErrReportExt] file. A data dir is moved once no
+// workout file left in the watched dir references it, to the failed sub-dir if
+// any of the workout files that referenced it failed.
+//
+// Blocks until the supplied context is cancelled. An error is only returned
+// if the dir cannot be watched. Errors uploading or moving individual files
+// are logged and do not stop the watcher.
+//
+// The context must have a [types.State] variable.
+func WatchWorkoutDir(
+	ctxt context.Context,
+	opts *types.WatchWorkoutDirOpts,
+) (opErr error) {
+	// Each workout file is uploaded in its own transaction so do not call
+	// [runOp] here.
+	var state *types.State
+	if state, opErr = getState(ctxt); opErr != nil {
+		return
+	}
+	return jobs.WatchWorkoutDir(ctxt, state, opts)
+}
+
+// Writes the workouts for the supplied client in the supplied date range to
+// the supplied dir in the same layout that [BulkUploadData] loads. The dir will
+// be created if it does not exist. The following files are written:
+//   - <dir>/[types.ExportWorkoutDir]/<client email>.csv: the workout file,
+//     which can be supplied as the WorkoutDir of [types.BulkUploadDataOpts]
+//   - <dir>/[types.ExportPhysDataDir]/<client email>/<date>.<session>.<exercise idx>/SetN.csv:
+//     the time series data of each set that has physics data, in the
+//     providentia time series format
+//
+// Dates are written with the [types.ExportDateFormat] layout, so the
+// TimeFormat of the [sbcsv.Opts] must be set to it when loading. Physics data
+// is recalculated from the time series data when loading, so only the time
+// and position of each set is exported. Exporting a client again to the same
+// dir overwrites their workout file.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database. If an error occurs the files that
+// were already written will not be removed.
+func ExportWorkouts(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+	dir string,
+) (opErr error) {
+	return runOp(ctxt, jobs.ExportWorkouts, jobs.ExportWorkoutsOpts{
+		Email: clientEmail,
+		Start: start,
+		End:   end,
+		Dir:   dir,
+	})
+}
+
+// Writes every client, client attribute, bodyweight, exercise, hyperparam (all
+// versions of all models), and workout in the database to the supplied writer
+// as a single gzipped tar archive. The archive holds the following files:
+//   - manifest.json: the [types.ArchiveVersion] the archive was written with,
+//     when it was written, and the number of entries it holds
+//   - clients/clients.csv: every client
+//   - clientAttributes.jsonl: the attributes of every client that has any
+//   - bodyweights.jsonl: every bodyweight entry
+//   - exercises/exercises.csv: every exercise
+//   - hyperparams/hyperparams.<hyperparam type>.csv: every hyperparam
+//   - workouts/<client email>.jsonl: every workout of the client, in the
+//     format written by [WriteJSONL], including its physics data
+//
+// The archive can be loaded with [Restore]. The manifest is always the first
+// file in the archive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func Dump(ctxt context.Context, w io.Writer) (opErr error) {
+	return runOp(ctxt, jobs.Dump, jobs.DumpOpts{W: w})
+}
+
+// Loads an archive written by [Dump] into the database. Archives written with
+// a different [types.ArchiveVersion] cannot be restored. Clients, exercises,
+// hyperparams, and workouts are loaded using the create types in the supplied
+// options, so an archive can be merged into a populated database by using
+// [types.EnsureExists]. Note that a newly migrated database already holds the
+// default exercises and hyperparams, which are also in every archive, so
+// [types.EnsureExists] should be used for exercises and hyperparams when
+// restoring into a new database. Bodyweights are loaded using the client
+// create type and client attributes replace any existing attributes.
+//
+// Physics data is not recalculated. Each set keeps the physics data that was
+// archived, which references the versions of the hyperparams it was
+// calculated with. Those hyperparams are restored from the same archive.
+//
+// The context must have a [types.State] variable.
+//
+// If any error occurs no changes will be made to the database.
+func Restore(
+	ctxt context.Context,
+	r io.Reader,
+	opts *types.RestoreOpts,
+) (opErr error) {
+	if opts == nil {
+		opts = &types.RestoreOpts{}
+	}
+	return runOp(ctxt, jobs.Restore, jobs.RestoreOpts{R: r, RestoreOpts: opts})
+}
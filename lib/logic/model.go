@@ -0,0 +1,82 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Fits the fitness fatigue model to the training log of each of the supplied
+// clients using the [types.FitnessFatigueHyperparams] with the supplied
+// version. The hyperparams must already exist in the database.
+//
+// A model state is fit for every training log entry that has at least
+// MinNumSamples entries of the same exercise in the TimeFrame days before it.
+// Each fitted state is used to predict the weight of its training log entry.
+// Estimated maxes are calculated with the
+// [types.GlobalConf.EstimatedMaxFormula], so the model is fit with the same
+// formula that is used everywhere else. The formula is stored with each model
+// state. Entries the formula cannot be applied to are not used.
+// Any model states that were previously fit for the supplied clients with the
+// same hyperparams will be replaced.
+//
+// The context must have a [types.State] variable.
+//
+// If any error occurs no changes will be made to the database.
+func FitFitnessFatigueModel(
+	ctxt context.Context,
+	version int32,
+	emails ...string,
+) (opErr error) {
+	if len(emails) == 0 {
+		return
+	}
+	return runOp(
+		ctxt, jobs.FitFitnessFatigueModel, jobs.FitFitnessFatigueModelOpts{
+			Version: version,
+			Emails:  emails,
+		},
+	)
+}
+
+// Predicts the weight the supplied client can lift for the supplied exercise,
+// reps, and effort on the supplied date. The prediction is made with the most
+// recent model state on or before the supplied date that was fit using the
+// default [types.FitnessFatigueHyperparams]. If no such model state exists a
+// [types.NoModelStateErr] will be returned.
+//
+// The following must be true:
+//   - Reps >= 1
+//   - Effort must be in the range [0, 10]
+//   - The [types.GlobalConf.EstimatedMaxFormula] must be able to be applied
+//     to the reps and effort, i.e. the effort must be >=6.5 for the
+//     [types.RPETable] formula
+//   - The model state must have been fit with the current
+//     [types.GlobalConf.EstimatedMaxFormula]. If the formula has changed since
+//     the model was fit, the model must be refit.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func PredictWeight(
+	ctxt context.Context,
+	email string,
+	exercise string,
+	reps int32,
+	rpe types.RPE,
+	date time.Time,
+) (res types.Kilogram, opErr error) {
+	opErr = runOp(
+		ctxt, jobs.PredictWeight, jobs.PredictWeightOpts{
+			Email:    email,
+			Exercise: exercise,
+			Reps:     reps,
+			Effort:   rpe,
+			Date:     date,
+			Res:      &res,
+		},
+	)
+	return
+}
@@ -0,0 +1,66 @@
+package logic
+
+import (
+	"context"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Gets every personal record the supplied client has ever set for the
+// supplied exercises. If no exercises are supplied the records of all
+// exercises are returned. The following kinds of records are tracked:
+//   - [types.HeaviestWeight]: the heaviest weight lifted for a rep count
+//   - [types.BestEstimatedMax]: the best estimated max of any set, calculated
+//     with the [types.GlobalConf.EstimatedMaxFormula]
+//   - [types.BestVolumeSet]: the largest weight*reps of any single set
+//   - [types.FastestPeakVel]: the fastest peak velocity of any rep lifted
+//     with a weight, only sets with physics data are considered
+//
+// A record is set when a training log entry strictly beats every prior entry
+// with the same kind and identifying fields, refer to [types.PersonalRecord].
+// The first such entry always sets a record. The
+// beaten record is included with each new record. Entries with no reps never
+// set records.
+//
+// Records are calculated from the training log, so updating or deleting
+// workouts updates the history. The returned records are ordered by the date,
+// session, and exercise they were set in.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func ReadPersonalRecordHistory(
+	ctxt context.Context,
+	clientEmail string,
+	exercises ...string,
+) (res []types.PersonalRecord, opErr error) {
+	opErr = runOp(ctxt, dal.ReadPersonalRecords, dal.ReadPersonalRecordsOpts{
+		Email:     clientEmail,
+		Exercises: exercises,
+		Res:       &res,
+	})
+	return
+}
+
+// Gets the current personal records of the supplied client for the supplied
+// exercises. This is the most recent record of each kind returned by
+// [ReadPersonalRecordHistory]. If no exercises are supplied the records of
+// all exercises are returned.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func ReadPersonalRecords(
+	ctxt context.Context,
+	clientEmail string,
+	exercises ...string,
+) (res []types.PersonalRecord, opErr error) {
+	opErr = runOp(ctxt, dal.ReadPersonalRecords, dal.ReadPersonalRecordsOpts{
+		Email:       clientEmail,
+		Exercises:   exercises,
+		CurrentOnly: true,
+		Res:         &res,
+	})
+	return
+}
@@ -0,0 +1,43 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Gets the velocity based fatigue indicators of every set with physics data
+// for the supplied client in the supplied date range. Sets where no rep has a
+// concentric portion are not returned. The returned entries are ordered by the
+// date, session, exercise, and set they were performed in.
+//
+// The velocity loss of a set is the percent of mean concentric velocity lost
+// from the fastest rep to the last rep. The threshold rep is the first rep
+// after the fastest rep where the velocity loss reached the
+// [types.GlobalConf.VelocityLossThreshold]. The fatigue slope is the slope of
+// a linear regression of the mean concentric velocity of each rep against the
+// rep number.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func ReadSetFatigueInDateRange(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+) (res []types.SetFatigueEntry, opErr error) {
+	opErr = runOp(
+		ctxt, jobs.ReadSetFatigueInDateRange, jobs.ReadSetFatigueInDateRangeOpts{
+			Email: clientEmail,
+			Start: start,
+			End:   end,
+			Res:   &res,
+		},
+	)
+	return
+}
@@ -82,6 +82,26 @@ func checkStateGlobalConf(state *types.State) error {
 			),
 		)
 	}
+	if state.Global.VelocityLossThreshold == 0 ||
+		state.Global.VelocityLossThreshold > 100 {
+		return sberr.AppendError(
+			types.InvalidGlobalConfErr,
+			sberr.Wrap(
+				types.InvalidVelocityLossThresholdErr,
+				"Must be in the range (0, 100]. Got: %d",
+				state.Global.VelocityLossThreshold,
+			),
+		)
+	}
+	if !state.Global.EstimatedMaxFormula.IsValid() {
+		return sberr.AppendError(
+			types.InvalidGlobalConfErr,
+			sberr.Wrap(
+				types.InvalidEstimatedMaxFormulaErr,
+				"Got: %s", state.Global.EstimatedMaxFormula,
+			),
+		)
+	}
 	if state.Global.BatchSize > 1e5 {
 		state.Log.Warn(
 			"Large batch sizes can lead to OOM errors and will limit the " +
@@ -0,0 +1,54 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Calculates the DOTS, Wilks, and IPF GL points of the supplied clients best
+// squat, bench, and deadlift in the supplied date range. Only exercises with a
+// kind of [types.MainCompound] and a focus of [types.Squat], [types.Bench], or
+// [types.Deadlift] are considered. The best lift of each focus is the one with
+// the largest estimated max, calculated with the
+// [types.GlobalConf.EstimatedMaxFormula]. The estimated maxes of the best
+// lifts are summed to get the total that is scored, lifts that were not
+// performed in the date range do not contribute to the total. If any lift was
+// not performed the Partial field of the result is set, and the scores should
+// not be compared to scores from a full total.
+//
+// The scores are estimates. They are defined for a total of lifts performed at
+// a meet, but are calculated here from estimated maxes.
+//
+// The scores are calculated with:
+//   - DOTS: the 2019 coefficients
+//   - Wilks: the original, pre 2020, coefficients
+//   - IPF GL: the classic three lift coefficients
+//
+// The clients sex must have been set with [SetClientAttributes] and the client
+// must have a bodyweight entry before `end`, otherwise an error will be
+// returned. The most recent bodyweight entry before `end` is used.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func CalcStrengthScores(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+) (res types.StrengthScores, opErr error) {
+	opErr = runOp(
+		ctxt, jobs.CalcStrengthScores, jobs.CalcStrengthScoresOpts{
+			Email: clientEmail,
+			Start: start,
+			End:   end,
+			Res:   &res,
+		},
+	)
+	return
+}
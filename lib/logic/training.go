@@ -0,0 +1,127 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+)
+
+// Gets the sums and averages of the volume, exertion, and total reps of the
+// supplied clients training in the supplied date range. The training log
+// entries are bucketed by the supplied granularity and then grouped by
+// exercise, exercise focus, or exercise kind as selected by the supplied group
+// by value. All aggregation is performed by the database.
+//
+// Buckets are aligned to calendar days, ISO weeks (starting on Monday), or
+// calendar months, so the first bucket may start before `start`. Only entries
+// within the date range are included in any bucket. Buckets without any
+// entries are not returned. The returned aggregates are ordered by bucket and
+// then group.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func AggregateTraining(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+	granularity types.TrainingGranularity,
+	groupBy types.TrainingGroupBy,
+) (res []types.TrainingAggregate, opErr error) {
+	opErr = runOp(ctxt, dal.AggregateTraining, dal.AggregateTrainingOpts{
+		Email:       clientEmail,
+		Start:       start,
+		End:         end,
+		Granularity: granularity,
+		GroupBy:     groupBy,
+		Res:         &res,
+	})
+	return
+}
+
+// Calculates the acute chronic workload ratio (ACWR) of the supplied client
+// for every day in the supplied date range. The daily load is the sum of the
+// metric selected by `opts` over every training log entry performed on that
+// day, and may be restricted to exercises with a single focus. Days without
+// any entries have a load of 0.
+//
+// The acute window covers 7 days and the chronic window covers 28 days, both
+// including the current day. The windows are averaged according to the
+// supplied method:
+//   - [types.RollingAverage]: the mean daily load of each window
+//   - [types.EWMA]: an exponentially weighted moving average of the daily load
+//     with a decay of 2/(N+1), where N is the number of days in the window
+//
+// Training performed before `start` is used to fill the windows of the first
+// days in the date range. The EWMAs are seeded with a load of 0 84 days
+// before `start`. The ratio of a day is 0 if its chronic load is 0. One entry
+// is returned per day, ordered by date.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func CalcACWR(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+	method types.ACWRMethod,
+	opts types.WorkloadOpts,
+) (res []types.ACWR, opErr error) {
+	opErr = runOp(ctxt, jobs.CalcACWR, jobs.CalcACWROpts{
+		Email:        clientEmail,
+		Start:        start,
+		End:          end,
+		Method:       method,
+		WorkloadOpts: opts,
+		Res:          &res,
+	})
+	return
+}
+
+// Calculates Fosters training monotony and strain of the supplied client for
+// every day in the supplied date range. The daily load is the sum of the
+// metric selected by `opts` over every training log entry performed on that
+// day, and may be restricted to exercises with a single focus. Days without
+// any entries have a load of 0.
+//
+// Both values are calculated over the 7 days ending on, and including, each
+// day:
+//   - Monotony: the mean daily load divided by the population standard
+//     deviation of the daily load
+//   - Strain: the sum of the daily load multiplied by the monotony
+//
+// Training performed before `start` is used to fill the weeks of the first
+// days in the date range. Monotony is undefined when every daily load in the
+// week is equal, including weeks without any load, so monotony and strain are
+// not present for those days. One entry is returned per day, ordered by date.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func CalcMonotonyStrain(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+	opts types.WorkloadOpts,
+) (res []types.MonotonyStrain, opErr error) {
+	opErr = runOp(ctxt, jobs.CalcMonotonyStrain, jobs.CalcMonotonyStrainOpts{
+		Email:        clientEmail,
+		Start:        start,
+		End:          end,
+		WorkloadOpts: opts,
+		Res:          &res,
+	})
+	return
+}
@@ -2,9 +2,11 @@ package logic
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"code.barbellmath.net/barbell-math/providentia/internal/dal"
+	"code.barbellmath.net/barbell-math/providentia/internal/jobs"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
 )
 
@@ -29,15 +31,26 @@ import (
 // Workouts will be uploaded in batches that respect the size set in the
 // [State.BatchSize] variable.
 //
+// The personal records that were set by the supplied workouts and are still
+// current are returned. A workout sets a record when it beats every other
+// entry for the same client and exercise, including entries that were
+// performed after it and entries in the other supplied workouts, so a
+// backdated workout only sets a record if nothing later has beaten it. Refer
+// to [ReadPersonalRecordHistory] for the kinds of records that are tracked.
+//
 // If any error occurs no changes will be made to the database.
 func CreateWorkouts(
 	ctxt context.Context,
 	workouts ...types.Workout,
-) (opErr error) {
+) (res []types.PersonalRecord, opErr error) {
 	if len(workouts) == 0 {
 		return
 	}
-	return runOp(ctxt, dal.CreateWorkouts, workouts)
+	opErr = runOp(ctxt, dal.CreateWorkouts, dal.CreateWorkoutsOpts{
+		Workouts: workouts,
+		PRs:      &res,
+	})
+	return
 }
 
 // Gets the total number of workouts in the database for a given client.
@@ -134,6 +147,165 @@ func FindWorkoutsInDateRange(
 	return
 }
 
+// Gets the workout summaries associated with the supplied ids if they exist. If
+// they do not exist an error will be returned. The order of the returned
+// summaries will match the order of the supplied workout ids.
+//
+// Summaries hold the same data as [ReadWorkoutsById] minus the physics data,
+// which is never read from the database. Each exercise summary instead has a
+// flag for each set indicating if the set has physics data.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func ReadWorkoutSummariesById(
+	ctxt context.Context,
+	ids ...types.WorkoutId,
+) (res []types.WorkoutSummary, opErr error) {
+	if len(ids) == 0 {
+		return
+	}
+	opErr = runOp(
+		ctxt, dal.ReadWorkoutSummariesById, dal.ReadWorkoutSummariesByIdOpts{
+			Ids: ids,
+			Res: &res,
+		},
+	)
+	return
+}
+
+// Gets the workout summaries for the supplied client in the supplied date
+// range. Has the same behavior as [FindWorkoutsInDateRange] other than
+// returning summaries, as described by [ReadWorkoutSummariesById]. Summaries
+// are ordered by date and then session.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func FindWorkoutSummariesInDateRange(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+) (res []types.WorkoutSummary, opErr error) {
+	opErr = runOp(
+		ctxt, dal.FindWorkoutSummariesInDateRange,
+		dal.FindWorkoutSummariesInDateRangeOpts{
+			Email: clientEmail,
+			Start: start,
+			End:   end,
+			Res:   &res,
+		},
+	)
+	return
+}
+
+// Streams the workout data associated with the supplied ids. Has the same
+// behavior as [ReadWorkoutsById] other than returning the workouts one at a
+// time rather than as a slice. The order of the workouts will match the order
+// of the supplied workout ids. If a workout does not exist an error will be
+// yielded and iteration will stop.
+//
+// A transaction is held open while the returned iterator is being consumed.
+// Physics data is only read if the SkipPhysData option is false.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func StreamWorkoutsById(
+	ctxt context.Context,
+	opts types.StreamWorkoutsOpts,
+	ids ...types.WorkoutId,
+) iter.Seq2[types.Workout, error] {
+	return runStreamOp(
+		ctxt, dal.StreamWorkoutsById,
+		func(yield func(w types.Workout) bool) dal.StreamWorkoutsByIdOpts {
+			return dal.StreamWorkoutsByIdOpts{
+				Ids:          ids,
+				SkipPhysData: opts.SkipPhysData,
+				Yield:        yield,
+			}
+		},
+	)
+}
+
+// Streams the workouts for the supplied client in the supplied date range.
+// Has the same behavior as [FindWorkoutsInDateRange] other than returning the
+// workouts one at a time rather than as a slice. Workouts are ordered by date
+// and then session.
+//
+// `start` is inclusive and `end` is exclusive.
+//
+// A transaction is held open while the returned iterator is being consumed.
+// The rows are read from the database in batches that respect the size set in
+// the [State.BatchSize] variable, so only a single batch of rows and a single
+// workout are held in memory at once. Physics data is only read if the
+// SkipPhysData option is false.
+//
+// The context must have a [types.State] variable.
+//
+// No changes will be made to the database.
+func StreamWorkoutsInDateRange(
+	ctxt context.Context,
+	clientEmail string,
+	start time.Time,
+	end time.Time,
+	opts types.StreamWorkoutsOpts,
+) iter.Seq2[types.Workout, error] {
+	return runStreamOp(
+		ctxt, dal.StreamWorkoutsInDateRange,
+		func(yield func(w types.Workout) bool) dal.StreamWorkoutsInDateRangeOpts {
+			return dal.StreamWorkoutsInDateRangeOpts{
+				Email:        clientEmail,
+				Start:        start,
+				End:          end,
+				SkipPhysData: opts.SkipPhysData,
+				Yield:        yield,
+			}
+		},
+	)
+}
+
+// Updates the supplied workouts in place, replacing the stored data with the
+// supplied data. The supplied workouts must already exist in the database and
+// must have the same number of exercises as the stored workouts. Exercises
+// cannot be added or removed because their position in the workout is part of
+// their identity. The supplied workouts are expected to be a modified version
+// of the workouts returned by [ReadWorkoutsById].
+//
+// Each exercise must satisfy the same constraints outlined by
+// [CreateWorkouts]. The PhysData of each exercise must either be empty or have
+// exactly one entry per set, ceil(Sets) entries, because physics data is
+// matched to its set by its index.
+//
+// Only the exercises that changed are updated. Physics data is only
+// recalculated for sets where:
+//
+//   - Physics data was added
+//   - The bar path input (time, position, or video path) changed
+//   - The hyperparameter versions changed
+//   - The weight or expected number of reps for the set changed
+//
+// Physics data for all other sets is left untouched. When physics data is
+// recalculated the hyperparameters are selected using the versions in the
+// supplied physics data. Sets that no longer have physics data present will
+// have their physics data deleted.
+//
+// The context must have a [types.State] variable.
+//
+// If any error occurs no changes will be made to the database.
+func UpdateWorkouts(
+	ctxt context.Context,
+	workouts ...types.Workout,
+) (opErr error) {
+	if len(workouts) == 0 {
+		return
+	}
+	return runOp(ctxt, jobs.UpdateWorkouts, workouts)
+}
+
 // Deletes the workout data associated with the supplied ids if they exist. If
 // they do not exist an error will be returned.
 //
@@ -10,6 +10,12 @@ type (
 	// Global settings that configure many parts of providentia's behavior.
 	GlobalConf struct {
 		BatchSize uint
+		// The percent of velocity lost from the best rep of a set that marks
+		// the threshold rep of the set. Refer to [SetFatigue].
+		VelocityLossThreshold uint
+		// The formula used to calculate the estimated max of every exercise
+		// read from the database. Refer to [ExerciseData].
+		EstimatedMaxFormula EstimatedMaxFormula
 	}
 
 	// Holds all configuration data for the library. Used to define the state of
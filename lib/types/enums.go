@@ -18,6 +18,7 @@ type (
 	//	UnknownModel,
 	//	BarPathTracker,
 	//	BarPathCalc,
+	//	FitnessFatigue,
 	// )
 	ModelID int32
 
@@ -32,4 +33,31 @@ type (
 
 	// ENUM(NoBarPathData, VideoBarPathData, TimeSeriesBarPathData)
 	BarPathFlag int
+
+	// ENUM(Daily, Weekly, Monthly)
+	TrainingGranularity int32
+
+	// ENUM(ByExercise, ByExerciseFocus, ByExerciseKind)
+	TrainingGroupBy int32
+
+	// ENUM(VolumeLoad, ExertionLoad)
+	WorkloadMetric int32
+
+	// ENUM(RollingAverage, EWMA)
+	ACWRMethod int32
+
+	// ENUM(HeaviestWeight, BestEstimatedMax, BestVolumeSet, FastestPeakVel)
+	PersonalRecordKind int32
+
+	// ENUM(Epley, Brzycki, RPETable)
+	EstimatedMaxFormula int32
+
+	// ENUM(UnknownSex, Male, Female)
+	Sex int32
+
+	// ENUM(AbortOnError, SkipRejectedRows)
+	UploadErrorPolicy int32
+
+	// ENUM(FileDiscovered, ChunkScheduled, ChunkCompleted, RowsUploaded, PhysicsJobFinished)
+	ProgressEventKind int32
 )
@@ -522,16 +522,19 @@ const (
 	BarPathTracker
 	// BarPathCalc is a ModelID of type BarPathCalc.
 	BarPathCalc
+	// FitnessFatigue is a ModelID of type FitnessFatigue.
+	FitnessFatigue
 )
 
 var ErrInvalidModelID = fmt.Errorf("not a valid ModelID, try [%s]", strings.Join(_ModelIDNames, ", "))
 
-const _ModelIDName = "UnknownModelBarPathTrackerBarPathCalc"
+const _ModelIDName = "UnknownModelBarPathTrackerBarPathCalcFitnessFatigue"
 
 var _ModelIDNames = []string{
 	_ModelIDName[0:12],
 	_ModelIDName[12:26],
 	_ModelIDName[26:37],
+	_ModelIDName[37:51],
 }
 
 // ModelIDNames returns a list of possible string values of ModelID.
@@ -547,6 +550,7 @@ func ModelIDValues() []ModelID {
 		UnknownModel,
 		BarPathTracker,
 		BarPathCalc,
+		FitnessFatigue,
 	}
 }
 
@@ -554,6 +558,7 @@ var _ModelIDMap = map[ModelID]string{
 	UnknownModel:   _ModelIDName[0:12],
 	BarPathTracker: _ModelIDName[12:26],
 	BarPathCalc:    _ModelIDName[26:37],
+	FitnessFatigue: _ModelIDName[37:51],
 }
 
 // String implements the Stringer interface.
@@ -578,6 +583,8 @@ var _ModelIDValue = map[string]ModelID{
 	strings.ToLower(_ModelIDName[12:26]): BarPathTracker,
 	_ModelIDName[26:37]:                  BarPathCalc,
 	strings.ToLower(_ModelIDName[26:37]): BarPathCalc,
+	_ModelIDName[37:51]:                  FitnessFatigue,
+	strings.ToLower(_ModelIDName[37:51]): FitnessFatigue,
 }
 
 // ParseModelID attempts to convert a string to a ModelID.
@@ -615,3 +622,912 @@ func (x *ModelID) UnmarshalText(text []byte) error {
 func (x *ModelID) AppendText(b []byte) ([]byte, error) {
 	return append(b, x.String()...), nil
 }
+
+const (
+	// Daily is a TrainingGranularity of type Daily.
+	Daily TrainingGranularity = iota
+	// Weekly is a TrainingGranularity of type Weekly.
+	Weekly
+	// Monthly is a TrainingGranularity of type Monthly.
+	Monthly
+)
+
+var ErrInvalidTrainingGranularity = fmt.Errorf("not a valid TrainingGranularity, try [%s]", strings.Join(_TrainingGranularityNames, ", "))
+
+const _TrainingGranularityName = "DailyWeeklyMonthly"
+
+var _TrainingGranularityNames = []string{
+	_TrainingGranularityName[0:5],
+	_TrainingGranularityName[5:11],
+	_TrainingGranularityName[11:18],
+}
+
+// TrainingGranularityNames returns a list of possible string values of TrainingGranularity.
+func TrainingGranularityNames() []string {
+	tmp := make([]string, len(_TrainingGranularityNames))
+	copy(tmp, _TrainingGranularityNames)
+	return tmp
+}
+
+// TrainingGranularityValues returns a list of the values for TrainingGranularity
+func TrainingGranularityValues() []TrainingGranularity {
+	return []TrainingGranularity{
+		Daily,
+		Weekly,
+		Monthly,
+	}
+}
+
+var _TrainingGranularityMap = map[TrainingGranularity]string{
+	Daily:   _TrainingGranularityName[0:5],
+	Weekly:  _TrainingGranularityName[5:11],
+	Monthly: _TrainingGranularityName[11:18],
+}
+
+// String implements the Stringer interface.
+func (x TrainingGranularity) String() string {
+	if str, ok := _TrainingGranularityMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("TrainingGranularity(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x TrainingGranularity) IsValid() bool {
+	_, ok := _TrainingGranularityMap[x]
+	return ok
+}
+
+var _TrainingGranularityValue = map[string]TrainingGranularity{
+	_TrainingGranularityName[0:5]:                    Daily,
+	strings.ToLower(_TrainingGranularityName[0:5]):   Daily,
+	_TrainingGranularityName[5:11]:                   Weekly,
+	strings.ToLower(_TrainingGranularityName[5:11]):  Weekly,
+	_TrainingGranularityName[11:18]:                  Monthly,
+	strings.ToLower(_TrainingGranularityName[11:18]): Monthly,
+}
+
+// ParseTrainingGranularity attempts to convert a string to a TrainingGranularity.
+func ParseTrainingGranularity(name string) (TrainingGranularity, error) {
+	if x, ok := _TrainingGranularityValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _TrainingGranularityValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return TrainingGranularity(0), fmt.Errorf("%s is %w", name, ErrInvalidTrainingGranularity)
+}
+
+// MarshalText implements the text marshaller method.
+func (x TrainingGranularity) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *TrainingGranularity) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseTrainingGranularity(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *TrainingGranularity) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
+
+const (
+	// ByExercise is a TrainingGroupBy of type ByExercise.
+	ByExercise TrainingGroupBy = iota
+	// ByExerciseFocus is a TrainingGroupBy of type ByExerciseFocus.
+	ByExerciseFocus
+	// ByExerciseKind is a TrainingGroupBy of type ByExerciseKind.
+	ByExerciseKind
+)
+
+var ErrInvalidTrainingGroupBy = fmt.Errorf("not a valid TrainingGroupBy, try [%s]", strings.Join(_TrainingGroupByNames, ", "))
+
+const _TrainingGroupByName = "ByExerciseByExerciseFocusByExerciseKind"
+
+var _TrainingGroupByNames = []string{
+	_TrainingGroupByName[0:10],
+	_TrainingGroupByName[10:25],
+	_TrainingGroupByName[25:39],
+}
+
+// TrainingGroupByNames returns a list of possible string values of TrainingGroupBy.
+func TrainingGroupByNames() []string {
+	tmp := make([]string, len(_TrainingGroupByNames))
+	copy(tmp, _TrainingGroupByNames)
+	return tmp
+}
+
+// TrainingGroupByValues returns a list of the values for TrainingGroupBy
+func TrainingGroupByValues() []TrainingGroupBy {
+	return []TrainingGroupBy{
+		ByExercise,
+		ByExerciseFocus,
+		ByExerciseKind,
+	}
+}
+
+var _TrainingGroupByMap = map[TrainingGroupBy]string{
+	ByExercise:      _TrainingGroupByName[0:10],
+	ByExerciseFocus: _TrainingGroupByName[10:25],
+	ByExerciseKind:  _TrainingGroupByName[25:39],
+}
+
+// String implements the Stringer interface.
+func (x TrainingGroupBy) String() string {
+	if str, ok := _TrainingGroupByMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("TrainingGroupBy(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x TrainingGroupBy) IsValid() bool {
+	_, ok := _TrainingGroupByMap[x]
+	return ok
+}
+
+var _TrainingGroupByValue = map[string]TrainingGroupBy{
+	_TrainingGroupByName[0:10]:                   ByExercise,
+	strings.ToLower(_TrainingGroupByName[0:10]):  ByExercise,
+	_TrainingGroupByName[10:25]:                  ByExerciseFocus,
+	strings.ToLower(_TrainingGroupByName[10:25]): ByExerciseFocus,
+	_TrainingGroupByName[25:39]:                  ByExerciseKind,
+	strings.ToLower(_TrainingGroupByName[25:39]): ByExerciseKind,
+}
+
+// ParseTrainingGroupBy attempts to convert a string to a TrainingGroupBy.
+func ParseTrainingGroupBy(name string) (TrainingGroupBy, error) {
+	if x, ok := _TrainingGroupByValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _TrainingGroupByValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return TrainingGroupBy(0), fmt.Errorf("%s is %w", name, ErrInvalidTrainingGroupBy)
+}
+
+// MarshalText implements the text marshaller method.
+func (x TrainingGroupBy) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *TrainingGroupBy) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseTrainingGroupBy(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *TrainingGroupBy) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
+
+const (
+	// VolumeLoad is a WorkloadMetric of type VolumeLoad.
+	VolumeLoad WorkloadMetric = iota
+	// ExertionLoad is a WorkloadMetric of type ExertionLoad.
+	ExertionLoad
+)
+
+var ErrInvalidWorkloadMetric = fmt.Errorf("not a valid WorkloadMetric, try [%s]", strings.Join(_WorkloadMetricNames, ", "))
+
+const _WorkloadMetricName = "VolumeLoadExertionLoad"
+
+var _WorkloadMetricNames = []string{
+	_WorkloadMetricName[0:10],
+	_WorkloadMetricName[10:22],
+}
+
+// WorkloadMetricNames returns a list of possible string values of WorkloadMetric.
+func WorkloadMetricNames() []string {
+	tmp := make([]string, len(_WorkloadMetricNames))
+	copy(tmp, _WorkloadMetricNames)
+	return tmp
+}
+
+// WorkloadMetricValues returns a list of the values for WorkloadMetric
+func WorkloadMetricValues() []WorkloadMetric {
+	return []WorkloadMetric{
+		VolumeLoad,
+		ExertionLoad,
+	}
+}
+
+var _WorkloadMetricMap = map[WorkloadMetric]string{
+	VolumeLoad:   _WorkloadMetricName[0:10],
+	ExertionLoad: _WorkloadMetricName[10:22],
+}
+
+// String implements the Stringer interface.
+func (x WorkloadMetric) String() string {
+	if str, ok := _WorkloadMetricMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("WorkloadMetric(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x WorkloadMetric) IsValid() bool {
+	_, ok := _WorkloadMetricMap[x]
+	return ok
+}
+
+var _WorkloadMetricValue = map[string]WorkloadMetric{
+	_WorkloadMetricName[0:10]:                   VolumeLoad,
+	strings.ToLower(_WorkloadMetricName[0:10]):  VolumeLoad,
+	_WorkloadMetricName[10:22]:                  ExertionLoad,
+	strings.ToLower(_WorkloadMetricName[10:22]): ExertionLoad,
+}
+
+// ParseWorkloadMetric attempts to convert a string to a WorkloadMetric.
+func ParseWorkloadMetric(name string) (WorkloadMetric, error) {
+	if x, ok := _WorkloadMetricValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _WorkloadMetricValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return WorkloadMetric(0), fmt.Errorf("%s is %w", name, ErrInvalidWorkloadMetric)
+}
+
+// MarshalText implements the text marshaller method.
+func (x WorkloadMetric) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *WorkloadMetric) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseWorkloadMetric(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *WorkloadMetric) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
+
+const (
+	// RollingAverage is a ACWRMethod of type RollingAverage.
+	RollingAverage ACWRMethod = iota
+	// EWMA is a ACWRMethod of type EWMA.
+	EWMA
+)
+
+var ErrInvalidACWRMethod = fmt.Errorf("not a valid ACWRMethod, try [%s]", strings.Join(_ACWRMethodNames, ", "))
+
+const _ACWRMethodName = "RollingAverageEWMA"
+
+var _ACWRMethodNames = []string{
+	_ACWRMethodName[0:14],
+	_ACWRMethodName[14:18],
+}
+
+// ACWRMethodNames returns a list of possible string values of ACWRMethod.
+func ACWRMethodNames() []string {
+	tmp := make([]string, len(_ACWRMethodNames))
+	copy(tmp, _ACWRMethodNames)
+	return tmp
+}
+
+// ACWRMethodValues returns a list of the values for ACWRMethod
+func ACWRMethodValues() []ACWRMethod {
+	return []ACWRMethod{
+		RollingAverage,
+		EWMA,
+	}
+}
+
+var _ACWRMethodMap = map[ACWRMethod]string{
+	RollingAverage: _ACWRMethodName[0:14],
+	EWMA:           _ACWRMethodName[14:18],
+}
+
+// String implements the Stringer interface.
+func (x ACWRMethod) String() string {
+	if str, ok := _ACWRMethodMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("ACWRMethod(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x ACWRMethod) IsValid() bool {
+	_, ok := _ACWRMethodMap[x]
+	return ok
+}
+
+var _ACWRMethodValue = map[string]ACWRMethod{
+	_ACWRMethodName[0:14]:                   RollingAverage,
+	strings.ToLower(_ACWRMethodName[0:14]):  RollingAverage,
+	_ACWRMethodName[14:18]:                  EWMA,
+	strings.ToLower(_ACWRMethodName[14:18]): EWMA,
+}
+
+// ParseACWRMethod attempts to convert a string to a ACWRMethod.
+func ParseACWRMethod(name string) (ACWRMethod, error) {
+	if x, ok := _ACWRMethodValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _ACWRMethodValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return ACWRMethod(0), fmt.Errorf("%s is %w", name, ErrInvalidACWRMethod)
+}
+
+// MarshalText implements the text marshaller method.
+func (x ACWRMethod) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *ACWRMethod) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseACWRMethod(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *ACWRMethod) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
+
+const (
+	// HeaviestWeight is a PersonalRecordKind of type HeaviestWeight.
+	HeaviestWeight PersonalRecordKind = iota
+	// BestEstimatedMax is a PersonalRecordKind of type BestEstimatedMax.
+	BestEstimatedMax
+	// BestVolumeSet is a PersonalRecordKind of type BestVolumeSet.
+	BestVolumeSet
+	// FastestPeakVel is a PersonalRecordKind of type FastestPeakVel.
+	FastestPeakVel
+)
+
+var ErrInvalidPersonalRecordKind = fmt.Errorf("not a valid PersonalRecordKind, try [%s]", strings.Join(_PersonalRecordKindNames, ", "))
+
+const _PersonalRecordKindName = "HeaviestWeightBestEstimatedMaxBestVolumeSetFastestPeakVel"
+
+var _PersonalRecordKindNames = []string{
+	_PersonalRecordKindName[0:14],
+	_PersonalRecordKindName[14:30],
+	_PersonalRecordKindName[30:43],
+	_PersonalRecordKindName[43:57],
+}
+
+// PersonalRecordKindNames returns a list of possible string values of PersonalRecordKind.
+func PersonalRecordKindNames() []string {
+	tmp := make([]string, len(_PersonalRecordKindNames))
+	copy(tmp, _PersonalRecordKindNames)
+	return tmp
+}
+
+// PersonalRecordKindValues returns a list of the values for PersonalRecordKind
+func PersonalRecordKindValues() []PersonalRecordKind {
+	return []PersonalRecordKind{
+		HeaviestWeight,
+		BestEstimatedMax,
+		BestVolumeSet,
+		FastestPeakVel,
+	}
+}
+
+var _PersonalRecordKindMap = map[PersonalRecordKind]string{
+	HeaviestWeight:   _PersonalRecordKindName[0:14],
+	BestEstimatedMax: _PersonalRecordKindName[14:30],
+	BestVolumeSet:    _PersonalRecordKindName[30:43],
+	FastestPeakVel:   _PersonalRecordKindName[43:57],
+}
+
+// String implements the Stringer interface.
+func (x PersonalRecordKind) String() string {
+	if str, ok := _PersonalRecordKindMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("PersonalRecordKind(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x PersonalRecordKind) IsValid() bool {
+	_, ok := _PersonalRecordKindMap[x]
+	return ok
+}
+
+var _PersonalRecordKindValue = map[string]PersonalRecordKind{
+	_PersonalRecordKindName[0:14]:                   HeaviestWeight,
+	strings.ToLower(_PersonalRecordKindName[0:14]):  HeaviestWeight,
+	_PersonalRecordKindName[14:30]:                  BestEstimatedMax,
+	strings.ToLower(_PersonalRecordKindName[14:30]): BestEstimatedMax,
+	_PersonalRecordKindName[30:43]:                  BestVolumeSet,
+	strings.ToLower(_PersonalRecordKindName[30:43]): BestVolumeSet,
+	_PersonalRecordKindName[43:57]:                  FastestPeakVel,
+	strings.ToLower(_PersonalRecordKindName[43:57]): FastestPeakVel,
+}
+
+// ParsePersonalRecordKind attempts to convert a string to a PersonalRecordKind.
+func ParsePersonalRecordKind(name string) (PersonalRecordKind, error) {
+	if x, ok := _PersonalRecordKindValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _PersonalRecordKindValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return PersonalRecordKind(0), fmt.Errorf("%s is %w", name, ErrInvalidPersonalRecordKind)
+}
+
+// MarshalText implements the text marshaller method.
+func (x PersonalRecordKind) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *PersonalRecordKind) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParsePersonalRecordKind(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *PersonalRecordKind) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
+
+const (
+	// Epley is a EstimatedMaxFormula of type Epley.
+	Epley EstimatedMaxFormula = iota
+	// Brzycki is a EstimatedMaxFormula of type Brzycki.
+	Brzycki
+	// RPETable is a EstimatedMaxFormula of type RPETable.
+	RPETable
+)
+
+var ErrInvalidEstimatedMaxFormula = fmt.Errorf("not a valid EstimatedMaxFormula, try [%s]", strings.Join(_EstimatedMaxFormulaNames, ", "))
+
+const _EstimatedMaxFormulaName = "EpleyBrzyckiRPETable"
+
+var _EstimatedMaxFormulaNames = []string{
+	_EstimatedMaxFormulaName[0:5],
+	_EstimatedMaxFormulaName[5:12],
+	_EstimatedMaxFormulaName[12:20],
+}
+
+// EstimatedMaxFormulaNames returns a list of possible string values of EstimatedMaxFormula.
+func EstimatedMaxFormulaNames() []string {
+	tmp := make([]string, len(_EstimatedMaxFormulaNames))
+	copy(tmp, _EstimatedMaxFormulaNames)
+	return tmp
+}
+
+// EstimatedMaxFormulaValues returns a list of the values for EstimatedMaxFormula
+func EstimatedMaxFormulaValues() []EstimatedMaxFormula {
+	return []EstimatedMaxFormula{
+		Epley,
+		Brzycki,
+		RPETable,
+	}
+}
+
+var _EstimatedMaxFormulaMap = map[EstimatedMaxFormula]string{
+	Epley:    _EstimatedMaxFormulaName[0:5],
+	Brzycki:  _EstimatedMaxFormulaName[5:12],
+	RPETable: _EstimatedMaxFormulaName[12:20],
+}
+
+// String implements the Stringer interface.
+func (x EstimatedMaxFormula) String() string {
+	if str, ok := _EstimatedMaxFormulaMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("EstimatedMaxFormula(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x EstimatedMaxFormula) IsValid() bool {
+	_, ok := _EstimatedMaxFormulaMap[x]
+	return ok
+}
+
+var _EstimatedMaxFormulaValue = map[string]EstimatedMaxFormula{
+	_EstimatedMaxFormulaName[0:5]:                    Epley,
+	strings.ToLower(_EstimatedMaxFormulaName[0:5]):   Epley,
+	_EstimatedMaxFormulaName[5:12]:                   Brzycki,
+	strings.ToLower(_EstimatedMaxFormulaName[5:12]):  Brzycki,
+	_EstimatedMaxFormulaName[12:20]:                  RPETable,
+	strings.ToLower(_EstimatedMaxFormulaName[12:20]): RPETable,
+}
+
+// ParseEstimatedMaxFormula attempts to convert a string to a EstimatedMaxFormula.
+func ParseEstimatedMaxFormula(name string) (EstimatedMaxFormula, error) {
+	if x, ok := _EstimatedMaxFormulaValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _EstimatedMaxFormulaValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return EstimatedMaxFormula(0), fmt.Errorf("%s is %w", name, ErrInvalidEstimatedMaxFormula)
+}
+
+// MarshalText implements the text marshaller method.
+func (x EstimatedMaxFormula) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *EstimatedMaxFormula) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseEstimatedMaxFormula(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *EstimatedMaxFormula) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
+
+const (
+	// UnknownSex is a Sex of type UnknownSex.
+	UnknownSex Sex = iota
+	// Male is a Sex of type Male.
+	Male
+	// Female is a Sex of type Female.
+	Female
+)
+
+var ErrInvalidSex = fmt.Errorf("not a valid Sex, try [%s]", strings.Join(_SexNames, ", "))
+
+const _SexName = "UnknownSexMaleFemale"
+
+var _SexNames = []string{
+	_SexName[0:10],
+	_SexName[10:14],
+	_SexName[14:20],
+}
+
+// SexNames returns a list of possible string values of Sex.
+func SexNames() []string {
+	tmp := make([]string, len(_SexNames))
+	copy(tmp, _SexNames)
+	return tmp
+}
+
+// SexValues returns a list of the values for Sex
+func SexValues() []Sex {
+	return []Sex{
+		UnknownSex,
+		Male,
+		Female,
+	}
+}
+
+var _SexMap = map[Sex]string{
+	UnknownSex: _SexName[0:10],
+	Male:       _SexName[10:14],
+	Female:     _SexName[14:20],
+}
+
+// String implements the Stringer interface.
+func (x Sex) String() string {
+	if str, ok := _SexMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("Sex(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x Sex) IsValid() bool {
+	_, ok := _SexMap[x]
+	return ok
+}
+
+var _SexValue = map[string]Sex{
+	_SexName[0:10]:                   UnknownSex,
+	strings.ToLower(_SexName[0:10]):  UnknownSex,
+	_SexName[10:14]:                  Male,
+	strings.ToLower(_SexName[10:14]): Male,
+	_SexName[14:20]:                  Female,
+	strings.ToLower(_SexName[14:20]): Female,
+}
+
+// ParseSex attempts to convert a string to a Sex.
+func ParseSex(name string) (Sex, error) {
+	if x, ok := _SexValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _SexValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return Sex(0), fmt.Errorf("%s is %w", name, ErrInvalidSex)
+}
+
+// MarshalText implements the text marshaller method.
+func (x Sex) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *Sex) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseSex(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *Sex) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
+
+const (
+	// AbortOnError is a UploadErrorPolicy of type AbortOnError.
+	AbortOnError UploadErrorPolicy = iota
+	// SkipRejectedRows is a UploadErrorPolicy of type SkipRejectedRows.
+	SkipRejectedRows
+)
+
+var ErrInvalidUploadErrorPolicy = fmt.Errorf("not a valid UploadErrorPolicy, try [%s]", strings.Join(_UploadErrorPolicyNames, ", "))
+
+const _UploadErrorPolicyName = "AbortOnErrorSkipRejectedRows"
+
+var _UploadErrorPolicyNames = []string{
+	_UploadErrorPolicyName[0:12],
+	_UploadErrorPolicyName[12:28],
+}
+
+// UploadErrorPolicyNames returns a list of possible string values of UploadErrorPolicy.
+func UploadErrorPolicyNames() []string {
+	tmp := make([]string, len(_UploadErrorPolicyNames))
+	copy(tmp, _UploadErrorPolicyNames)
+	return tmp
+}
+
+// UploadErrorPolicyValues returns a list of the values for UploadErrorPolicy
+func UploadErrorPolicyValues() []UploadErrorPolicy {
+	return []UploadErrorPolicy{
+		AbortOnError,
+		SkipRejectedRows,
+	}
+}
+
+var _UploadErrorPolicyMap = map[UploadErrorPolicy]string{
+	AbortOnError:     _UploadErrorPolicyName[0:12],
+	SkipRejectedRows: _UploadErrorPolicyName[12:28],
+}
+
+// String implements the Stringer interface.
+func (x UploadErrorPolicy) String() string {
+	if str, ok := _UploadErrorPolicyMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("UploadErrorPolicy(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x UploadErrorPolicy) IsValid() bool {
+	_, ok := _UploadErrorPolicyMap[x]
+	return ok
+}
+
+var _UploadErrorPolicyValue = map[string]UploadErrorPolicy{
+	_UploadErrorPolicyName[0:12]:                   AbortOnError,
+	strings.ToLower(_UploadErrorPolicyName[0:12]):  AbortOnError,
+	_UploadErrorPolicyName[12:28]:                  SkipRejectedRows,
+	strings.ToLower(_UploadErrorPolicyName[12:28]): SkipRejectedRows,
+}
+
+// ParseUploadErrorPolicy attempts to convert a string to a UploadErrorPolicy.
+func ParseUploadErrorPolicy(name string) (UploadErrorPolicy, error) {
+	if x, ok := _UploadErrorPolicyValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _UploadErrorPolicyValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return UploadErrorPolicy(0), fmt.Errorf("%s is %w", name, ErrInvalidUploadErrorPolicy)
+}
+
+// MarshalText implements the text marshaller method.
+func (x UploadErrorPolicy) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *UploadErrorPolicy) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseUploadErrorPolicy(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *UploadErrorPolicy) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
+
+const (
+	// FileDiscovered is a ProgressEventKind of type FileDiscovered.
+	FileDiscovered ProgressEventKind = iota
+	// ChunkScheduled is a ProgressEventKind of type ChunkScheduled.
+	ChunkScheduled
+	// ChunkCompleted is a ProgressEventKind of type ChunkCompleted.
+	ChunkCompleted
+	// RowsUploaded is a ProgressEventKind of type RowsUploaded.
+	RowsUploaded
+	// PhysicsJobFinished is a ProgressEventKind of type PhysicsJobFinished.
+	PhysicsJobFinished
+)
+
+var ErrInvalidProgressEventKind = fmt.Errorf("not a valid ProgressEventKind, try [%s]", strings.Join(_ProgressEventKindNames, ", "))
+
+const _ProgressEventKindName = "FileDiscoveredChunkScheduledChunkCompletedRowsUploadedPhysicsJobFinished"
+
+var _ProgressEventKindNames = []string{
+	_ProgressEventKindName[0:14],
+	_ProgressEventKindName[14:28],
+	_ProgressEventKindName[28:42],
+	_ProgressEventKindName[42:54],
+	_ProgressEventKindName[54:72],
+}
+
+// ProgressEventKindNames returns a list of possible string values of ProgressEventKind.
+func ProgressEventKindNames() []string {
+	tmp := make([]string, len(_ProgressEventKindNames))
+	copy(tmp, _ProgressEventKindNames)
+	return tmp
+}
+
+// ProgressEventKindValues returns a list of the values for ProgressEventKind
+func ProgressEventKindValues() []ProgressEventKind {
+	return []ProgressEventKind{
+		FileDiscovered,
+		ChunkScheduled,
+		ChunkCompleted,
+		RowsUploaded,
+		PhysicsJobFinished,
+	}
+}
+
+var _ProgressEventKindMap = map[ProgressEventKind]string{
+	FileDiscovered:     _ProgressEventKindName[0:14],
+	ChunkScheduled:     _ProgressEventKindName[14:28],
+	ChunkCompleted:     _ProgressEventKindName[28:42],
+	RowsUploaded:       _ProgressEventKindName[42:54],
+	PhysicsJobFinished: _ProgressEventKindName[54:72],
+}
+
+// String implements the Stringer interface.
+func (x ProgressEventKind) String() string {
+	if str, ok := _ProgressEventKindMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("ProgressEventKind(%d)", x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x ProgressEventKind) IsValid() bool {
+	_, ok := _ProgressEventKindMap[x]
+	return ok
+}
+
+var _ProgressEventKindValue = map[string]ProgressEventKind{
+	_ProgressEventKindName[0:14]:                   FileDiscovered,
+	strings.ToLower(_ProgressEventKindName[0:14]):  FileDiscovered,
+	_ProgressEventKindName[14:28]:                  ChunkScheduled,
+	strings.ToLower(_ProgressEventKindName[14:28]): ChunkScheduled,
+	_ProgressEventKindName[28:42]:                  ChunkCompleted,
+	strings.ToLower(_ProgressEventKindName[28:42]): ChunkCompleted,
+	_ProgressEventKindName[42:54]:                  RowsUploaded,
+	strings.ToLower(_ProgressEventKindName[42:54]): RowsUploaded,
+	_ProgressEventKindName[54:72]:                  PhysicsJobFinished,
+	strings.ToLower(_ProgressEventKindName[54:72]): PhysicsJobFinished,
+}
+
+// ParseProgressEventKind attempts to convert a string to a ProgressEventKind.
+func ParseProgressEventKind(name string) (ProgressEventKind, error) {
+	if x, ok := _ProgressEventKindValue[name]; ok {
+		return x, nil
+	}
+	// Case insensitive parse, do a separate lookup to prevent unnecessary cost of lowercasing a string if we don't need to.
+	if x, ok := _ProgressEventKindValue[strings.ToLower(name)]; ok {
+		return x, nil
+	}
+	return ProgressEventKind(0), fmt.Errorf("%s is %w", name, ErrInvalidProgressEventKind)
+}
+
+// MarshalText implements the text marshaller method.
+func (x ProgressEventKind) MarshalText() ([]byte, error) {
+	return []byte(x.String()), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *ProgressEventKind) UnmarshalText(text []byte) error {
+	name := string(text)
+	tmp, err := ParseProgressEventKind(name)
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+// AppendText appends the textual representation of itself to the end of b
+// (allocating a larger slice if necessary) and returns the updated slice.
+//
+// Implementations must not retain b, nor mutate any bytes within b[:len(b)].
+func (x *ProgressEventKind) AppendText(b []byte) ([]byte, error) {
+	return append(b, x.String()...), nil
+}
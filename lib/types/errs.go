@@ -6,8 +6,10 @@ import "errors"
 var (
 	InvalidCtxtErr = errors.New("Invalid context")
 
-	InvalidGlobalConfErr = errors.New("Invalid global conf")
-	InvalidBatchSizeErr  = errors.New("Invalid batch size")
+	InvalidGlobalConfErr            = errors.New("Invalid global conf")
+	InvalidBatchSizeErr             = errors.New("Invalid batch size")
+	InvalidVelocityLossThresholdErr = errors.New("Invalid velocity loss threshold")
+	InvalidEstimatedMaxFormulaErr   = errors.New("Invalid estimated max formula")
 
 	InvalidLoggerErr            = errors.New("Invalid logger")
 	InvalidDBErr                = errors.New("Invalid database connection pool")
@@ -17,6 +19,13 @@ var (
 	InvalidGPJobQueueErr        = errors.New("Invalid general purpose loader job queue")
 )
 
+// Cause errors. These are appended to the error of an operation to describe
+// why it failed, i.e. a read of a client that does not exist returns an error
+// that is both [CouldNotReadAllClientsErr] and [NotFoundErr].
+var (
+	NotFoundErr = errors.New("Entry not found")
+)
+
 // [ExerciseFocus] errors
 var (
 	CouldNotCreateAllExerciseFocusEntriesErr = errors.New("Could not create all exercise focus entries")
@@ -38,6 +47,18 @@ var (
 	CouldNotReadAllClientsErr   = errors.New("Could not read all clients")
 	CouldNotUpdateAllClientsErr = errors.New("Could not update all clients")
 	CouldNotDeleteAllClientsErr = errors.New("Could not delete all clients")
+
+	CouldNotSetAllClientAttributesErr  = errors.New("Could not set all client attributes")
+	CouldNotReadAllClientAttributesErr = errors.New("Could not read all client attributes")
+	InvalidSexErr                      = errors.New("Invalid sex")
+)
+
+// Bodyweight errors
+var (
+	CouldNotCreateAllBodyweightsErr = errors.New("Could not create all bodyweight entries")
+	CouldNotReadAllBodyweightsErr   = errors.New("Could not read all bodyweight entries")
+	CouldNotUpdateAllBodyweightsErr = errors.New("Could not update all bodyweight entries")
+	CouldNotDeleteAllBodyweightsErr = errors.New("Could not delete all bodyweight entries")
 )
 
 // [Exercise] errors
@@ -56,8 +77,11 @@ var (
 	CouldNotDeleteAllWorkoutsErr = errors.New("Could not delete all workouts")
 
 	CouldNotCreateAllPhysicsDataErr                    = errors.New("Could not create all physics data entries")
+	CouldNotReadAllPhysicsDataErr                      = errors.New("Could not read all physics data entries")
 	CouldNotDeleteAllPhysicsDataErr                    = errors.New("Could not delete all physics data entries")
 	CouldNotCreateAllTrainingLogsErr                   = errors.New("Could not create all training log entries")
+	CouldNotReadAllTrainingLogsErr                     = errors.New("Could not read all training log entries")
+	CouldNotUpdateAllTrainingLogsErr                   = errors.New("Could not update all training log entries")
 	CouldNotDeleteAllTrainingLogsErr                   = errors.New("Could not delete all training log entries")
 	CouldNotCreateAllTrainingLogPhysicsDataMappingsErr = errors.New("Could not create all training log to physics data mappings")
 )
@@ -74,12 +98,24 @@ var (
 	InvalidMinLengthErr      = errors.New("Invalid min length")
 	InvalidMaxFileSizeErr    = errors.New("Invalid max file size")
 
+	InvalidFitnessFatigueErr = errors.New("Invalid fitness fatigue conf")
+	InvalidTimeFrameErr      = errors.New("Invalid time frame")
+	InvalidDecayErr          = errors.New("Invalid decay")
+
 	CouldNotCreateAllHyperparamsErr = errors.New("Could not create all hyperparams")
 	CouldNotReadAllHyperparamsErr   = errors.New("Could not read all hyperparams")
 	CouldNotUpdateAllHyperparamsErr = errors.New("Could not update all hyperparams")
 	CouldNotDeleteAllHyperparamsErr = errors.New("Could not delete all hyperparams")
 )
 
+// Model state errors
+var (
+	CouldNotCreateAllModelStatesErr = errors.New("Could not create all model states")
+	CouldNotReadAllModelStatesErr   = errors.New("Could not read all model states")
+	CouldNotPredictWeightErr        = errors.New("Could not predict weight")
+	NoModelStateErr                 = errors.New("No fitted model state")
+)
+
 // CSV loader job queue errors
 var (
 	CSVLoaderJobQueueErr = errors.New("Could not process csv loader job")
@@ -92,6 +128,62 @@ var (
 	InvalidExpNumRepsErr      = errors.New("Invalid exp num reps")
 	TimeSeriesDecreaseErr     = errors.New("Time series data must not decrease")
 	TimeSeriesNotMonotonicErr = errors.New("Time series must increase mononically")
+	NonFinitePhysicsDataErr   = errors.New("Physics data must be finite")
+	InvalidRepSplitErr        = errors.New("Invalid rep split")
+)
+
+// Video job queue errors
+var (
+	VideoJobQueueErr         = errors.New("Could not process video job")
+	InvalidVideoFileSizeErr  = errors.New("Invalid video file size")
+	VideoTooShortErr         = errors.New("Video is too short")
+	CouldNotDecodeVideoErr   = errors.New("Could not decode video")
+	BarPathMarkerNotFoundErr = errors.New("Could not find bar path marker")
+)
+
+// Load velocity profile errors
+var (
+	CouldNotCalcLoadVelocityProfileErr = errors.New("Could not calculate load velocity profile")
+	NotEnoughVelocityDataErr           = errors.New("Not enough velocity data")
+	InvalidLoadVelocitySlopeErr        = errors.New("Invalid load velocity slope")
+)
+
+// Set fatigue errors
+var (
+	CouldNotReadAllSetFatigueErr = errors.New("Could not read all set fatigue data")
+)
+
+// Training aggregate errors
+var (
+	CouldNotAggregateTrainingErr  = errors.New("Could not aggregate training")
+	InvalidTrainingGranularityErr = errors.New("Invalid training granularity")
+	InvalidTrainingGroupByErr     = errors.New("Invalid training group by")
+)
+
+// Workload errors
+var (
+	CouldNotCalcACWRErr           = errors.New("Could not calculate acute chronic workload ratio")
+	CouldNotCalcMonotonyStrainErr = errors.New("Could not calculate training monotony and strain")
+	InvalidWorkloadMetricErr      = errors.New("Invalid workload metric")
+	InvalidACWRMethodErr          = errors.New("Invalid acute chronic workload ratio method")
+	InvalidExerciseFocusErr       = errors.New("Invalid exercise focus")
+)
+
+// Estimated max errors
+var (
+	CouldNotReadAllEstimatedMaxesErr = errors.New("Could not read all estimated maxes")
+)
+
+// Personal record errors
+var (
+	CouldNotReadAllPersonalRecordsErr = errors.New("Could not read all personal records")
+	CouldNotDetectPersonalRecordsErr  = errors.New("Could not detect personal records")
+)
+
+// Strength score errors
+var (
+	CouldNotCalcStrengthScoresErr = errors.New("Could not calculate strength scores")
+	NoBodyweightErr               = errors.New("No bodyweight entry")
 )
 
 // Bulk upload errors
@@ -99,4 +191,42 @@ var (
 	BulkDataUploadErr       = errors.New("Could not bulk upload data")
 	InvalidDataDirErr       = errors.New("Invalid data dir")
 	UnknownFileInDataDirErr = errors.New("Unknown file in data dir")
+
+	InvalidTimeSeriesCSVFormatErr = errors.New("Invalid time series csv format")
+	UnknownTimeSeriesCSVFormatErr = errors.New("Unknown time series csv format")
+
+	MalformedJSONLErr     = errors.New("Malformed jsonl")
+	CouldNotWriteJSONLErr = errors.New("Could not write jsonl")
+)
+
+// Import journal errors
+var (
+	CouldNotCreateImportJournalErr = errors.New("Could not create import journal entries")
+	CouldNotReadImportJournalErr   = errors.New("Could not read import journal entries")
+	CouldNotDeleteImportJournalErr = errors.New("Could not delete import journal entries")
+	ImportJournalMismatchErr       = errors.New("File does not match the import journal")
+)
+
+// Export errors
+var (
+	CouldNotExportWorkoutsErr = errors.New("Could not export workouts")
+)
+
+// Archive errors
+var (
+	CouldNotDumpErr              = errors.New("Could not dump database")
+	CouldNotRestoreErr           = errors.New("Could not restore database")
+	InvalidArchiveErr            = errors.New("Invalid archive")
+	UnsupportedArchiveVersionErr = errors.New("Unsupported archive version")
+)
+
+// Watch errors
+var (
+	CouldNotWatchWorkoutDirErr = errors.New("Could not watch workout dir")
+)
+
+// HTTP api errors
+var (
+	InvalidRequestErr  = errors.New("Invalid request")
+	RequestTooLargeErr = errors.New("Request body too large")
 )
@@ -0,0 +1,44 @@
+package types
+
+type (
+	// A single progress event sent by a long running job batch. Which fields
+	// are set depends on the kind of event:
+	//   - [FileDiscovered]: File
+	//   - [ChunkScheduled]: File, Job
+	//   - [ChunkCompleted]: File, Job, Err
+	//   - [RowsUploaded]: File, Job, NumRows
+	//   - [PhysicsJobFinished]: Job, Err
+	//
+	// The csv and JSON Lines loaders send every kind of event other than
+	// [PhysicsJobFinished], which is sent by the physics and video jobs. Dry
+	// runs do not send [RowsUploaded] because nothing is uploaded. Events are
+	// never sent while a job holds the transaction of its batch.
+	ProgressEvent struct {
+		Kind    ProgressEventKind
+		File    string // The data file the event applies to
+		Job     uint64 // The unique id of the job the event applies to
+		NumRows int    // The number of rows that were uploaded, each workout counts as one row
+		Err     error  // The error the job failed with, nil if it succeeded
+	}
+
+	// Receives the progress events of every job batch that is run with the
+	// state the sink is part of. Progress is called from many jobs at once so
+	// it must be safe for concurrent use, and it is called from the jobs
+	// themselves so it should return quickly.
+	ProgressSink interface {
+		Progress(e ProgressEvent)
+	}
+
+	// A [ProgressSink] that calls the function with every event.
+	ProgressFunc func(e ProgressEvent)
+
+	// A [ProgressSink] that sends every event on the channel. Sending blocks
+	// the job that sent the event, so the channel must be read from until the
+	// operation that is reporting progress returns or the jobs will stall. Use
+	// a buffered channel to avoid slowing the jobs down. The channel is never
+	// closed by providentia.
+	ProgressChan chan ProgressEvent
+)
+
+func (p ProgressFunc) Progress(e ProgressEvent) { p(e) }
+func (p ProgressChan) Progress(e ProgressEvent) { p <- e }
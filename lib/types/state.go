@@ -31,5 +31,12 @@ type (
 		WorkoutCSVFileChunks    sbcsv.ChunkFileOpts
 
 		Global GlobalConf
+
+		// Receives the progress events of bulk uploads, csv loaders, and
+		// physics calculations. Optional, no events are sent when nil. To
+		// report the progress of a single operation copy the state, set the
+		// sink, and put the copy in the context that is passed to the
+		// operation.
+		Progress ProgressSink
 	}
 )
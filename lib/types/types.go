@@ -11,7 +11,14 @@ type (
 	// The set of all available hyperparameters.
 	Hyperparams interface {
 		BarPathCalcHyperparams |
-			BarPathTrackerHyperparams
+			BarPathTrackerHyperparams |
+			FitnessFatigueHyperparams
+	}
+
+	// The set of all types that can be read from and written to JSON Lines
+	// files.
+	JSONLTypes interface {
+		Client | Exercise | Hyperparams | Workout
 	}
 
 	// Hyperparameters used by the algorithm that calculates physics data from
@@ -39,6 +46,16 @@ type (
 		MaxFileSize uint64
 	}
 
+	// Hyperparameters used by the model that fits a clients estimated max to
+	// the fitness and fatigue generated by their training.
+	FitnessFatigueHyperparams struct {
+		Version       int32
+		TimeFrame     uint64  // The number of days of training history to fit over
+		FitnessDecay  float64 // The time constant of fitness decay in days
+		FatigueDecay  float64 // The time constant of fatigue decay in days
+		MinNumSamples uint64  // The minimum number of entries needed to fit
+	}
+
 	// Represents a client from the database
 	Client struct {
 		FirstName string `db:"first_name"` // The first name of the client
@@ -46,6 +63,21 @@ type (
 		Email     string `db:"email"`      // The clients email
 	}
 
+	// Optional client attributes that are needed to normalize strength by
+	// bodyweight. Clients that never set their attributes have the zero value.
+	ClientAttributes struct {
+		Email string // The clients email
+		Sex   Sex    // Selects the coefficients used by the strength scores
+	}
+
+	// A single bodyweight measurement of a client. A client can have at most
+	// one measurement per day.
+	BodyweightEntry struct {
+		ClientEmail  string    // The clients email
+		DateMeasured time.Time // The day the bodyweight was measured on
+		Weight       Kilogram  // The measured bodyweight
+	}
+
 	// Represents an exercise from the database
 	Exercise struct {
 		Name    string        `db:"name"`     // The exercise name
@@ -61,6 +93,35 @@ const (
 	// The second file extension for a CSV file that holds bar path tracker data.
 	// The file is expected to follow the format: <file name>.barPathTracker.csv
 	BarPathTrackerFileExt = "barPathTracker"
+	// The second file extension for a CSV file that holds fitness fatigue data.
+	// The file is expected to follow the format: <file name>.fitnessFatigue.csv
+	FitnessFatigueFileExt = "fitnessFatigue"
+
+	// The layout dates are written with when exporting workouts. The
+	// TimeFormat of the [sbcsv.Opts] must be set to this layout when loading
+	// exported workouts.
+	ExportDateFormat = time.DateOnly
+	// The sub-dir of an export dir that workout csv files are written to. The
+	// files are expected to follow the format: <client email>.csv
+	ExportWorkoutDir = "workouts"
+	// The sub-dir of an export dir that the time series data of each set is
+	// written to. It is kept separate from [ExportWorkoutDir] because workout
+	// dirs cannot contain sub-dirs.
+	ExportPhysDataDir = "physData"
+
+	// The version of the archive format written by [logic.Dump]. Archives
+	// with a different version cannot be restored.
+	ArchiveVersion = 2
+
+	// The sub-dir of a watched dir that workout files, and their data dirs,
+	// are moved to once they are uploaded by [logic.WatchWorkoutDir].
+	WatchDoneDir = "done"
+	// The sub-dir of a watched dir that workout files, and their data dirs,
+	// are moved to when they cannot be uploaded by [logic.WatchWorkoutDir].
+	WatchFailedDir = "failed"
+	// The extension of the error report that is written next to each workout
+	// file that is moved to [WatchFailedDir]: <client email>.error.txt
+	WatchErrReportExt = ".error.txt"
 )
 
 var (
@@ -68,6 +129,7 @@ var (
 	HyperparamFileNames = map[string]struct{}{
 		BarPathCalcFileExt:    {},
 		BarPathTrackerFileExt: {},
+		FitnessFatigueFileExt: {},
 	}
 )
 
@@ -81,6 +143,18 @@ type (
 		PositionData []Vec2[Meter, Meter] // The position data for the set
 	}
 
+	// Describes a csv file format that holds the bar path as time series data.
+	// Formats are registered with [logic.RegisterTimeSeriesCSVFormat]. When
+	// loading a time series csv file the first registered format that has all
+	// of its columns present in the file is used to parse the file.
+	TimeSeriesCSVFormat struct {
+		Name string   // The unique name of the format
+		Cols []string // The column names the format needs from the file
+		// Parses a single row of the file. The supplied values are in the
+		// same order as Cols. The returned position must be in meters.
+		ParseRow func(vals []string) (Second, Vec2[Meter, Meter], error)
+	}
+
 	// A tagged union that either contains a [RawTimeSeriesData] struct or a
 	// path to a video file.
 	//
@@ -130,6 +204,16 @@ type (
 		AvgPower              []Watt
 		MinPower              []PointInTime[Second, Watt]
 		MaxPower              []PointInTime[Second, Watt]
+		// The mean of the positive vertical velocity samples of each rep. The
+		// samples are evenly spaced in time so this is also the time weighted
+		// mean. This is the only definition of mean concentric velocity used,
+		// all velocity based calculations read it rather than recomputing it.
+		MeanConcentricVel []MeterPerSec
+		MeanPropulsiveVel []MeterPerSec
+		TimeToPeakVel     []Second
+		ConcentricDur     []Second
+		EccentricDur      []Second
+		RangeOfMotion     []Meter
 	}
 
 	// Holds all data that can be collected when a lifter performs an exercise.
@@ -142,6 +226,149 @@ type (
 
 		AbstractData Optional[AbstractData]  // Will be calculated by the database for consistency
 		PhysData     []Optional[PhysicsData] // Can be calculated with [logic.CalcPhysicsData]
+		SetFatigue   []Optional[SetFatigue]  // Will be calculated from PhysData when read from the database
+		EstimatedMax Optional[Kilogram]      // Will be calculated with the [GlobalConf.EstimatedMaxFormula] when read from the database
+	}
+
+	// The data collected when a lifter performs an exercise without any of
+	// the physics data. Reading summaries never touches the physics data.
+	ExerciseSummary struct {
+		Name         string             // The unique name of the exercise
+		Weight       Kilogram           // The weight the exercise was performed with
+		Sets         float64            // The number sets that were performed
+		Reps         int32              // The number of reps that were performed
+		Effort       RPE                // The effort the exercise was performed at
+		AbstractData AbstractData       // Calculated by the database for consistency
+		EstimatedMax Optional[Kilogram] // Calculated with the [GlobalConf.EstimatedMaxFormula]
+		HasPhysData  []bool             // One entry per set, true if the set has physics data
+	}
+
+	// Velocity based fatigue indicators for a single set, calculated from the
+	// mean concentric velocity of each rep in the set.
+	SetFatigue struct {
+		BestRep      int32       // The rep with the fastest mean concentric velocity, starting at 1
+		BestVel      MeterPerSec // The mean concentric velocity of the best rep
+		LastVel      MeterPerSec // The mean concentric velocity of the last rep
+		VelocityLoss float64     // The percent of velocity lost from the best rep to the last rep
+		ThresholdRep int32       // The first rep that reached the velocity loss threshold, 0 if it was never reached
+		FatigueSlope float64     // The change in mean concentric velocity per rep, (m/s)/rep
+	}
+
+	// The velocity based fatigue indicators of a single set along with the
+	// data that identifies the set.
+	SetFatigueEntry struct {
+		WorkoutId
+		Exercise string   // The name of the exercise
+		Weight   Kilogram // The weight the set was performed with
+		Reps     int32    // The number of reps that were performed
+		SetNum   int32    // The set within the exercise, starting at 1
+		SetFatigue
+	}
+
+	// A linear regression of the mean concentric velocity of the fastest rep
+	// of each set against the weight the set was performed with:
+	//
+	//	velocity = Intercept + Slope*weight
+	LoadVelocityProfile struct {
+		Intercept       MeterPerSec // The velocity the model predicts at 0 kg
+		Slope           float64     // The change in velocity per kg, (m/s)/kg
+		MinVelThreshold MeterPerSec // The slowest mean concentric velocity of any rep
+		EstimatedMax    Kilogram    // The weight where velocity equals MinVelThreshold
+		RSquared        float64     // The coefficient of determination of the fit
+		NumSets         int64       // The number of sets used to build the profile
+	}
+
+	// The training log data of a single group summed and averaged over a
+	// single time bucket. Averages are taken over the training log entries,
+	// where each entry is a single exercise in a workout.
+	TrainingAggregate struct {
+		BucketStart   time.Time // The first day of the bucket
+		Group         string    // The exercise name, focus, or kind of the group
+		NumEntries    int64     // The number of training log entries in the bucket
+		TotalVolume   Kilogram
+		AvgVolume     Kilogram
+		TotalExertion RPE
+		AvgExertion   RPE
+		TotalReps     float64
+		AvgTotalReps  float64
+	}
+
+	// Selects the training log data that a workload time series is built
+	// from.
+	WorkloadOpts struct {
+		Metric WorkloadMetric // The training log column that is used as the daily load
+		Focus  *ExerciseFocus // When not nil, only exercises with this focus are included
+	}
+
+	// The acute chronic workload ratio of a client on a single day. The acute
+	// load covers the last 7 days and the chronic load covers the last 28
+	// days, both including the current day.
+	ACWR struct {
+		Date        time.Time
+		Load        float64 // The sum of the selected metric over the day
+		AcuteLoad   float64 // The average daily load of the acute window
+		ChronicLoad float64 // The average daily load of the chronic window
+		Ratio       float64 // AcuteLoad/ChronicLoad, 0 if ChronicLoad is 0
+	}
+
+	// Fosters training monotony and strain of a client over the 7 days ending
+	// on, and including, a single day.
+	MonotonyStrain struct {
+		Date       time.Time
+		Load       float64           // The sum of the selected metric over the day
+		WeeklyLoad float64           // The sum of the daily loads over the week
+		Monotony   Optional[float64] // Mean/standard deviation of the daily loads, not present when the standard deviation is 0
+		Strain     Optional[float64] // WeeklyLoad*Monotony, not present when Monotony is not present
+	}
+
+	// The estimated max of a single training log entry.
+	EstimatedMaxEntry struct {
+		WorkoutId
+		Exercise     string   // The name of the exercise
+		Weight       Kilogram // The weight the exercise was performed with
+		Reps         int32    // The number of reps that were performed
+		Effort       RPE      // The effort the exercise was performed at
+		EstimatedMax Kilogram // The estimated max calculated from the entry
+	}
+
+	// Relative strength scores calculated from the best estimated max of a
+	// clients squat, bench, and deadlift. Only exercises with a kind of
+	// [MainCompound] are considered. Lifts that were never performed are not
+	// present and do not contribute to the total. The scores are defined for
+	// a meet total of all three lifts, so they are always estimates of the
+	// score the client would get at a meet and are only comparable to meet
+	// scores when Partial is false.
+	StrengthScores struct {
+		ClientEmail string
+		Sex         Sex
+		Bodyweight  BodyweightEntry // The bodyweight the scores were calculated with
+		Squat       Optional[EstimatedMaxEntry]
+		Bench       Optional[EstimatedMaxEntry]
+		Deadlift    Optional[EstimatedMaxEntry]
+		Total       Kilogram // The sum of the estimated maxes of each lift
+		Partial     bool     // True if any lift is not present, the total and scores only include the lifts that are
+		Dots        float64
+		Wilks       float64
+		IPFGL       float64
+	}
+
+	// A personal record set by a client in a single workout. The kind of the
+	// record determines the fields that identify it and the units of Value:
+	//   - [HeaviestWeight]: identified by Exercise and Reps, Value is kg
+	//   - [BestEstimatedMax]: identified by Exercise, Value is kg calculated
+	//     with the [GlobalConf.EstimatedMaxFormula]
+	//   - [BestVolumeSet]: identified by Exercise, Value is the kg*reps of a
+	//     single set
+	//   - [FastestPeakVel]: identified by Exercise and Weight, Value is m/s
+	PersonalRecord struct {
+		WorkoutId
+		Exercise string             // The name of the exercise
+		Kind     PersonalRecordKind // The kind of record that was set
+		Weight   Kilogram           // The weight the record was set with
+		Reps     int32              // The reps the record was set with
+		Effort   RPE                // The effort the record was set with
+		Value    float64            // The value of the record
+		Previous Optional[float64]  // The record that was beaten, not present for the first record
 	}
 
 	// A unique identifier for a workout in the database
@@ -157,10 +384,25 @@ type (
 		WorkoutId
 		Exercises []ExerciseData
 	}
+
+	// Represents a workout performed by a lifter without any physics data.
+	WorkoutSummary struct {
+		WorkoutId
+		Exercises []ExerciseSummary
+	}
 )
 
 // Aggregate types
 type (
+	// Options that control how workouts are streamed from the database by
+	// [logic.StreamWorkoutsById] and [logic.StreamWorkoutsInDateRange].
+	StreamWorkoutsOpts struct {
+		// When true the physics data of each set is not read from the
+		// database. The PhysData and SetFatigue fields of every exercise will
+		// be nil.
+		SkipPhysData bool
+	}
+
 	BulkUploadDataOpts struct {
 		sbcsv.Opts
 		*BarPathCalcHyperparams
@@ -171,6 +413,137 @@ type (
 		ExerciseDir           string
 		HyperparamsCreateType CreateFuncType
 		HyperparamsDir        string
+		WorkoutCreateType     CreateFuncType
 		WorkoutDir            string
+
+		// Controls what happens when a row cannot be uploaded. With
+		// [AbortOnError] the first error stops the upload and nothing is
+		// written. With [SkipRejectedRows] each file chunk and workout is
+		// written in its own savepoint, rows that cannot be uploaded are
+		// written to a sidecar <file name>.rejected.csv file next to the file
+		// they came from, and the number of accepted and rejected rows is
+		// added to Counts. Ignored by dry runs.
+		ErrorPolicy UploadErrorPolicy
+		// Holds the number of accepted and rejected rows. Must not be nil
+		// when ErrorPolicy is [SkipRejectedRows]. Not used otherwise.
+		Counts *UploadCounts
+
+		// When true every file is parsed and validated and the physics data
+		// of every workout is calculated, but all changes to the database are
+		// rolled back. Rather than stopping at the first error every problem
+		// that is found is added to Report.
+		DryRun bool
+		// Holds every problem found by a dry run. Must not be nil when DryRun
+		// is true. Not used otherwise.
+		Report *BulkUploadReport
+
+		// When true the upload can be resumed after it fails. Each file chunk
+		// is committed in its own transaction and recorded in the import
+		// journal along with a hash of its content, and chunks that the
+		// journal records as completed by a previous upload are skipped. The
+		// journal entries of every file are removed once the upload finishes.
+		// Both the failed and the resumed upload must set Resume and must use
+		// the same chunk options. Cannot be used with [SkipRejectedRows]
+		// because the rows rejected by skipped chunks would be lost. Ignored
+		// by dry runs.
+		Resume bool
+	}
+
+	// The options used by the *FromCSV functions of the logic package, i.e.
+	// [logic.CreateClientsFromCSV].
+	FromCSVOpts struct {
+		sbcsv.Opts
+
+		// Controls what happens when a row cannot be uploaded. Behaves the
+		// same as the ErrorPolicy field of [BulkUploadDataOpts].
+		ErrorPolicy UploadErrorPolicy
+		// Holds the number of accepted and rejected rows. Must not be nil
+		// when ErrorPolicy is [SkipRejectedRows]. Not used otherwise.
+		Counts *UploadCounts
+	}
+
+	// The number of rows that were accepted and rejected by an upload that
+	// used the [SkipRejectedRows] error policy. For workout csv files a row
+	// is a single exercise and if any row of a workout is rejected the whole
+	// workout is rejected. A row that cannot be parsed is part of the workout
+	// whose rows precede it. For JSON Lines files a row is a single line.
+	UploadCounts struct {
+		Accepted int64
+		Rejected int64
+	}
+
+	// A single problem found by a dry run of [logic.BulkUploadData].
+	BulkUploadProblem struct {
+		File string // The file the problem was found in, empty if it was not tied to a file
+		// The line of the problem in the file, starting at 1, so it can be
+		// found with any text editor. The header of a csv file is line 1 so
+		// its first data row is line 2. Blank lines of JSON Lines files are
+		// counted. 0 if the problem applies to the whole file.
+		Row int
+		// The column, or JSON field, of the problem. Empty if it was not tied
+		// to a single column or the column could not be determined from the
+		// error.
+		Column string
+		Err    error // The problem, use [errors.Is] to check it against the error sentinels
+	}
+
+	// The result of a dry run of [logic.BulkUploadData]. Problems are ordered
+	// by file and then by row.
+	BulkUploadReport struct {
+		Problems []BulkUploadProblem
+	}
+
+	// A file chunk that was committed by a resumable [logic.BulkUploadData].
+	ImportJournalEntry struct {
+		File      string // The path of the file the chunk came from
+		Chunk     int32  // The index of the chunk in the file, starting at 0
+		FileHash  []byte // The sha256 hash of the whole file
+		ChunkHash []byte // The sha256 hash of the chunk
+	}
+
+	// Options that control how an archive written by [logic.Dump] is loaded
+	// by [logic.Restore].
+	RestoreOpts struct {
+		// Also controls how bodyweight entries are loaded. With
+		// [EnsureExists] bodyweight entries that already exist are skipped.
+		// Client attributes always replace any existing attributes.
+		ClientCreateType      CreateFuncType
+		ExerciseCreateType    CreateFuncType
+		HyperparamsCreateType CreateFuncType
+		// With [EnsureExists] workouts that already exist are skipped, their
+		// exercises are not compared to the archived workouts.
+		WorkoutCreateType CreateFuncType
+	}
+
+	// Options that control how [logic.WatchWorkoutDir] watches a dir.
+	WatchWorkoutDirOpts struct {
+		sbcsv.Opts
+		*BarPathCalcHyperparams
+		*BarPathTrackerHyperparams
+
+		// The dir to watch. Every workout file in the dir tree is uploaded,
+		// other than those in the [WatchDoneDir] and [WatchFailedDir] sub-dirs.
+		Dir string
+		// How often the dir tree is checked for new files. Must be >0.
+		PollInterval time.Duration
+		// How long a workout file, and every file in the data dirs it
+		// references, must go unchanged before the workout file is uploaded.
+		// Used to avoid uploading files that are still being copied. Must be
+		// >0.
+		StableFor time.Duration
+		// The clock that decides when the dir tree is polled. Optional, the
+		// system clock is used when nil.
+		Clock WatchClock
+	}
+
+	// The time source of [logic.WatchWorkoutDir]. Replacing the system clock
+	// allows the polls of a watched dir to be driven by hand.
+	WatchClock interface {
+		// Returns the current time. Only used for the first poll, every
+		// later poll uses the time received from the ticker.
+		Now() time.Time
+		// Returns a channel that receives the time every time the dir tree
+		// should be polled and a function that stops the ticker.
+		Ticker(d time.Duration) (<-chan time.Time, func())
 	}
 )
@@ -0,0 +1,303 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/api"
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sberr "code.barbellmath.net/barbell-math/smoothbrain-errs"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestAPI(t *testing.T) {
+	t.Run("statusCodes", apiStatusCodes)
+	t.Run("clientCreateReadDelete", apiClientCreateReadDelete)
+	t.Run("exerciseRead", apiExerciseRead)
+	t.Run("hyperparamsReadDefault", apiHyperparamsReadDefault)
+	t.Run("workoutCreateReadDelete", apiWorkoutCreateReadDelete)
+}
+
+func apiServer(t *testing.T, ctxt context.Context) *httptest.Server {
+	state, ok := logic.StateFromContext(ctxt)
+	sbtest.True(t, ok)
+	handler, err := api.NewHandler(state)
+	sbtest.Nil(t, err)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func apiRequest(
+	t *testing.T,
+	server *httptest.Server,
+	method string,
+	path string,
+	body any,
+	res any,
+) int {
+	var reqBody bytes.Buffer
+	if body != nil {
+		sbtest.Nil(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+	req, err := http.NewRequest(method, server.URL+path, &reqBody)
+	sbtest.Nil(t, err)
+	resp, err := server.Client().Do(req)
+	sbtest.Nil(t, err)
+	defer resp.Body.Close()
+	if res != nil {
+		sbtest.Nil(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+	return resp.StatusCode
+}
+
+func apiStatusCodes(t *testing.T) {
+	sbtest.Eq(t, http.StatusBadRequest, api.StatusCode(types.InvalidRequestErr))
+	sbtest.Eq(
+		t, http.StatusRequestEntityTooLarge,
+		api.StatusCode(types.RequestTooLargeErr),
+	)
+	sbtest.Eq(
+		t, http.StatusNotFound,
+		api.StatusCode(sberr.AppendError(
+			types.CouldNotReadAllWorkoutsErr, types.NotFoundErr,
+		)),
+	)
+	sbtest.Eq(
+		t, http.StatusUnprocessableEntity,
+		api.StatusCode(sberr.AppendError(
+			types.CouldNotCreateAllClientsErr,
+			&pgconn.PgError{Code: "23505"},
+		)),
+	)
+	sbtest.Eq(
+		t, http.StatusInternalServerError,
+		api.StatusCode(types.InvalidCtxtErr),
+	)
+
+	// The operation errors alone do not say why the operation failed, i.e.
+	// the database could be unavailable
+	sbtest.Eq(
+		t, http.StatusInternalServerError,
+		api.StatusCode(types.CouldNotReadAllWorkoutsErr),
+	)
+	sbtest.Eq(
+		t, http.StatusInternalServerError,
+		api.StatusCode(sberr.AppendError(
+			types.CouldNotCreateAllClientsErr,
+			&pgconn.PgError{Code: "08006"},
+		)),
+	)
+}
+
+func apiClientCreateReadDelete(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+	server := apiServer(t, ctxt)
+
+	clients := []types.Client{
+		{FirstName: "FName", LastName: "LName", Email: "email@email.com"},
+		{FirstName: "FName1", LastName: "LName1", Email: "email1@email.com"},
+	}
+	status := apiRequest(t, server, http.MethodPost, "/clients", clients, nil)
+	sbtest.Eq(t, http.StatusCreated, status)
+
+	var errRes api.ErrorResponse
+	status = apiRequest(t, server, http.MethodPost, "/clients", clients, &errRes)
+	sbtest.Eq(t, http.StatusUnprocessableEntity, status)
+	sbtest.True(t, len(errRes.Error) > 0)
+
+	status = apiRequest(
+		t, server, http.MethodPost, "/clients?ensureExists=true", clients, nil,
+	)
+	sbtest.Eq(t, http.StatusCreated, status)
+
+	status = apiRequest(
+		t, server, http.MethodPost, "/clients", "not a list", &errRes,
+	)
+	sbtest.Eq(t, http.StatusBadRequest, status)
+
+	// The quotes of the encoded string push the body over the limit
+	status = apiRequest(
+		t, server, http.MethodPost, "/clients",
+		strings.Repeat("a", api.MaxBodySize), &errRes,
+	)
+	sbtest.Eq(t, http.StatusRequestEntityTooLarge, status)
+	sbtest.True(t, strings.Contains(errRes.Error, "Request body too large"))
+
+	var count api.CountResponse
+	status = apiRequest(t, server, http.MethodGet, "/clients/count", nil, &count)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, 2, count.Count)
+
+	var client types.Client
+	status = apiRequest(
+		t, server, http.MethodGet, "/clients/email1@email.com", nil, &client,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, clients[1], client)
+
+	clients[1].FirstName = "FName2"
+	status = apiRequest(t, server, http.MethodPut, "/clients", clients, nil)
+	sbtest.Eq(t, http.StatusNoContent, status)
+	status = apiRequest(
+		t, server, http.MethodGet, "/clients/email1@email.com", nil, &client,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, clients[1], client)
+
+	status = apiRequest(
+		t, server, http.MethodDelete, "/clients/email1@email.com", nil, nil,
+	)
+	sbtest.Eq(t, http.StatusNoContent, status)
+	status = apiRequest(
+		t, server, http.MethodGet, "/clients/email1@email.com", nil, &errRes,
+	)
+	sbtest.Eq(t, http.StatusNotFound, status)
+}
+
+func apiExerciseRead(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+	server := apiServer(t, ctxt)
+
+	var exercise types.Exercise
+	status := apiRequest(
+		t, server, http.MethodGet, "/exercises/Squat", nil, &exercise,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, "Squat", exercise.Name)
+	sbtest.Eq(t, types.Squat, exercise.FocusId)
+
+	var errRes api.ErrorResponse
+	status = apiRequest(
+		t, server, http.MethodGet, "/exercises/NotAnExercise", nil, &errRes,
+	)
+	sbtest.Eq(t, http.StatusNotFound, status)
+}
+
+func apiHyperparamsReadDefault(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+	server := apiServer(t, ctxt)
+
+	exp, err := logic.ReadDefaultHyperparamsFor[types.BarPathCalcHyperparams](
+		ctxt,
+	)
+	sbtest.Nil(t, err)
+
+	var res types.BarPathCalcHyperparams
+	status := apiRequest(
+		t, server, http.MethodGet, "/hyperparams/barPathCalc/default", nil, &res,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, exp, res)
+
+	var errRes api.ErrorResponse
+	status = apiRequest(
+		t, server, http.MethodGet, "/hyperparams/barPathCalc/asdf", nil, &errRes,
+	)
+	sbtest.Eq(t, http.StatusBadRequest, status)
+}
+
+func apiWorkoutCreateReadDelete(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+	server := apiServer(t, ctxt)
+
+	status := apiRequest(
+		t, server, http.MethodPost, "/clients",
+		[]types.Client{{
+			FirstName: "FName", LastName: "LName", Email: "email@email.com",
+		}},
+		nil,
+	)
+	sbtest.Eq(t, http.StatusCreated, status)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	workouts := modelWorkouts(start, 3)
+	workouts[0].Exercises[0].PhysData = []types.Optional[types.PhysicsData]{
+		{Present: true, Value: testPhysicsData2},
+	}
+	var prs []types.PersonalRecord
+	status = apiRequest(t, server, http.MethodPost, "/workouts", workouts, &prs)
+	sbtest.Eq(t, http.StatusCreated, status)
+	sbtest.Eq(t, 3, len(prs))
+	for _, pr := range prs {
+		sbtest.True(t, util.DateEqual(start, pr.DatePerformed))
+	}
+
+	status = apiRequest(
+		t, server, http.MethodGet,
+		"/clients/email@email.com/personal-records?exercise=Squat", nil, &prs,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, 3, len(prs))
+	status = apiRequest(
+		t, server, http.MethodGet,
+		"/clients/email@email.com/personal-records/history?exercise=Bench",
+		nil, &prs,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, 0, len(prs))
+
+	var workout types.Workout
+	status = apiRequest(
+		t, server, http.MethodGet,
+		"/clients/email@email.com/workouts/2025-01-01/1", nil, &workout,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	workoutsEqual(t, workouts[:1], []types.Workout{workout})
+
+	var res []types.Workout
+	status = apiRequest(
+		t, server, http.MethodGet,
+		"/clients/email@email.com/workouts?start=2025-01-01&end=2025-01-03",
+		nil, &res,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	workoutsEqual(t, workouts[:2], res)
+
+	var errRes api.ErrorResponse
+	status = apiRequest(
+		t, server, http.MethodGet,
+		"/clients/email@email.com/workouts?start=01/01/2025&end=2025-01-03",
+		nil, &errRes,
+	)
+	sbtest.Eq(t, http.StatusBadRequest, status)
+
+	var count api.CountResponse
+	status = apiRequest(
+		t, server, http.MethodDelete,
+		"/clients/email@email.com/workouts?start=2025-01-02&end=2025-01-04",
+		nil, &count,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, 2, count.Count)
+
+	status = apiRequest(
+		t, server, http.MethodDelete,
+		"/clients/email@email.com/workouts/2025-01-01/1", nil, nil,
+	)
+	sbtest.Eq(t, http.StatusNoContent, status)
+	status = apiRequest(
+		t, server, http.MethodGet,
+		"/clients/email@email.com/workouts/2025-01-01/1", nil, &errRes,
+	)
+	sbtest.Eq(t, http.StatusNotFound, status)
+	status = apiRequest(
+		t, server, http.MethodGet,
+		"/clients/email@email.com/workouts/count", nil, &count,
+	)
+	sbtest.Eq(t, http.StatusOK, status)
+	sbtest.Eq(t, 0, count.Count)
+}
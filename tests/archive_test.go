@@ -0,0 +1,272 @@
+package tests
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestArchive(t *testing.T) {
+	t.Run("dumpRestore", archiveDumpRestore)
+	t.Run("merge", archiveMerge)
+	t.Run("mergeWorkouts", archiveMergeWorkouts)
+	t.Run("invalidArchive", archiveInvalidArchive)
+}
+
+type archiveCounts struct {
+	Clients     int64
+	Exercises   int64
+	Hyperparams int64
+	Workouts    int64
+	Bodyweights int
+}
+
+func archiveReadCounts(t *testing.T, ctxt context.Context) archiveCounts {
+	var res archiveCounts
+	var err error
+	res.Clients, err = logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	res.Exercises, err = logic.ReadNumExercises(ctxt)
+	sbtest.Nil(t, err)
+	res.Hyperparams, err = logic.ReadNumHyperparams(ctxt)
+	sbtest.Nil(t, err)
+	res.Workouts, err = logic.ReadNumWorkoutsForClient(ctxt, "two@gmail.com")
+	sbtest.Nil(t, err)
+	bodyweights, err := logic.ReadBodyweightsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+	res.Bodyweights = len(bodyweights)
+	return res
+}
+
+func archiveRestoreOpts(clientCreateType types.CreateFuncType) *types.RestoreOpts {
+	return &types.RestoreOpts{
+		ClientCreateType:      clientCreateType,
+		ExerciseCreateType:    types.EnsureExists,
+		HyperparamsCreateType: types.EnsureExists,
+		WorkoutCreateType:     clientCreateType,
+	}
+}
+
+func archiveDumpRestore(t *testing.T) {
+	ctxt, cleanup := workoutExportUpload(t, "./testData/workoutData", "1/2/2006")
+	err := logic.SetClientAttributes(ctxt, types.ClientAttributes{
+		Email: "two@gmail.com", Sex: types.Female,
+	})
+	sbtest.Nil(t, err)
+	err = logic.CreateBodyweights(
+		ctxt,
+		types.BodyweightEntry{
+			ClientEmail:  "two@gmail.com",
+			DateMeasured: workoutExportStart,
+			Weight:       60,
+		},
+		types.BodyweightEntry{
+			ClientEmail:  "two@gmail.com",
+			DateMeasured: workoutExportStart.Add(24 * time.Hour),
+			Weight:       61,
+		},
+	)
+	sbtest.Nil(t, err)
+
+	archive := bytes.Buffer{}
+	err = logic.Dump(ctxt, &archive)
+	sbtest.Nil(t, err)
+	expectedCounts := archiveReadCounts(t, ctxt)
+	expected, err := logic.FindWorkoutsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+	cleanup()
+
+	ctxt, cleanup = resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+	err = logic.Restore(ctxt, &archive, archiveRestoreOpts(types.Create))
+	sbtest.Nil(t, err)
+
+	sbtest.Eq(t, expectedCounts, archiveReadCounts(t, ctxt))
+	sbtest.Eq(t, 2, expectedCounts.Clients)
+	sbtest.Eq(t, 3, expectedCounts.Workouts)
+	sbtest.Eq(t, 2, expectedCounts.Bodyweights)
+	res, err := logic.FindWorkoutsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+	exportedWorkoutsEqual(t, expected, res)
+
+	// Physics data is restored as is rather than being recalculated
+	for i := range expected {
+		for j, e := range expected[i].Exercises {
+			for k, p := range e.PhysData {
+				rp := res[i].Exercises[j].PhysData[k].Value
+				sbtest.Eq(t, p.Value.BarPathCalcVersion, rp.BarPathCalcVersion)
+				sbtest.Eq(
+					t, p.Value.BarPathTrackerVersion, rp.BarPathTrackerVersion,
+				)
+				sbtest.Eq(t, len(p.Value.Velocity), len(rp.Velocity))
+				for l := range p.Value.Velocity {
+					sbtest.EqFloat(
+						t, float64(p.Value.Velocity[l].Y),
+						float64(rp.Velocity[l].Y), 1e-9,
+					)
+				}
+			}
+		}
+	}
+
+	attrs, err := logic.ReadClientAttributes(ctxt, "two@gmail.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, types.Female, attrs[0].Sex)
+	bodyweights, err := logic.ReadBodyweightsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+	sbtest.EqFloat(t, 60, float64(bodyweights[0].Weight), 1e-9)
+	sbtest.EqFloat(t, 61, float64(bodyweights[1].Weight), 1e-9)
+
+	hyperparams, err := logic.ReadHyperparamsByVersionFor[types.BarPathCalcHyperparams](
+		ctxt, 1, 2,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, types.SecondOrder, hyperparams[0].ApproxErr)
+	sbtest.Eq(t, types.FourthOrder, hyperparams[1].ApproxErr)
+	sbtest.EqFloat(t, 0.3, hyperparams[1].SmootherWeight3, 1e-9)
+}
+
+func archiveMerge(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+	archive := bytes.Buffer{}
+	err = logic.Dump(ctxt, &archive)
+	sbtest.Nil(t, err)
+	data := archive.Bytes()
+
+	// Creating clients that already exist fails and changes nothing
+	err = logic.Restore(
+		ctxt, bytes.NewReader(data), archiveRestoreOpts(types.Create),
+	)
+	sbtest.ContainsError(t, types.CouldNotRestoreErr, err)
+	sbtest.ContainsError(t, types.CouldNotCreateAllClientsErr, err)
+
+	err = logic.Restore(
+		ctxt, bytes.NewReader(data), archiveRestoreOpts(types.EnsureExists),
+	)
+	sbtest.Nil(t, err)
+	n, err := logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, n)
+
+	// Restoring with no options creates everything
+	err = logic.Restore(ctxt, bytes.NewReader(data), nil)
+	sbtest.ContainsError(t, types.CouldNotRestoreErr, err)
+}
+
+func archiveMergeWorkouts(t *testing.T) {
+	ctxt, cleanup := workoutExportUpload(t, "./testData/workoutData", "1/2/2006")
+	t.Cleanup(cleanup)
+	err := logic.CreateBodyweights(ctxt, types.BodyweightEntry{
+		ClientEmail:  "two@gmail.com",
+		DateMeasured: workoutExportStart,
+		Weight:       60,
+	})
+	sbtest.Nil(t, err)
+	archive := bytes.Buffer{}
+	err = logic.Dump(ctxt, &archive)
+	sbtest.Nil(t, err)
+	data := archive.Bytes()
+	expectedCounts := archiveReadCounts(t, ctxt)
+
+	opts := archiveRestoreOpts(types.EnsureExists)
+	opts.WorkoutCreateType = types.Create
+	err = logic.Restore(ctxt, bytes.NewReader(data), opts)
+	sbtest.ContainsError(t, types.CouldNotRestoreErr, err)
+	sbtest.ContainsError(t, types.CouldNotCreateAllWorkoutsErr, err)
+
+	// Existing workouts and bodyweights are skipped
+	err = logic.Restore(
+		ctxt, bytes.NewReader(data), archiveRestoreOpts(types.EnsureExists),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, expectedCounts, archiveReadCounts(t, ctxt))
+}
+
+func archiveInvalidArchive(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.Restore(
+		ctxt, bytes.NewReader([]byte("not an archive")),
+		archiveRestoreOpts(types.Create),
+	)
+	sbtest.ContainsError(t, types.CouldNotRestoreErr, err)
+	sbtest.ContainsError(t, types.InvalidArchiveErr, err)
+
+	writeArchive := func(name string, contents string) *bytes.Buffer {
+		res := bytes.Buffer{}
+		gw := gzip.NewWriter(&res)
+		tw := tar.NewWriter(gw)
+		sbtest.Nil(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(contents)),
+		}))
+		_, err := tw.Write([]byte(contents))
+		sbtest.Nil(t, err)
+		sbtest.Nil(t, tw.Close())
+		sbtest.Nil(t, gw.Close())
+		return &res
+	}
+
+	err = logic.Restore(
+		ctxt, writeArchive("manifest.json", `{"Version": 3}`),
+		archiveRestoreOpts(types.Create),
+	)
+	sbtest.ContainsError(t, types.UnsupportedArchiveVersionErr, err)
+
+	err = logic.Restore(
+		ctxt, writeArchive("clients/clients.csv", ""),
+		archiveRestoreOpts(types.Create),
+	)
+	sbtest.ContainsError(
+		t, types.InvalidArchiveErr, err, `The first file must be manifest.json`,
+	)
+
+	res := bytes.Buffer{}
+	gw := gzip.NewWriter(&res)
+	tw := tar.NewWriter(gw)
+	for _, f := range []struct{ name, contents string }{
+		{"manifest.json", `{"Version": 2}`},
+		{"workouts/two@gmail.com.csv", ""},
+	} {
+		sbtest.Nil(t, tw.WriteHeader(&tar.Header{
+			Name:     f.name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(f.contents)),
+		}))
+		_, err := tw.Write([]byte(f.contents))
+		sbtest.Nil(t, err)
+	}
+	sbtest.Nil(t, tw.Close())
+	sbtest.Nil(t, gw.Close())
+	err = logic.Restore(ctxt, &res, archiveRestoreOpts(types.Create))
+	sbtest.ContainsError(
+		t, types.InvalidArchiveErr, err, `Workouts must be JSON Lines files`,
+	)
+}
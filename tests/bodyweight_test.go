@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestBodyweight(t *testing.T) {
+	t.Run("crud", bodyweightCrud)
+	t.Run("invalidArgs", bodyweightInvalidArgs)
+	t.Run("clientAttributes", bodyweightClientAttributes)
+	t.Run("strengthScores", bodyweightStrengthScores)
+	t.Run("strengthScoresMissingData", bodyweightStrengthScoresMissingData)
+}
+
+func bodyweightEntry(day int, weight types.Kilogram) types.BodyweightEntry {
+	return types.BodyweightEntry{
+		ClientEmail:  "email@email.com",
+		DateMeasured: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC),
+		Weight:       weight,
+	}
+}
+
+func bodyweightsEqual(
+	t *testing.T,
+	l []types.BodyweightEntry,
+	r []types.BodyweightEntry,
+) {
+	sbtest.Eq(t, len(l), len(r))
+	for i := range len(l) {
+		sbtest.Eq(t, l[i].ClientEmail, r[i].ClientEmail)
+		sbtest.True(t, util.DateEqual(l[i].DateMeasured, r[i].DateMeasured))
+		sbtest.EqFloat(t, float64(l[i].Weight), float64(r[i].Weight), 1e-6)
+	}
+}
+
+func bodyweightSetup(t *testing.T) (context.Context, func()) {
+	ctxt, cleanup := resetApp(t, context.Background())
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+	return ctxt, cleanup
+}
+
+func bodyweightCrud(t *testing.T) {
+	ctxt, cleanup := bodyweightSetup(t)
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	err := logic.CreateBodyweights(
+		ctxt, bodyweightEntry(3, 90), bodyweightEntry(1, 91),
+		bodyweightEntry(5, 89.5),
+	)
+	sbtest.Nil(t, err)
+	res, err := logic.ReadBodyweightsInDateRange(
+		ctxt, "email@email.com", start, end,
+	)
+	sbtest.Nil(t, err)
+	bodyweightsEqual(t, []types.BodyweightEntry{
+		bodyweightEntry(1, 91), bodyweightEntry(3, 90), bodyweightEntry(5, 89.5),
+	}, res)
+
+	err = logic.UpdateBodyweights(ctxt, bodyweightEntry(3, 92))
+	sbtest.Nil(t, err)
+	res, err = logic.ReadBodyweightsInDateRange(
+		ctxt, "email@email.com", start, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC),
+	)
+	sbtest.Nil(t, err)
+	bodyweightsEqual(t, []types.BodyweightEntry{
+		bodyweightEntry(1, 91), bodyweightEntry(3, 92),
+	}, res)
+
+	num, err := logic.DeleteBodyweightsInDateRange(
+		ctxt, "email@email.com", start, time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 2, num)
+	res, err = logic.ReadBodyweightsInDateRange(
+		ctxt, "email@email.com", start, end,
+	)
+	sbtest.Nil(t, err)
+	bodyweightsEqual(t, []types.BodyweightEntry{bodyweightEntry(5, 89.5)}, res)
+
+	// Deleting the client deletes their bodyweight entries
+	err = logic.DeleteClients(ctxt, "email@email.com")
+	sbtest.Nil(t, err)
+	res, err = logic.ReadBodyweightsInDateRange(
+		ctxt, "email@email.com", start, end,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(res))
+}
+
+func bodyweightInvalidArgs(t *testing.T) {
+	ctxt, cleanup := bodyweightSetup(t)
+	t.Cleanup(cleanup)
+
+	err := logic.CreateBodyweights(ctxt, bodyweightEntry(1, 0))
+	sbtest.ContainsError(t, types.CouldNotCreateAllBodyweightsErr, err)
+
+	err = logic.CreateBodyweights(ctxt, types.BodyweightEntry{
+		ClientEmail:  "bad@email.com",
+		DateMeasured: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Weight:       90,
+	})
+	sbtest.ContainsError(
+		t, types.CouldNotCreateAllBodyweightsErr, err, `Does client exist\?`,
+	)
+
+	// A client can only have one entry per day
+	err = logic.CreateBodyweights(ctxt, bodyweightEntry(1, 90))
+	sbtest.Nil(t, err)
+	err = logic.CreateBodyweights(ctxt, bodyweightEntry(1, 91))
+	sbtest.ContainsError(t, types.CouldNotCreateAllBodyweightsErr, err)
+
+	err = logic.UpdateBodyweights(ctxt, bodyweightEntry(2, 91))
+	sbtest.ContainsError(
+		t, types.CouldNotUpdateAllBodyweightsErr, err, `Does client exist\?`,
+	)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = logic.ReadBodyweightsInDateRange(
+		ctxt, "email@email.com", start, start.AddDate(0, 0, -1),
+	)
+	sbtest.ContainsError(t, types.CouldNotReadAllBodyweightsErr, err)
+	_, err = logic.DeleteBodyweightsInDateRange(
+		ctxt, "email@email.com", start, start.AddDate(0, 0, -1),
+	)
+	sbtest.ContainsError(t, types.CouldNotDeleteAllBodyweightsErr, err)
+}
+
+func bodyweightClientAttributes(t *testing.T) {
+	ctxt, cleanup := bodyweightSetup(t)
+	t.Cleanup(cleanup)
+
+	res, err := logic.ReadClientAttributes(ctxt, "email@email.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(res))
+	sbtest.Eq(t, "email@email.com", res[0].Email)
+	sbtest.Eq(t, types.UnknownSex, res[0].Sex)
+
+	err = logic.SetClientAttributes(ctxt, types.ClientAttributes{
+		Email: "email@email.com", Sex: types.Female,
+	})
+	sbtest.Nil(t, err)
+	err = logic.SetClientAttributes(ctxt, types.ClientAttributes{
+		Email: "email@email.com", Sex: types.Male,
+	})
+	sbtest.Nil(t, err)
+	res, err = logic.ReadClientAttributes(ctxt, "email@email.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, types.Male, res[0].Sex)
+
+	err = logic.SetClientAttributes(ctxt, types.ClientAttributes{
+		Email: "email@email.com", Sex: types.Sex(-1),
+	})
+	sbtest.ContainsError(t, types.InvalidSexErr, err)
+	err = logic.SetClientAttributes(ctxt, types.ClientAttributes{
+		Email: "bad@email.com", Sex: types.Male,
+	})
+	sbtest.ContainsError(
+		t, types.CouldNotSetAllClientAttributesErr, err, `Does client exist\?`,
+	)
+	_, err = logic.ReadClientAttributes(ctxt, "bad@email.com")
+	sbtest.ContainsError(
+		t, types.CouldNotReadAllClientAttributesErr, err, `Does client exist\?`,
+	)
+}
+
+func bodyweightStrengthScores(t *testing.T) {
+	ctxt, cleanup := bodyweightSetup(t)
+	t.Cleanup(cleanup)
+
+	_, err := logic.CreateWorkouts(ctxt, trainingWorkouts()...)
+	sbtest.Nil(t, err)
+	err = logic.SetClientAttributes(ctxt, types.ClientAttributes{
+		Email: "email@email.com", Sex: types.Male,
+	})
+	sbtest.Nil(t, err)
+	err = logic.CreateBodyweights(
+		ctxt, bodyweightEntry(1, 90), bodyweightEntry(10, 100),
+	)
+	sbtest.Nil(t, err)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	res, err := logic.CalcStrengthScores(
+		ctxt, "email@email.com", start,
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, types.Male, res.Sex)
+	sbtest.EqFloat(t, 100, float64(res.Bodyweight.Weight), 1e-6)
+	sbtest.True(t, res.Squat.Present)
+	sbtest.EqFloat(t, 110, float64(res.Squat.Value.Weight), 1e-6)
+	sbtest.EqFloat(t, 110*7.0/6, float64(res.Squat.Value.EstimatedMax), 1e-6)
+	sbtest.True(t, res.Bench.Present)
+	sbtest.EqFloat(t, 50*4.0/3, float64(res.Bench.Value.EstimatedMax), 1e-6)
+	sbtest.True(t, res.Deadlift.Present)
+	sbtest.EqFloat(t, 175, float64(res.Deadlift.Value.EstimatedMax), 1e-6)
+	sbtest.EqFloat(t, 370, float64(res.Total), 1e-6)
+	sbtest.False(t, res.Partial)
+	sbtest.EqFloat(t, 227.741, res.Dots, 1e-3)
+	sbtest.EqFloat(t, 225.178, res.Wilks, 1e-3)
+	sbtest.EqFloat(t, 46.742, res.IPFGL, 1e-3)
+
+	// The deadlift and the second bodyweight entry are outside of the range
+	res, err = logic.CalcStrengthScores(
+		ctxt, "email@email.com", start,
+		time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+	)
+	sbtest.Nil(t, err)
+	sbtest.EqFloat(t, 90, float64(res.Bodyweight.Weight), 1e-6)
+	sbtest.False(t, res.Deadlift.Present)
+	sbtest.EqFloat(t, 195, float64(res.Total), 1e-6)
+	sbtest.True(t, res.Partial)
+	sbtest.EqFloat(t, 126.087, res.Dots, 1e-3)
+	sbtest.EqFloat(t, 124.487, res.Wilks, 1e-3)
+	sbtest.EqFloat(t, 25.924, res.IPFGL, 1e-3)
+}
+
+func bodyweightStrengthScoresMissingData(t *testing.T) {
+	ctxt, cleanup := bodyweightSetup(t)
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := logic.CalcStrengthScores(ctxt, "email@email.com", start, end)
+	sbtest.ContainsError(t, types.InvalidSexErr, err)
+
+	err = logic.SetClientAttributes(ctxt, types.ClientAttributes{
+		Email: "email@email.com", Sex: types.Female,
+	})
+	sbtest.Nil(t, err)
+	_, err = logic.CalcStrengthScores(ctxt, "email@email.com", start, end)
+	sbtest.ContainsError(t, types.NoBodyweightErr, err)
+
+	// No lifts results in a total of 0
+	err = logic.CreateBodyweights(ctxt, bodyweightEntry(1, 60))
+	sbtest.Nil(t, err)
+	res, err := logic.CalcStrengthScores(ctxt, "email@email.com", start, end)
+	sbtest.Nil(t, err)
+	sbtest.False(t, res.Squat.Present)
+	sbtest.True(t, res.Partial)
+	sbtest.EqFloat(t, 0, float64(res.Total), 1e-6)
+	sbtest.EqFloat(t, 0, res.Dots, 1e-6)
+
+	_, err = logic.CalcStrengthScores(ctxt, "bad@email.com", start, end)
+	sbtest.ContainsError(t, types.CouldNotCalcStrengthScoresErr, err)
+}
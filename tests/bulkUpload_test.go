@@ -2,7 +2,12 @@ package tests
 
 import (
 	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	"code.barbellmath.net/barbell-math/providentia/internal/dal/migrations"
 	"code.barbellmath.net/barbell-math/providentia/internal/util"
@@ -15,6 +20,13 @@ import (
 func TestBulkUpload(t *testing.T) {
 	t.Run("failingNoWrites", bulkUploadFailingNoWrites)
 	t.Run("passing", bulkUploadPassing)
+	t.Run("customTimeSeriesFormat", bulkUploadCustomTimeSeriesFormat)
+	t.Run("dryRunPassing", bulkUploadDryRunPassing)
+	t.Run("dryRunReport", bulkUploadDryRunReport)
+	t.Run("dryRunReportColumns", bulkUploadDryRunReportColumns)
+	t.Run("skipRejectedRows", bulkUploadSkipRejectedRows)
+	t.Run("skipRejectedWorkoutRows", bulkUploadSkipRejectedWorkoutRows)
+	t.Run("resume", bulkUploadResume)
 }
 
 func bulkUploadFailingNoWrites(t *testing.T) {
@@ -32,6 +44,7 @@ func bulkUploadFailingNoWrites(t *testing.T) {
 	t.Run("badHyperparamsExt", bulkUploadBadHyperparamsExt(ctxt))
 	t.Run("badHyperparamsType", bulkUploadBadHyperparamsType(ctxt))
 	t.Run("malformedHyperparamFile", bulkUploadMalformedHyperparamFile(ctxt))
+	t.Run("unknownTimeSeriesFormat", bulkUploadUnknownTimeSeriesFormat(ctxt))
 }
 
 func bulkUploadBadClientDir(ctxt context.Context) func(t *testing.T) {
@@ -57,7 +70,7 @@ func bulkUploadBadClientFile(ctxt context.Context) func(t *testing.T) {
 		sbtest.ContainsError(t, types.BulkDataUploadErr, err)
 		sbtest.ContainsError(
 			t, util.FilesWithExtInDirErr, err,
-			`Supplied dir \(\./testData/dataBadFile\) contained non-csv files`,
+			`Supplied dir \(\./testData/dataBadFile\) contained non-csv/jsonl files`,
 		)
 	}
 }
@@ -101,7 +114,7 @@ func bulkUploadBadExerciseFile(ctxt context.Context) func(t *testing.T) {
 		sbtest.ContainsError(t, types.BulkDataUploadErr, err)
 		sbtest.ContainsError(
 			t, util.FilesWithExtInDirErr, err,
-			`Supplied dir \(\./testData/dataBadFile\) contained non-csv files`,
+			`Supplied dir \(\./testData/dataBadFile\) contained non-csv/jsonl files`,
 		)
 	}
 }
@@ -145,7 +158,7 @@ func bulkUploadBadHyperparamsFile(ctxt context.Context) func(t *testing.T) {
 		sbtest.ContainsError(t, types.BulkDataUploadErr, err)
 		sbtest.ContainsError(
 			t, util.FilesWithExtInDirErr, err,
-			`Supplied dir \(\./testData/dataBadFile\) contained non-csv files`,
+			`Supplied dir \(\./testData/dataBadFile\) contained non-csv/jsonl files`,
 		)
 	}
 }
@@ -159,7 +172,7 @@ func bulkUploadBadHyperparamsExt(ctxt context.Context) func(t *testing.T) {
 		sbtest.ContainsError(t, types.BulkDataUploadErr, err)
 		sbtest.ContainsError(
 			t, types.UnknownFileInDataDirErr, err,
-			`Invalid hyperparam file. File name must have the following format: <file name>.<hyperparam type>.csv`,
+			`Invalid hyperparam file. File name must have the following format: <file name>.<hyperparam type>.<csv\|jsonl>`,
 		)
 	}
 }
@@ -194,6 +207,35 @@ func bulkUploadMalformedHyperparamFile(ctxt context.Context) func(t *testing.T)
 	}
 }
 
+func bulkUploadUnknownTimeSeriesFormat(ctxt context.Context) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+			ClientDir:          "./testData/clientData",
+			ClientCreateType:   types.Create,
+			ExerciseDir:        "./testData/exerciseData",
+			ExerciseCreateType: types.Create,
+			WorkoutDir:         "./testData/customTimeSeriesWorkoutData",
+			BarPathCalcHyperparams: &types.BarPathCalcHyperparams{
+				MinNumSamples: 5,
+				ApproxErr:     types.SecondOrder,
+				NoiseFilter:   1,
+			},
+			BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+			Opts:                      sbcsv.Opts{TimeFormat: "1/2/2006"},
+		})
+		sbtest.ContainsError(t, types.CSVLoaderJobQueueErr, err)
+		sbtest.ContainsError(t, types.InvalidDataDirErr, err)
+		sbtest.ContainsError(
+			t, types.UnknownTimeSeriesCSVFormatErr, err,
+			`did not match any of the registered formats: providentia, metricVBT`,
+		)
+
+		numClients, err := logic.ReadNumClients(ctxt)
+		sbtest.Nil(t, err)
+		sbtest.Eq(t, numClients, 0)
+	}
+}
+
 func bulkUploadPassing(t *testing.T) {
 	ctxt, cleanup := resetApp(t, context.Background())
 	t.Cleanup(cleanup)
@@ -238,4 +280,521 @@ func bulkUploadPassing(t *testing.T) {
 	numWorkouts, err := logic.ReadNumWorkoutsForClient(ctxt, "two@gmail.com")
 	sbtest.Nil(t, err)
 	sbtest.Eq(t, numWorkouts, 3)
+
+	// Set1 is in the providentia format and Set2 is the same bar path in the
+	// Metric VBT format
+	workouts, err := logic.ReadWorkoutsById(ctxt, types.WorkoutId{
+		ClientEmail:   "two@gmail.com",
+		Session:       1,
+		DatePerformed: time.Date(2023, 2, 21, 0, 0, 0, 0, time.UTC),
+	})
+	sbtest.Nil(t, err)
+	physData := workouts[0].Exercises[0].PhysData
+	sbtest.Eq(t, 2, len(physData))
+	sbtest.True(t, physData[0].Present)
+	sbtest.True(t, physData[1].Present)
+	sbtest.Eq(t, 11, len(physData[1].Value.Position))
+	for i := range physData[0].Value.Position {
+		sbtest.EqFloat(
+			t,
+			float64(physData[0].Value.Time[i]),
+			float64(physData[1].Value.Time[i]),
+			1e-9,
+		)
+		sbtest.EqFloat(
+			t,
+			float64(physData[0].Value.Position[i].X),
+			float64(physData[1].Value.Position[i].X),
+			1e-9,
+		)
+		sbtest.EqFloat(
+			t,
+			float64(physData[0].Value.Position[i].Y),
+			float64(physData[1].Value.Position[i].Y),
+			1e-9,
+		)
+	}
+}
+
+func bulkUploadCustomTimeSeriesFormat(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	parseMilli := func(vals []string) (types.Second, types.Vec2[types.Meter, types.Meter], error) {
+		res := [3]float64{}
+		for i := range res {
+			v, err := strconv.ParseFloat(vals[i], 64)
+			if err != nil {
+				return 0, types.Vec2[types.Meter, types.Meter]{}, err
+			}
+			res[i] = v / 1000
+		}
+		return types.Second(res[0]), types.Vec2[types.Meter, types.Meter]{
+			X: types.Meter(res[1]), Y: types.Meter(res[2]),
+		}, nil
+	}
+
+	err := logic.RegisterTimeSeriesCSVFormat(types.TimeSeriesCSVFormat{
+		Cols:     []string{"t_ms", "x_mm", "y_mm"},
+		ParseRow: parseMilli,
+	})
+	sbtest.ContainsError(
+		t, types.InvalidTimeSeriesCSVFormatErr, err,
+		`The name must not be empty`,
+	)
+	err = logic.RegisterTimeSeriesCSVFormat(types.TimeSeriesCSVFormat{
+		Name:     "milli",
+		ParseRow: parseMilli,
+	})
+	sbtest.ContainsError(
+		t, types.InvalidTimeSeriesCSVFormatErr, err,
+		`Format 'milli' must request at least one column`,
+	)
+	err = logic.RegisterTimeSeriesCSVFormat(types.TimeSeriesCSVFormat{
+		Name: "milli",
+		Cols: []string{"t_ms", "x_mm", "y_mm"},
+	})
+	sbtest.ContainsError(
+		t, types.InvalidTimeSeriesCSVFormatErr, err,
+		`Format 'milli' must have a ParseRow function`,
+	)
+	err = logic.RegisterTimeSeriesCSVFormat(types.TimeSeriesCSVFormat{
+		Name:     "metricVBT",
+		Cols:     []string{"t_ms", "x_mm", "y_mm"},
+		ParseRow: parseMilli,
+	})
+	sbtest.ContainsError(
+		t, types.InvalidTimeSeriesCSVFormatErr, err,
+		`A format with the name 'metricVBT' is already registered`,
+	)
+
+	err = logic.RegisterTimeSeriesCSVFormat(types.TimeSeriesCSVFormat{
+		Name:     "milli",
+		Cols:     []string{"t_ms", "x_mm", "y_mm"},
+		ParseRow: parseMilli,
+	})
+	sbtest.Nil(t, err)
+	// The registry is shared by the whole process so the format must be
+	// removed to not leak into other tests.
+	t.Cleanup(func() {
+		sbtest.Nil(t, logic.UnregisterTimeSeriesCSVFormat("milli"))
+	})
+
+	err = logic.UnregisterTimeSeriesCSVFormat("metricVBT")
+	sbtest.ContainsError(
+		t, types.InvalidTimeSeriesCSVFormatErr, err,
+		`The default format 'metricVBT' cannot be unregistered`,
+	)
+	err = logic.UnregisterTimeSeriesCSVFormat("notRegistered")
+	sbtest.ContainsError(
+		t, types.UnknownTimeSeriesCSVFormatErr, err,
+		`No format with the name 'notRegistered' is registered`,
+	)
+
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:          "./testData/clientData",
+		ClientCreateType:   types.Create,
+		ExerciseDir:        "./testData/exerciseData",
+		ExerciseCreateType: types.Create,
+		WorkoutDir:         "./testData/customTimeSeriesWorkoutData",
+		BarPathCalcHyperparams: &types.BarPathCalcHyperparams{
+			MinNumSamples: 5,
+			ApproxErr:     types.SecondOrder,
+			NoiseFilter:   1,
+		},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		Opts:                      sbcsv.Opts{TimeFormat: "1/2/2006"},
+	})
+	sbtest.Nil(t, err)
+
+	workouts, err := logic.ReadWorkoutsById(ctxt, types.WorkoutId{
+		ClientEmail:   "two@gmail.com",
+		Session:       1,
+		DatePerformed: time.Date(2023, 2, 21, 0, 0, 0, 0, time.UTC),
+	})
+	sbtest.Nil(t, err)
+	physData := workouts[0].Exercises[0].PhysData
+	sbtest.Eq(t, 1, len(physData))
+	sbtest.True(t, physData[0].Present)
+	sbtest.Eq(t, 11, len(physData[0].Value.Position))
+	sbtest.EqFloat(t, 10, float64(physData[0].Value.Time[10]), 1e-9)
+	sbtest.EqFloat(t, 10, float64(physData[0].Value.Position[10].Y), 1e-9)
+}
+
+func bulkUploadDryRunPassing(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	report := types.BulkUploadReport{}
+	err := logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:             "./testData/clientData",
+		ClientCreateType:      types.Create,
+		ExerciseDir:           "./testData/exerciseData",
+		ExerciseCreateType:    types.Create,
+		HyperparamsDir:        "./testData/hyperparamData",
+		HyperparamsCreateType: types.Create,
+		WorkoutDir:            "./testData/workoutData",
+		BarPathCalcHyperparams: &types.BarPathCalcHyperparams{
+			MinNumSamples: 5,
+			ApproxErr:     types.SecondOrder,
+			NoiseFilter:   1,
+		},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		Opts:                      sbcsv.Opts{TimeFormat: "1/2/2006"},
+		DryRun:                    true,
+		Report:                    &report,
+	})
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(report.Problems))
+
+	numClients, err := logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numClients, 0)
+	numExercises, err := logic.ReadNumExercises(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numExercises, int64(len(migrations.ExerciseSetupData)))
+
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{DryRun: true})
+	sbtest.ContainsError(
+		t, types.BulkDataUploadErr, err,
+		`When DryRun is true Report must not be nil`,
+	)
+}
+
+func bulkUploadDryRunReport(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	report := types.BulkUploadReport{}
+	err := logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:             "./testData/dryRunClientData",
+		ClientCreateType:      types.Create,
+		ExerciseDir:           "./testData/dryRunExerciseData",
+		ExerciseCreateType:    types.Create,
+		HyperparamsDir:        "./testData/badHyperparamTypeData",
+		HyperparamsCreateType: types.Create,
+		DryRun:                true,
+		Report:                &report,
+	})
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 4, len(report.Problems))
+
+	sbtest.Eq(
+		t, "testData/badHyperparamTypeData/1.asdf.csv", report.Problems[0].File,
+	)
+	sbtest.Eq(t, 0, report.Problems[0].Row)
+	sbtest.ContainsError(
+		t, types.UnknownFileInDataDirErr, report.Problems[0].Err,
+	)
+
+	sbtest.Eq(
+		t, "testData/dryRunClientData/clients.csv", report.Problems[1].File,
+	)
+	// The header is line 1, the duplicate client is the second data row
+	sbtest.Eq(t, 3, report.Problems[1].Row)
+	sbtest.ContainsError(
+		t, types.CouldNotCreateAllClientsErr, report.Problems[1].Err,
+	)
+
+	sbtest.Eq(
+		t, "testData/dryRunExerciseData/exercises.jsonl",
+		report.Problems[2].File,
+	)
+	sbtest.Eq(t, 2, report.Problems[2].Row)
+	sbtest.ContainsError(t, types.MalformedJSONLErr, report.Problems[2].Err)
+	sbtest.ContainsError(
+		t, types.ErrInvalidExerciseKind, report.Problems[2].Err,
+	)
+
+	sbtest.Eq(
+		t, "testData/dryRunExerciseData/exercises.jsonl",
+		report.Problems[3].File,
+	)
+	sbtest.Eq(t, 4, report.Problems[3].Row)
+	sbtest.ContainsError(t, types.MalformedJSONLErr, report.Problems[3].Err)
+
+	numClients, err := logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numClients, 0)
+	numExercises, err := logic.ReadNumExercises(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numExercises, int64(len(migrations.ExerciseSetupData)))
+}
+
+func bulkUploadDryRunReportColumns(t *testing.T) {
+	dir := t.TempDir()
+	for f, data := range map[string]string{
+		"exercises/exercises.csv": "Name,KindId,FocusId\n" +
+			"colOne,MainCompound,Squat\n" +
+			"colTwo,asdf,Squat\n",
+		"workouts/one@gmail.com.jsonl": `{"Session":"asdf"}` + "\n",
+		"workouts/two@gmail.com.csv": "Exercise,DatePerformed,Weight,Sets,Reps,Effort,Session,DataDir\n" +
+			"Squat,2/21/2023,asdf,2,1,8,1,\n",
+	} {
+		sbtest.Nil(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(f)), 0755))
+		sbtest.Nil(t, os.WriteFile(filepath.Join(dir, f), []byte(data), 0644))
+	}
+
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	report := types.BulkUploadReport{}
+	err := logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		Opts:                      sbcsv.Opts{TimeFormat: "1/2/2006"},
+		BarPathCalcHyperparams:    &types.BarPathCalcHyperparams{},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		ExerciseDir:               filepath.Join(dir, "exercises"),
+		ExerciseCreateType:        types.Create,
+		WorkoutDir:                filepath.Join(dir, "workouts"),
+		DryRun:                    true,
+		Report:                    &report,
+	})
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 3, len(report.Problems))
+
+	sbtest.Eq(
+		t, filepath.Join(dir, "exercises/exercises.csv"),
+		report.Problems[0].File,
+	)
+	sbtest.Eq(t, 3, report.Problems[0].Row)
+	sbtest.Eq(t, "KindId", report.Problems[0].Column)
+
+	sbtest.Eq(
+		t, filepath.Join(dir, "workouts/one@gmail.com.jsonl"),
+		report.Problems[1].File,
+	)
+	sbtest.Eq(t, 1, report.Problems[1].Row)
+	sbtest.Eq(t, "Session", report.Problems[1].Column)
+	sbtest.ContainsError(t, types.MalformedJSONLErr, report.Problems[1].Err)
+
+	sbtest.Eq(
+		t, filepath.Join(dir, "workouts/two@gmail.com.csv"),
+		report.Problems[2].File,
+	)
+	sbtest.Eq(t, 2, report.Problems[2].Row)
+	sbtest.Eq(t, "Weight", report.Problems[2].Column)
+}
+
+func bulkUploadSkipRejectedRows(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{
+		"dryRunClientData/clients.csv",
+		"dryRunExerciseData/exercises.jsonl",
+	} {
+		data, err := os.ReadFile(filepath.Join("./testData", f))
+		sbtest.Nil(t, err)
+		sbtest.Nil(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(f)), 0755))
+		sbtest.Nil(t, os.WriteFile(filepath.Join(dir, f), data, 0644))
+	}
+
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:   filepath.Join(dir, "dryRunClientData"),
+		ErrorPolicy: types.SkipRejectedRows,
+	})
+	sbtest.ContainsError(
+		t, types.BulkDataUploadErr, err,
+		`When ErrorPolicy is SkipRejectedRows Counts must not be nil`,
+	)
+
+	counts := types.UploadCounts{}
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:          filepath.Join(dir, "dryRunClientData"),
+		ClientCreateType:   types.Create,
+		ExerciseDir:        filepath.Join(dir, "dryRunExerciseData"),
+		ExerciseCreateType: types.Create,
+		ErrorPolicy:        types.SkipRejectedRows,
+		Counts:             &counts,
+	})
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, types.UploadCounts{Accepted: 4, Rejected: 3}, counts)
+
+	numClients, err := logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numClients, 2)
+	numExercises, err := logic.ReadNumExercises(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numExercises, int64(len(migrations.ExerciseSetupData)+2))
+
+	rejected := readRejectedFile(
+		t, filepath.Join(dir, "dryRunClientData/clients.rejected.csv"),
+	)
+	sbtest.Eq(t, 2, len(rejected))
+	sbtest.SlicesMatch(
+		t, []string{"FirstName", "LastName", "Email", "Error"}, rejected[0],
+	)
+	sbtest.SlicesMatch(
+		t, []string{"TwoFN", "TwoLN", "one@gmail.com"}, rejected[1][:3],
+	)
+
+	rejected = readRejectedFile(
+		t, filepath.Join(dir, "dryRunExerciseData/exercises.rejected.csv"),
+	)
+	sbtest.Eq(t, 3, len(rejected))
+	sbtest.SlicesMatch(t, []string{"Line", "Value", "Error"}, rejected[0])
+	sbtest.Eq(t, "2", rejected[1][0])
+	sbtest.Eq(t, `{"Name":"two","KindId":"asdf","FocusId":"Squat"}`, rejected[1][1])
+	sbtest.Eq(t, "4", rejected[2][0])
+
+	// Rejected files are not loaded by later uploads and are replaced by
+	// them
+	counts = types.UploadCounts{}
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:        filepath.Join(dir, "dryRunClientData"),
+		ClientCreateType: types.EnsureExists,
+		ErrorPolicy:      types.SkipRejectedRows,
+		Counts:           &counts,
+	})
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, types.UploadCounts{Accepted: 3, Rejected: 0}, counts)
+	_, err = os.Stat(filepath.Join(dir, "dryRunClientData/clients.rejected.csv"))
+	sbtest.True(t, os.IsNotExist(err))
+}
+
+func bulkUploadSkipRejectedWorkoutRows(t *testing.T) {
+	dir := t.TempDir()
+	sbtest.Nil(t, os.WriteFile(
+		filepath.Join(dir, "two@gmail.com.csv"),
+		[]byte(
+			"Exercise,DatePerformed,Weight,Sets,Reps,Effort,Session,DataDir\n"+
+				"Squat,2/21/2023,245,5,5,6,1,\n"+
+				"Bench,2/21/2023,asdf,3,8,6,1,\n"+
+				"Deadlift,2/21/2023,315,3,5,8,1,\n"+
+				"Squat,2/22/2023,245,5,5,6,1,\n",
+		),
+		0644,
+	))
+
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "TwoFN",
+		LastName:  "TwoLN",
+		Email:     "two@gmail.com",
+	})
+	sbtest.Nil(t, err)
+
+	counts := types.UploadCounts{}
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		WorkoutDir: dir,
+		BarPathCalcHyperparams: &types.BarPathCalcHyperparams{
+			MinNumSamples: 5,
+			ApproxErr:     types.SecondOrder,
+			NoiseFilter:   1,
+		},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		Opts:                      sbcsv.Opts{TimeFormat: "1/2/2006"},
+		ErrorPolicy:               types.SkipRejectedRows,
+		Counts:                    &counts,
+	})
+	sbtest.Nil(t, err)
+	// The row that cannot be parsed rejects every row of its workout
+	sbtest.Eq(t, types.UploadCounts{Accepted: 1, Rejected: 3}, counts)
+
+	numWorkouts, err := logic.ReadNumWorkoutsForClient(ctxt, "two@gmail.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, numWorkouts)
+	_, err = logic.ReadWorkoutsById(ctxt, types.WorkoutId{
+		ClientEmail:   "two@gmail.com",
+		Session:       1,
+		DatePerformed: time.Date(2023, 2, 21, 0, 0, 0, 0, time.UTC),
+	})
+	sbtest.ContainsError(t, types.NotFoundErr, err)
+
+	rejected := readRejectedFile(
+		t, filepath.Join(dir, "two@gmail.com.rejected.csv"),
+	)
+	sbtest.Eq(t, 4, len(rejected))
+	sbtest.Eq(t, "Error", rejected[0][len(rejected[0])-1])
+	for i, exercise := range []string{"Squat", "Bench", "Deadlift"} {
+		sbtest.Eq(t, exercise, rejected[i+1][0])
+		sbtest.Eq(t, "2/21/2023", rejected[i+1][1])
+	}
+}
+
+func readRejectedFile(t *testing.T, file string) [][]string {
+	f, err := os.Open(file)
+	sbtest.Nil(t, err)
+	defer f.Close()
+	res, err := csv.NewReader(f).ReadAll()
+	sbtest.Nil(t, err)
+	return res
+}
+
+func bulkUploadResume(t *testing.T) {
+	badWorkoutDir := t.TempDir()
+	sbtest.Nil(t, os.WriteFile(
+		filepath.Join(badWorkoutDir, "notAnEmail.csv"),
+		[]byte("DatePerformed,Session,Exercise,Weight,Sets,Reps,Effort,DataDir\n"),
+		0644,
+	))
+
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	opts := types.BulkUploadDataOpts{
+		ClientDir:             "./testData/clientData",
+		ClientCreateType:      types.Create,
+		ExerciseDir:           "./testData/exerciseData",
+		ExerciseCreateType:    types.Create,
+		HyperparamsDir:        "./testData/hyperparamData",
+		HyperparamsCreateType: types.Create,
+		WorkoutDir:            badWorkoutDir,
+		BarPathCalcHyperparams: &types.BarPathCalcHyperparams{
+			MinNumSamples: 5,
+			ApproxErr:     types.SecondOrder,
+			NoiseFilter:   1,
+		},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		Opts:                      sbcsv.Opts{TimeFormat: "1/2/2006"},
+		Resume:                    true,
+	}
+	err := logic.BulkUploadData(ctxt, &opts)
+	sbtest.ContainsError(t, types.BulkDataUploadErr, err)
+
+	// The committed chunks remain even though the upload failed
+	numClients, err := logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numClients, 2)
+	numExercises, err := logic.ReadNumExercises(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numExercises, int64(len(migrations.ExerciseSetupData)+3))
+
+	// Resuming skips the committed chunks, which would fail to be created
+	// again, and uploads the rest
+	opts.WorkoutDir = "./testData/workoutData"
+	err = logic.BulkUploadData(ctxt, &opts)
+	sbtest.Nil(t, err)
+
+	numClients, err = logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numClients, 2)
+	numHyperparams, err := logic.ReadNumHyperparams(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numHyperparams, numDefaultHyperparams+4)
+	numWorkouts, err := logic.ReadNumWorkoutsForClient(ctxt, "two@gmail.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numWorkouts, 3)
+
+	// The journal is cleared once the upload succeeds so nothing is skipped
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:        "./testData/clientData",
+		ClientCreateType: types.Create,
+		Resume:           true,
+	})
+	sbtest.ContainsError(t, types.CouldNotCreateAllClientsErr, err)
+
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:   "./testData/clientData",
+		ErrorPolicy: types.SkipRejectedRows,
+		Counts:      &types.UploadCounts{},
+		Resume:      true,
+	})
+	sbtest.ContainsError(
+		t, types.BulkDataUploadErr, err,
+		`Resume cannot be used with the SkipRejectedRows error policy`,
+	)
 }
@@ -2,11 +2,12 @@ package tests
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"code.barbellmath.net/barbell-math/providentia/lib/logic"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
-	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
 	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
 )
 
@@ -20,6 +21,7 @@ func TestClient(t *testing.T) {
 	t.Run("createDeleteRead", clientCreateDeleteRead)
 	t.Run("createCSVRead", clientCreateCSVRead)
 	t.Run("ensureCSVRead", clientEnsureCSVRead)
+	t.Run("createCSVSkipRejectedRows", clientCreateCSVSkipRejectedRows)
 }
 
 func clientFailingNoWrites(t *testing.T) {
@@ -141,6 +143,7 @@ func clientCreateRead(t *testing.T) {
 		t, types.CouldNotReadAllClientsErr, err,
 		"Only read 0 entries out of batch of 1 requests",
 	)
+	sbtest.ContainsError(t, types.NotFoundErr, err)
 
 	err = logic.CreateClients(ctxt, clients...)
 	sbtest.ContainsError(
@@ -328,6 +331,7 @@ func clientCreateDeleteRead(t *testing.T) {
 		t, types.CouldNotDeleteAllClientsErr, err,
 		`Could not delete entry with id 'email@email.com' \(Does id exist\?\)`,
 	)
+	sbtest.ContainsError(t, types.NotFoundErr, err)
 
 	n, err = logic.ReadNumClients(ctxt)
 	sbtest.Nil(t, err)
@@ -339,7 +343,7 @@ func clientCreateCSVRead(t *testing.T) {
 	t.Cleanup(cleanup)
 
 	err := logic.CreateClientsFromCSV(
-		ctxt, &sbcsv.Opts{}, "./testData/clientData/clients.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/clientData/clients.csv",
 	)
 	sbtest.Nil(t, err)
 
@@ -367,7 +371,7 @@ func clientCreateCSVRead(t *testing.T) {
 	sbtest.ContainsError(t, types.CouldNotReadAllClientsErr, err)
 
 	err = logic.CreateClientsFromCSV(
-		ctxt, &sbcsv.Opts{}, "./testData/clientData/clients.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/clientData/clients.csv",
 	)
 	sbtest.ContainsError(t, types.CSVLoaderJobQueueErr, err)
 	sbtest.ContainsError(
@@ -385,7 +389,7 @@ func clientEnsureCSVRead(t *testing.T) {
 	t.Cleanup(cleanup)
 
 	err := logic.EnsureClientsExistFromCSV(
-		ctxt, &sbcsv.Opts{}, "./testData/clientData/clients.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/clientData/clients.csv",
 	)
 	sbtest.Nil(t, err)
 
@@ -413,7 +417,7 @@ func clientEnsureCSVRead(t *testing.T) {
 	sbtest.ContainsError(t, types.CouldNotReadAllClientsErr, err)
 
 	err = logic.EnsureClientsExistFromCSV(
-		ctxt, &sbcsv.Opts{}, "./testData/clientData/clients.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/clientData/clients.csv",
 	)
 	sbtest.Nil(t, err)
 
@@ -421,3 +425,45 @@ func clientEnsureCSVRead(t *testing.T) {
 	sbtest.Nil(t, err)
 	sbtest.Eq(t, 2, n)
 }
+
+func clientCreateCSVSkipRejectedRows(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	data, err := os.ReadFile("./testData/dryRunClientData/clients.csv")
+	sbtest.Nil(t, err)
+	file := filepath.Join(t.TempDir(), "clients.csv")
+	sbtest.Nil(t, os.WriteFile(file, data, 0644))
+
+	err = logic.CreateClientsFromCSV(
+		ctxt, &types.FromCSVOpts{ErrorPolicy: types.SkipRejectedRows}, file,
+	)
+	sbtest.ContainsError(
+		t, types.CSVLoaderJobQueueErr, err,
+		`When ErrorPolicy is SkipRejectedRows Counts must not be nil`,
+	)
+
+	counts := types.UploadCounts{}
+	err = logic.CreateClientsFromCSV(
+		ctxt,
+		&types.FromCSVOpts{
+			ErrorPolicy: types.SkipRejectedRows,
+			Counts:      &counts,
+		},
+		file,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, types.UploadCounts{Accepted: 2, Rejected: 1}, counts)
+
+	n, err := logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 2, n)
+
+	rejected := readRejectedFile(
+		t, filepath.Join(filepath.Dir(file), "clients.rejected.csv"),
+	)
+	sbtest.Eq(t, 2, len(rejected))
+	sbtest.SlicesMatch(
+		t, []string{"TwoFN", "TwoLN", "one@gmail.com"}, rejected[1][:3],
+	)
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestEstimatedMax(t *testing.T) {
+	t.Run("readWorkouts", estimatedMaxReadWorkouts)
+	t.Run("dateRange", estimatedMaxDateRange)
+	t.Run("invalidArgs", estimatedMaxInvalidArgs)
+}
+
+func estimatedMaxSetup(t *testing.T) (context.Context, func()) {
+	ctxt, cleanup := resetApp(t, context.Background())
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+	_, err = logic.CreateWorkouts(ctxt, trainingWorkouts()...)
+	sbtest.Nil(t, err)
+	return ctxt, cleanup
+}
+
+func estimatedMaxReadWorkouts(t *testing.T) {
+	ctxt, cleanup := estimatedMaxSetup(t)
+	t.Cleanup(cleanup)
+
+	workouts := trainingWorkouts()
+	res, err := logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(res))
+	sbtest.Eq(t, 2, len(res[0].Exercises))
+	sbtest.True(t, res[0].Exercises[0].EstimatedMax.Present)
+	sbtest.EqFloat(
+		t, 350.0/3, float64(res[0].Exercises[0].EstimatedMax.Value), 1e-6,
+	)
+	sbtest.True(t, res[0].Exercises[1].EstimatedMax.Present)
+	sbtest.EqFloat(
+		t, 50.0*4/3, float64(res[0].Exercises[1].EstimatedMax.Value), 1e-6,
+	)
+
+	summaries, err := logic.ReadWorkoutSummariesById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(summaries))
+	sbtest.True(t, summaries[0].Exercises[0].EstimatedMax.Present)
+	sbtest.EqFloat(
+		t, 350.0/3, float64(summaries[0].Exercises[0].EstimatedMax.Value), 1e-6,
+	)
+}
+
+func estimatedMaxDateRange(t *testing.T) {
+	ctxt, cleanup := estimatedMaxSetup(t)
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, formula := range []struct {
+		Formula  types.EstimatedMaxFormula
+		Expected []types.Kilogram
+	}{
+		{Formula: types.Epley, Expected: []types.Kilogram{350.0 / 3, 770.0 / 6}},
+		{Formula: types.Brzycki, Expected: []types.Kilogram{112.5, 123.75}},
+		{Formula: types.RPETable, Expected: []types.Kilogram{100 / 0.811, 110 / 0.837}},
+	} {
+		res, err := logic.ReadEstimatedMaxesInDateRange(
+			ctxt, "email@email.com", "Squat", start, end, formula.Formula,
+		)
+		sbtest.Nil(t, err)
+		sbtest.Eq(t, 2, len(res))
+		sbtest.True(t, util.DateEqual(
+			time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), res[0].DatePerformed,
+		))
+		sbtest.True(t, util.DateEqual(
+			time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), res[1].DatePerformed,
+		))
+		for i := range res {
+			sbtest.Eq(t, "email@email.com", res[i].ClientEmail)
+			sbtest.Eq(t, "Squat", res[i].Exercise)
+			sbtest.EqFloat(
+				t, float64(formula.Expected[i]),
+				float64(res[i].EstimatedMax), 1e-4,
+			)
+		}
+	}
+
+	res, err := logic.ReadEstimatedMaxesInDateRange(
+		ctxt, "email@email.com", "Squat", start,
+		time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), types.Epley,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(res))
+
+	res, err = logic.ReadEstimatedMaxesInDateRange(
+		ctxt, "asdf@email.com", "Squat", start, end, types.Epley,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(res))
+}
+
+func estimatedMaxInvalidArgs(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := logic.ReadEstimatedMaxesInDateRange(
+		ctxt, "email@email.com", "Squat", end, start, types.Epley,
+	)
+	sbtest.ContainsError(
+		t, types.CouldNotReadAllEstimatedMaxesErr, err,
+		`Start date \(.*\) must be before end date \(.*\)`,
+	)
+
+	_, err = logic.ReadEstimatedMaxesInDateRange(
+		ctxt, "email@email.com", "Squat", start, end,
+		types.EstimatedMaxFormula(-1),
+	)
+	sbtest.ContainsError(t, types.InvalidEstimatedMaxFormulaErr, err)
+}
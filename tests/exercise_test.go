@@ -9,7 +9,6 @@ import (
 	"code.barbellmath.net/barbell-math/providentia/internal/dal/migrations"
 	"code.barbellmath.net/barbell-math/providentia/lib/logic"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
-	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
 	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
 )
 
@@ -279,7 +278,7 @@ func exerciseCreateCSVRead(t *testing.T) {
 	t.Cleanup(cleanup)
 
 	err := logic.CreateExercisesFromCSV(
-		ctxt, &sbcsv.Opts{}, "./testData/exerciseData/exercises.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/exerciseData/exercises.csv",
 	)
 	sbtest.Nil(t, err)
 
@@ -307,7 +306,7 @@ func exerciseCreateCSVRead(t *testing.T) {
 	sbtest.ContainsError(t, types.CouldNotReadAllExercisesErr, err)
 
 	err = logic.CreateExercisesFromCSV(
-		ctxt, &sbcsv.Opts{}, "./testData/exerciseData/exercises.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/exerciseData/exercises.csv",
 	)
 	sbtest.ContainsError(t, types.CSVLoaderJobQueueErr, err)
 	sbtest.ContainsError(
@@ -325,7 +324,7 @@ func exerciseEnsureCSVRead(t *testing.T) {
 	t.Cleanup(cleanup)
 
 	err := logic.EnsureExercisesExistFromCSV(
-		ctxt, &sbcsv.Opts{}, "./testData/exerciseData/exercises.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/exerciseData/exercises.csv",
 	)
 	sbtest.Nil(t, err)
 
@@ -353,7 +352,7 @@ func exerciseEnsureCSVRead(t *testing.T) {
 	sbtest.ContainsError(t, types.CouldNotReadAllExercisesErr, err)
 
 	err = logic.EnsureExercisesExistFromCSV(
-		ctxt, &sbcsv.Opts{}, "./testData/exerciseData/exercises.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/exerciseData/exercises.csv",
 	)
 	sbtest.Nil(t, err)
 
@@ -8,13 +8,13 @@ import (
 	"code.barbellmath.net/barbell-math/providentia/internal/dal/migrations"
 	"code.barbellmath.net/barbell-math/providentia/lib/logic"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
-	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
 	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
 )
 
 var (
 	numDefaultHyperparams = int64(len(migrations.BarPathCalcHyperparamsSetupData) +
-		len(migrations.BarPathTrackerHyperparamsSetupData))
+		len(migrations.BarPathTrackerHyperparamsSetupData) +
+		len(migrations.FitnessFatigueHyperparamsSetupData))
 )
 
 func TestHyperparams(t *testing.T) {
@@ -35,6 +35,7 @@ func hyperparamsFailingNoWrites(t *testing.T) {
 
 	t.Run("invalidBarPathCalc", hyperparamsInvalidBarPathCalc(ctxt))
 	t.Run("invalidBarPathTracker", hyperparamsInvalidBarPathTracker(ctxt))
+	t.Run("invalidFitnessFatigue", hyperparamsInvalidFitnessFatigue(ctxt))
 
 	n, err := logic.ReadNumHyperparams(ctxt)
 	sbtest.Nil(t, err)
@@ -142,6 +143,62 @@ func hyperparamsInvalidBarPathTracker(ctxt context.Context) func(t *testing.T) {
 	}
 }
 
+func hyperparamsInvalidFitnessFatigue(ctxt context.Context) func(t *testing.T) {
+	return func(t *testing.T) {
+		err := logic.CreateHyperparams(ctxt, types.FitnessFatigueHyperparams{
+			TimeFrame:     0,
+			FitnessDecay:  42,
+			FatigueDecay:  7,
+			MinNumSamples: 5,
+		})
+		sbtest.ContainsError(
+			t, types.CouldNotCreateAllHyperparamsErr, err,
+			`ERROR: COPY from stdin failed: Invalid fitness fatigue conf`,
+			`Invalid time frame`,
+			`Must be >0. Got: 0 \(SQLSTATE 57014\)`,
+		)
+
+		err = logic.CreateHyperparams(ctxt, types.FitnessFatigueHyperparams{
+			TimeFrame:     180,
+			FitnessDecay:  42,
+			FatigueDecay:  0,
+			MinNumSamples: 5,
+		})
+		sbtest.ContainsError(
+			t, types.CouldNotCreateAllHyperparamsErr, err,
+			`ERROR: COPY from stdin failed: Invalid fitness fatigue conf`,
+			`Invalid decay`,
+			`Fatigue decay must be >0. Got: 0.000000 \(SQLSTATE 57014\)`,
+		)
+
+		err = logic.CreateHyperparams(ctxt, types.FitnessFatigueHyperparams{
+			TimeFrame:     180,
+			FitnessDecay:  7,
+			FatigueDecay:  7,
+			MinNumSamples: 5,
+		})
+		sbtest.ContainsError(
+			t, types.CouldNotCreateAllHyperparamsErr, err,
+			`ERROR: COPY from stdin failed: Invalid fitness fatigue conf`,
+			`Invalid decay`,
+			`Fitness decay \(7.000000\) must be > fatigue decay \(7.000000\) \(SQLSTATE 57014\)`,
+		)
+
+		err = logic.CreateHyperparams(ctxt, types.FitnessFatigueHyperparams{
+			TimeFrame:     180,
+			FitnessDecay:  42,
+			FatigueDecay:  7,
+			MinNumSamples: 2,
+		})
+		sbtest.ContainsError(
+			t, types.CouldNotCreateAllHyperparamsErr, err,
+			`ERROR: COPY from stdin failed: Invalid fitness fatigue conf`,
+			`Invalid min num samples`,
+			`Must be >=3. Got: 2 \(SQLSTATE 57014\)`,
+		)
+	}
+}
+
 func hyperparamsDefaults(t *testing.T) {
 	ctxt, cleanup := resetApp(t, context.Background())
 	t.Cleanup(cleanup)
@@ -154,6 +211,10 @@ func hyperparamsDefaults(t *testing.T) {
 	sbtest.Nil(t, err)
 	sbtest.Eq(t, res2, migrations.BarPathTrackerHyperparamsSetupData[0])
 
+	res3, err := logic.ReadDefaultHyperparamsFor[types.FitnessFatigueHyperparams](ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, res3, migrations.FitnessFatigueHyperparamsSetupData[0])
+
 	n, err := logic.ReadNumHyperparams(ctxt)
 	sbtest.Nil(t, err)
 	sbtest.Eq(t, numDefaultHyperparams, n)
@@ -437,7 +498,7 @@ func hyperparamsCreateCSVRead(t *testing.T) {
 	t.Cleanup(cleanup)
 
 	err := logic.CreateHyperparamsFromCSV[types.BarPathCalcHyperparams](
-		ctxt, &sbcsv.Opts{}, "./testData/hyperparamData/1.barPathCalc.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/hyperparamData/1.barPathCalc.csv",
 	)
 	sbtest.Nil(t, err)
 
@@ -481,7 +542,7 @@ func hyperparamsCreateCSVRead(t *testing.T) {
 	sbtest.ContainsError(t, types.CouldNotReadAllHyperparamsErr, err)
 
 	err = logic.CreateHyperparamsFromCSV[types.BarPathCalcHyperparams](
-		ctxt, &sbcsv.Opts{}, "./testData/hyperparamData/1.barPathCalc.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/hyperparamData/1.barPathCalc.csv",
 	)
 	sbtest.ContainsError(t, types.CSVLoaderJobQueueErr, err)
 	sbtest.ContainsError(
@@ -499,7 +560,7 @@ func hyperparamsEnsureCSVRead(t *testing.T) {
 	t.Cleanup(cleanup)
 
 	err := logic.EnsureHyperparamsExistFromCSV[types.BarPathCalcHyperparams](
-		ctxt, &sbcsv.Opts{}, "./testData/hyperparamData/1.barPathCalc.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/hyperparamData/1.barPathCalc.csv",
 	)
 	sbtest.Nil(t, err)
 
@@ -543,7 +604,7 @@ func hyperparamsEnsureCSVRead(t *testing.T) {
 	sbtest.ContainsError(t, types.CouldNotReadAllHyperparamsErr, err)
 
 	err = logic.EnsureHyperparamsExistFromCSV[types.BarPathCalcHyperparams](
-		ctxt, &sbcsv.Opts{}, "./testData/hyperparamData/1.barPathCalc.csv",
+		ctxt, &types.FromCSVOpts{}, "./testData/hyperparamData/1.barPathCalc.csv",
 	)
 	sbtest.Nil(t, err)
 
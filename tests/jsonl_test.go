@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestJSONL(t *testing.T) {
+	t.Run("roundTrip", jsonlRoundTrip)
+	t.Run("malformed", jsonlMalformed)
+	t.Run("bulkUpload", jsonlBulkUpload)
+	t.Run("badWorkoutEmail", jsonlBadWorkoutEmail)
+	t.Run("badPhysData", jsonlBadPhysData)
+}
+
+func jsonlRoundTrip(t *testing.T) {
+	clients := []types.Client{
+		{FirstName: "OneFN", LastName: "OneLN", Email: "one@gmail.com"},
+		{FirstName: "TwoFN", LastName: "TwoLN", Email: "two@gmail.com"},
+	}
+	var buf bytes.Buffer
+	err := logic.WriteJSONL(&buf, clients...)
+	sbtest.Nil(t, err)
+	sbtest.Eq(
+		t,
+		`{"FirstName":"OneFN","LastName":"OneLN","Email":"one@gmail.com"}`+"\n"+
+			`{"FirstName":"TwoFN","LastName":"TwoLN","Email":"two@gmail.com"}`+"\n",
+		buf.String(),
+	)
+	res, err := logic.ReadJSONL[types.Client](&buf)
+	sbtest.Nil(t, err)
+	sbtest.SlicesMatch(t, clients, res)
+
+	exercises := []types.Exercise{
+		{Name: "Squat", KindId: types.MainCompound, FocusId: types.Squat},
+	}
+	err = logic.WriteJSONL(&buf, exercises...)
+	sbtest.Nil(t, err)
+	sbtest.Eq(
+		t,
+		`{"Name":"Squat","KindId":"MainCompound","FocusId":"Squat"}`+"\n",
+		buf.String(),
+	)
+	exerciseRes, err := logic.ReadJSONL[types.Exercise](&buf)
+	sbtest.Nil(t, err)
+	sbtest.SlicesMatch(t, exercises, exerciseRes)
+}
+
+func jsonlMalformed(t *testing.T) {
+	_, err := logic.ReadJSONL[types.Client](strings.NewReader(
+		`{"FirstName":"OneFN","LastName":"OneLN","Email":"one@gmail.com"}` +
+			"\n\n" + `{"FirstName":"TwoFN","Unknown":1}` + "\n",
+	))
+	sbtest.ContainsError(t, types.MalformedJSONLErr, err, `Line 3`)
+
+	_, err = logic.ReadJSONL[types.Client](strings.NewReader(`{"FirstName":`))
+	sbtest.ContainsError(t, types.MalformedJSONLErr, err, `Line 1`)
+}
+
+func jsonlBulkUpload(t *testing.T) {
+	dir := t.TempDir()
+	sbtest.Nil(t, os.Mkdir(filepath.Join(dir, "clients"), 0755))
+	sbtest.Nil(t, os.Mkdir(filepath.Join(dir, "workouts"), 0755))
+
+	ctxt, cleanup := workoutExportUpload(t, "./testData/workoutData", "1/2/2006")
+	clients, err := logic.ReadClientsByEmail(
+		ctxt, "one@gmail.com", "two@gmail.com",
+	)
+	sbtest.Nil(t, err)
+	expected, err := logic.FindWorkoutsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+	cleanup()
+
+	f, err := os.Create(filepath.Join(dir, "clients", "clients.jsonl"))
+	sbtest.Nil(t, err)
+	sbtest.Nil(t, logic.WriteJSONL(f, clients...))
+	sbtest.Nil(t, f.Close())
+	f, err = os.Create(filepath.Join(dir, "workouts", "two@gmail.com.jsonl"))
+	sbtest.Nil(t, err)
+	sbtest.Nil(t, logic.WriteJSONL(f, expected...))
+	sbtest.Nil(t, f.Close())
+
+	ctxt, cleanup = resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:                 filepath.Join(dir, "clients"),
+		ClientCreateType:          types.Create,
+		ExerciseDir:               "./testData/exerciseData",
+		ExerciseCreateType:        types.Create,
+		HyperparamsDir:            "./testData/hyperparamData",
+		HyperparamsCreateType:     types.Create,
+		WorkoutDir:                filepath.Join(dir, "workouts"),
+		BarPathCalcHyperparams:    &types.BarPathCalcHyperparams{},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+	})
+	sbtest.Nil(t, err)
+
+	numClients, err := logic.ReadNumClients(ctxt)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, int64(len(clients)), numClients)
+	res, err := logic.FindWorkoutsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 3, len(res))
+	exportedWorkoutsEqual(t, expected, res)
+}
+
+func jsonlBadWorkoutEmail(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(
+		filepath.Join(dir, "two@gmail.com.jsonl"),
+		[]byte(`{"ClientEmail":"one@gmail.com","Session":1,"DatePerformed":"2023-01-01T00:00:00Z"}`+"\n"),
+		0644,
+	)
+	sbtest.Nil(t, err)
+
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		WorkoutDir:                dir,
+		BarPathCalcHyperparams:    &types.BarPathCalcHyperparams{},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+	})
+	sbtest.ContainsError(t, types.BulkDataUploadErr, err)
+	sbtest.ContainsError(t, types.CSVLoaderJobQueueErr, err)
+	sbtest.ContainsError(
+		t, types.MalformedJSONLErr, err,
+		`Workout client email \(one@gmail.com\) does not match the file name \(two@gmail.com\)`,
+	)
+}
+
+func jsonlBadPhysData(t *testing.T) {
+	ctxt, cleanup := workoutExportUpload(t, "./testData/workoutData", "1/2/2006")
+	workouts, err := logic.FindWorkoutsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+	cleanup()
+
+	var w *types.Workout
+	var physData *types.PhysicsData
+	for i := range workouts {
+		for j := range workouts[i].Exercises {
+			for k, p := range workouts[i].Exercises[j].PhysData {
+				if p.Present && physData == nil {
+					w = &workouts[i]
+					physData = &workouts[i].Exercises[j].PhysData[k].Value
+				}
+			}
+		}
+	}
+	sbtest.True(t, physData != nil)
+	physData.Velocity = physData.Velocity[:len(physData.Velocity)-1]
+
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "two@gmail.com.jsonl"))
+	sbtest.Nil(t, err)
+	sbtest.Nil(t, logic.WriteJSONL(f, *w))
+	sbtest.Nil(t, f.Close())
+
+	ctxt, cleanup = resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+	err = logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		WorkoutDir:                dir,
+		BarPathCalcHyperparams:    &types.BarPathCalcHyperparams{},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+	})
+	sbtest.ContainsError(t, types.BulkDataUploadErr, err)
+	sbtest.ContainsError(t, types.MalformedJSONLErr, err, `Line 1`)
+	sbtest.ContainsError(
+		t, types.InvalidRawDataLenErr, err, `Expected velocity slice of len`,
+	)
+}
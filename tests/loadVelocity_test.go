@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestLoadVelocity(t *testing.T) {
+	t.Run("profile", loadVelocityProfile)
+	t.Run("notEnoughData", loadVelocityNotEnoughData)
+}
+
+// Creates physics data where each rep moves up at the supplied velocity for one
+// second and then back down for one second. The mean concentric velocity of
+// each rep is set to match.
+func loadVelocityPhysData(repVels ...float64) types.Optional[types.PhysicsData] {
+	res := testPhysicsData1
+	res.Time = []types.Second{}
+	res.Velocity = []types.Vec2[types.MeterPerSec, types.MeterPerSec]{}
+	res.RepSplits = []types.Split{}
+	res.MeanConcentricVel = []types.MeterPerSec{}
+	for _, v := range repVels {
+		start := int64(len(res.Time))
+		for i := range 20 {
+			res.Time = append(res.Time, types.Second(len(res.Time))*0.1)
+			y := types.MeterPerSec(v)
+			if i >= 10 {
+				y = -y
+			}
+			res.Velocity = append(
+				res.Velocity,
+				types.Vec2[types.MeterPerSec, types.MeterPerSec]{Y: y},
+			)
+		}
+		res.RepSplits = append(res.RepSplits, types.Split{
+			StartIdx: start,
+			EndIdx:   int64(len(res.Time)),
+		})
+		res.MeanConcentricVel = append(
+			res.MeanConcentricVel, types.MeterPerSec(v),
+		)
+	}
+	return types.Optional[types.PhysicsData]{Present: true, Value: res}
+}
+
+func loadVelocityProfile(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	// velocity = 1.5 - 0.01*weight
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	workouts := []types.Workout{
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: start,
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Squat",
+					Weight: 60,
+					Sets:   2,
+					Reps:   2,
+					Effort: 5,
+					PhysData: []types.Optional[types.PhysicsData]{
+						loadVelocityPhysData(0.9, 0.85),
+						loadVelocityPhysData(0.9, 0.8),
+					},
+				},
+				{
+					Name:   "Squat",
+					Weight: 100,
+					Sets:   1,
+					Reps:   2,
+					Effort: 8,
+					PhysData: []types.Optional[types.PhysicsData]{
+						loadVelocityPhysData(0.5, 0.4),
+					},
+				},
+			},
+		},
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: start.AddDate(0, 0, 1),
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Squat",
+					Weight: 120,
+					Sets:   1,
+					Reps:   2,
+					Effort: 10,
+					PhysData: []types.Optional[types.PhysicsData]{
+						loadVelocityPhysData(0.3, 0.2),
+					},
+				},
+				{
+					Name:   "Bench",
+					Weight: 100,
+					Sets:   1,
+					Reps:   1,
+					Effort: 10,
+				},
+			},
+		},
+	}
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
+	sbtest.Nil(t, err)
+
+	res, err := logic.CalcLoadVelocityProfile(ctxt, "email@email.com", "Squat")
+	sbtest.Nil(t, err)
+	sbtest.EqFloat(t, 1.5, float64(res.Intercept), 1e-9)
+	sbtest.EqFloat(t, -0.01, res.Slope, 1e-9)
+	sbtest.EqFloat(t, 0.2, float64(res.MinVelThreshold), 1e-9)
+	sbtest.EqFloat(t, 130, float64(res.EstimatedMax), 1e-6)
+	sbtest.EqFloat(t, 1, res.RSquared, 1e-9)
+	sbtest.Eq(t, 4, res.NumSets)
+
+	_, err = logic.CalcLoadVelocityProfile(ctxt, "email@email.com", "Bench")
+	sbtest.ContainsError(
+		t, types.CouldNotCalcLoadVelocityProfileErr, err,
+		`Need sets with at least 2 different weights, got 0 \(from 0 sets with velocity data\)`,
+	)
+}
+
+func loadVelocityNotEnoughData(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	_, err := logic.CalcLoadVelocityProfile(ctxt, "email@email.com", "Squat")
+	sbtest.ContainsError(t, types.CouldNotCalcLoadVelocityProfileErr, err)
+	sbtest.ContainsError(t, types.NotEnoughVelocityDataErr, err)
+}
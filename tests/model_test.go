@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestModel(t *testing.T) {
+	t.Run("fitPredictFitnessFatigue", modelFitPredictFitnessFatigue)
+	t.Run("predictWithoutFit", modelPredictWithoutFit)
+}
+
+func modelWorkouts(start time.Time, numDays int) []types.Workout {
+	res := make([]types.Workout, numDays)
+	for i := range numDays {
+		res[i] = types.Workout{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: start.AddDate(0, 0, i),
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Squat",
+					Weight: 100,
+					Sets:   5,
+					Reps:   5,
+					Effort: 8,
+				},
+			},
+		}
+	}
+	return res
+}
+
+func modelFitPredictFitnessFatigue(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = logic.CreateWorkouts(ctxt, modelWorkouts(start, 30)...)
+	sbtest.Nil(t, err)
+
+	_, err = logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 5, 8, start.AddDate(0, 0, 30),
+	)
+	sbtest.ContainsError(t, types.NoModelStateErr, err)
+
+	err = logic.FitFitnessFatigueModel(ctxt, 0, "email@email.com")
+	sbtest.Nil(t, err)
+	// Refitting replaces the existing model states
+	err = logic.FitFitnessFatigueModel(ctxt, 0, "email@email.com")
+	sbtest.Nil(t, err)
+
+	// The estimated max never changes so the prediction should match the
+	// weight that was lifted
+	res, err := logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 5, 8, start.AddDate(0, 0, 30),
+	)
+	sbtest.Nil(t, err)
+	sbtest.EqFloat(t, 100, float64(res), 1e-3)
+
+	res, err = logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 1, 10, start.AddDate(0, 0, 30),
+	)
+	sbtest.Nil(t, err)
+	// With the default Epley formula a single is its own max
+	sbtest.EqFloat(t, 100*(1+5.0/30), float64(res), 1e-3)
+
+	// The first few entries do not have enough history to be fit
+	_, err = logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 5, 8, start.AddDate(0, 0, 2),
+	)
+	sbtest.ContainsError(
+		t, types.NoModelStateErr, err,
+		`Client 'email@email.com' has no model state for exercise 'Squat' on or before 2025-01-03 \(Has the model been fit\?\)`,
+	)
+
+	_, err = logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 0, 8, start.AddDate(0, 0, 30),
+	)
+	sbtest.ContainsError(
+		t, types.CouldNotPredictWeightErr, err, `Reps must be >=1, got 0`,
+	)
+
+	_, err = logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 5, 11, start.AddDate(0, 0, 30),
+	)
+	sbtest.ContainsError(
+		t, types.CouldNotPredictWeightErr, err,
+		`Effort must be in the range \[0, 10\], got 11.000000`,
+	)
+
+	err = logic.FitFitnessFatigueModel(ctxt, 1, "email@email.com")
+	sbtest.ContainsError(t, types.CouldNotCreateAllModelStatesErr, err)
+
+	// The model states were fit with Epley so predicting with any other
+	// formula must fail until the model is refit
+	state, ok := logic.StateFromContext(ctxt)
+	sbtest.True(t, ok)
+	state.Global.EstimatedMaxFormula = types.Brzycki
+	_, err = logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 5, 8, start.AddDate(0, 0, 30),
+	)
+	sbtest.ContainsError(
+		t, types.CouldNotPredictWeightErr, err,
+		`The model state was fit with the Epley formula but the current formula is Brzycki \(Refit the model\)`,
+	)
+
+	err = logic.FitFitnessFatigueModel(ctxt, 0, "email@email.com")
+	sbtest.Nil(t, err)
+	res, err = logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 5, 8, start.AddDate(0, 0, 30),
+	)
+	sbtest.Nil(t, err)
+	sbtest.EqFloat(t, 100, float64(res), 1e-3)
+}
+
+func modelPredictWithoutFit(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	_, err := logic.PredictWeight(
+		ctxt, "email@email.com", "Squat", 5, 8, time.Now(),
+	)
+	sbtest.ContainsError(t, types.NoModelStateErr, err)
+}
@@ -0,0 +1,237 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestPersonalRecord(t *testing.T) {
+	t.Run("createWorkouts", personalRecordCreateWorkouts)
+	t.Run("read", personalRecordRead)
+	t.Run("noClient", personalRecordNoClient)
+}
+
+func personalRecordWorkouts() []types.Workout {
+	workout := func(
+		day int, exercises ...types.ExerciseData,
+	) types.Workout {
+		return types.Workout{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC),
+			},
+			Exercises: exercises,
+		}
+	}
+
+	return []types.Workout{
+		workout(
+			1,
+			types.ExerciseData{
+				Name: "Squat", Weight: 100, Sets: 1, Reps: 5, Effort: 8,
+				PhysData: []types.Optional[types.PhysicsData]{
+					{Present: true, Value: testPhysicsData1},
+				},
+			},
+			types.ExerciseData{
+				Name: "Bench", Weight: 60, Sets: 1, Reps: 5, Effort: 8,
+			},
+		),
+		workout(
+			2,
+			types.ExerciseData{
+				Name: "Squat", Weight: 110, Sets: 1, Reps: 3, Effort: 9,
+			},
+			types.ExerciseData{
+				Name: "Squat", Weight: 100, Sets: 1, Reps: 5, Effort: 7,
+			},
+		),
+		workout(
+			3,
+			types.ExerciseData{
+				Name: "Squat", Weight: 105, Sets: 1, Reps: 5, Effort: 9,
+			},
+		),
+	}
+}
+
+func personalRecord(
+	day int,
+	exercise string,
+	kind types.PersonalRecordKind,
+	weight types.Kilogram,
+	reps int32,
+	value float64,
+	previous ...float64,
+) types.PersonalRecord {
+	res := types.PersonalRecord{
+		WorkoutId: types.WorkoutId{
+			ClientEmail:   "email@email.com",
+			Session:       1,
+			DatePerformed: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC),
+		},
+		Exercise: exercise,
+		Kind:     kind,
+		Weight:   weight,
+		Reps:     reps,
+		Value:    value,
+	}
+	if len(previous) > 0 {
+		res.Previous = types.Optional[float64]{Present: true, Value: previous[0]}
+	}
+	return res
+}
+
+func personalRecordsEqual(
+	t *testing.T,
+	l []types.PersonalRecord,
+	r []types.PersonalRecord,
+) {
+	sbtest.Eq(t, len(l), len(r))
+	for i := range len(l) {
+		sbtest.Eq(t, l[i].ClientEmail, r[i].ClientEmail)
+		sbtest.Eq(t, l[i].Session, r[i].Session)
+		sbtest.True(t, util.DateEqual(l[i].DatePerformed, r[i].DatePerformed))
+		sbtest.Eq(t, l[i].Exercise, r[i].Exercise)
+		sbtest.Eq(t, l[i].Kind, r[i].Kind)
+		sbtest.EqFloat(t, float64(l[i].Weight), float64(r[i].Weight), 1e-6)
+		sbtest.Eq(t, l[i].Reps, r[i].Reps)
+		sbtest.EqFloat(t, l[i].Value, r[i].Value, 1e-6)
+		sbtest.Eq(t, l[i].Previous.Present, r[i].Previous.Present)
+		sbtest.EqFloat(t, l[i].Previous.Value, r[i].Previous.Value, 1e-6)
+	}
+}
+
+func personalRecordSetup(t *testing.T) (context.Context, func()) {
+	ctxt, cleanup := resetApp(t, context.Background())
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+	return ctxt, cleanup
+}
+
+func personalRecordCreateWorkouts(t *testing.T) {
+	ctxt, cleanup := personalRecordSetup(t)
+	t.Cleanup(cleanup)
+
+	workouts := personalRecordWorkouts()
+	res, err := logic.CreateWorkouts(ctxt, workouts[:2]...)
+	sbtest.Nil(t, err)
+	// The day 1 squat estimated max is beaten by day 2 in the same call so it
+	// is not reported, only records that are still current are
+	personalRecordsEqual(t, []types.PersonalRecord{
+		personalRecord(1, "Squat", types.HeaviestWeight, 100, 5, 100),
+		personalRecord(1, "Squat", types.BestVolumeSet, 100, 5, 500),
+		personalRecord(1, "Squat", types.FastestPeakVel, 100, 5, 2),
+		personalRecord(1, "Bench", types.HeaviestWeight, 60, 5, 60),
+		personalRecord(1, "Bench", types.BestEstimatedMax, 60, 5, 70),
+		personalRecord(1, "Bench", types.BestVolumeSet, 60, 5, 300),
+		personalRecord(2, "Squat", types.HeaviestWeight, 110, 3, 110),
+		personalRecord(2, "Squat", types.BestEstimatedMax, 110, 3, 121),
+	}, res)
+
+	res, err = logic.CreateWorkouts(ctxt, workouts[2])
+	sbtest.Nil(t, err)
+	personalRecordsEqual(t, []types.PersonalRecord{
+		personalRecord(3, "Squat", types.HeaviestWeight, 105, 5, 105, 100),
+		personalRecord(3, "Squat", types.BestEstimatedMax, 105, 5, 122.5, 121),
+		personalRecord(3, "Squat", types.BestVolumeSet, 105, 5, 525, 500),
+	}, res)
+
+	// Repeating a lift does not set a record
+	res, err = logic.CreateWorkouts(ctxt, types.Workout{
+		WorkoutId: types.WorkoutId{
+			ClientEmail:   "email@email.com",
+			Session:       1,
+			DatePerformed: time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC),
+		},
+		Exercises: []types.ExerciseData{
+			{Name: "Squat", Weight: 105, Sets: 1, Reps: 5, Effort: 9},
+		},
+	})
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(res))
+
+	// A backdated workout is compared against the workouts performed after it
+	// so it does not set a record when a later workout beats it
+	res, err = logic.CreateWorkouts(ctxt, types.Workout{
+		WorkoutId: types.WorkoutId{
+			ClientEmail:   "email@email.com",
+			Session:       1,
+			DatePerformed: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		},
+		Exercises: []types.ExerciseData{
+			{Name: "Squat", Weight: 102, Sets: 1, Reps: 5, Effort: 9},
+		},
+	})
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(res))
+}
+
+func personalRecordRead(t *testing.T) {
+	ctxt, cleanup := personalRecordSetup(t)
+	t.Cleanup(cleanup)
+
+	_, err := logic.CreateWorkouts(ctxt, personalRecordWorkouts()...)
+	sbtest.Nil(t, err)
+
+	res, err := logic.ReadPersonalRecordHistory(ctxt, "email@email.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 12, len(res))
+
+	res, err = logic.ReadPersonalRecordHistory(ctxt, "email@email.com", "Bench")
+	sbtest.Nil(t, err)
+	personalRecordsEqual(t, []types.PersonalRecord{
+		personalRecord(1, "Bench", types.HeaviestWeight, 60, 5, 60),
+		personalRecord(1, "Bench", types.BestEstimatedMax, 60, 5, 70),
+		personalRecord(1, "Bench", types.BestVolumeSet, 60, 5, 300),
+	}, res)
+
+	res, err = logic.ReadPersonalRecords(ctxt, "email@email.com", "Squat")
+	sbtest.Nil(t, err)
+	personalRecordsEqual(t, []types.PersonalRecord{
+		personalRecord(1, "Squat", types.FastestPeakVel, 100, 5, 2),
+		personalRecord(2, "Squat", types.HeaviestWeight, 110, 3, 110),
+		personalRecord(3, "Squat", types.HeaviestWeight, 105, 5, 105, 100),
+		personalRecord(3, "Squat", types.BestEstimatedMax, 105, 5, 122.5, 121),
+		personalRecord(3, "Squat", types.BestVolumeSet, 105, 5, 525, 500),
+	}, res)
+
+	// Records are derived from the training log so deleting a workout removes
+	// the records it set
+	err = logic.DeleteWorkouts(ctxt, personalRecordWorkouts()[2].WorkoutId)
+	sbtest.Nil(t, err)
+	res, err = logic.ReadPersonalRecords(ctxt, "email@email.com", "Squat")
+	sbtest.Nil(t, err)
+	personalRecordsEqual(t, []types.PersonalRecord{
+		personalRecord(1, "Squat", types.HeaviestWeight, 100, 5, 100),
+		personalRecord(1, "Squat", types.BestVolumeSet, 100, 5, 500),
+		personalRecord(1, "Squat", types.FastestPeakVel, 100, 5, 2),
+		personalRecord(2, "Squat", types.HeaviestWeight, 110, 3, 110),
+		personalRecord(2, "Squat", types.BestEstimatedMax, 110, 3, 121, 350.0/3),
+	}, res)
+}
+
+func personalRecordNoClient(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	res, err := logic.ReadPersonalRecords(ctxt, "email@email.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(res))
+
+	res, err = logic.ReadPersonalRecordHistory(ctxt, "email@email.com", "Squat")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(res))
+}
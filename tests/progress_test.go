@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestProgress(t *testing.T) {
+	t.Run("func", progressFunc)
+	t.Run("chan", progressChan)
+}
+
+// Returns a context with a copy of the state in the supplied context that
+// sends progress events to the supplied sink.
+func withProgress(
+	t *testing.T,
+	ctxt context.Context,
+	sink types.ProgressSink,
+) context.Context {
+	state, ok := logic.StateFromContext(ctxt)
+	sbtest.True(t, ok)
+	s := *state
+	s.Progress = sink
+	return logic.WithStateValue(ctxt, &s)
+}
+
+func progressFunc(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	var mtx sync.Mutex
+	events := map[types.ProgressEventKind][]types.ProgressEvent{}
+	ctxt = withProgress(t, ctxt, types.ProgressFunc(func(e types.ProgressEvent) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		events[e.Kind] = append(events[e.Kind], e)
+	}))
+
+	err := logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:             "./testData/clientData",
+		ClientCreateType:      types.Create,
+		ExerciseDir:           "./testData/exerciseData",
+		ExerciseCreateType:    types.Create,
+		HyperparamsDir:        "./testData/hyperparamData",
+		HyperparamsCreateType: types.Create,
+		WorkoutDir:            "./testData/workoutData",
+		BarPathCalcHyperparams: &types.BarPathCalcHyperparams{
+			MinNumSamples: 5,
+			ApproxErr:     types.SecondOrder,
+			NoiseFilter:   1,
+		},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		Opts:                      sbcsv.Opts{TimeFormat: "1/2/2006"},
+	})
+	sbtest.Nil(t, err)
+
+	sbtest.Eq(t, 5, len(events[types.FileDiscovered]))
+	sbtest.True(t, len(events[types.ChunkScheduled]) > 0)
+	sbtest.Eq(
+		t, len(events[types.ChunkScheduled]), len(events[types.ChunkCompleted]),
+	)
+	for _, e := range events[types.ChunkCompleted] {
+		sbtest.Nil(t, e.Err)
+	}
+
+	numRows := map[string]int{}
+	for _, e := range events[types.RowsUploaded] {
+		numRows[e.File] += e.NumRows
+	}
+	sbtest.Eq(t, 2, numRows["testData/clientData/clients.csv"])
+	sbtest.Eq(t, 3, numRows["testData/exerciseData/exercises.csv"])
+	sbtest.Eq(t, 3, numRows["testData/workoutData/two@gmail.com.csv"])
+
+	sbtest.True(t, len(events[types.PhysicsJobFinished]) > 0)
+	for _, e := range events[types.PhysicsJobFinished] {
+		sbtest.Nil(t, e.Err)
+	}
+}
+
+func progressChan(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	events := make(types.ProgressChan)
+	ctxt = withProgress(t, ctxt, events)
+
+	errs := make(chan error)
+	go func() {
+		errs <- logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+			ClientDir:        "./testData/clientData",
+			ClientCreateType: types.Create,
+		})
+	}()
+
+	numRows := 0
+	kinds := []types.ProgressEventKind{}
+	for {
+		select {
+		case e := <-events:
+			if len(kinds) == 0 || kinds[len(kinds)-1] != e.Kind {
+				kinds = append(kinds, e.Kind)
+			}
+			if e.Kind == types.RowsUploaded {
+				numRows += e.NumRows
+			}
+			continue
+		case err := <-errs:
+			sbtest.Nil(t, err)
+		}
+		break
+	}
+
+	sbtest.Eq(t, 2, numRows)
+	sbtest.Eq(t, types.FileDiscovered, kinds[0])
+	sbtest.Eq(t, types.ChunkScheduled, kinds[1])
+	sbtest.Eq(t, types.ChunkCompleted, kinds[len(kinds)-1])
+}
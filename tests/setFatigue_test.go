@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestSetFatigue(t *testing.T) {
+	t.Run("readWorkouts", setFatigueReadWorkouts)
+	t.Run("dateRange", setFatigueDateRange)
+	t.Run("invalidDateRange", setFatigueInvalidDateRange)
+}
+
+func setFatiguePhysData(mcv ...types.MeterPerSec) types.Optional[types.PhysicsData] {
+	res := testPhysicsData2
+	res.MeanConcentricVel = mcv
+	return types.Optional[types.PhysicsData]{Present: true, Value: res}
+}
+
+func setFatigueWorkouts(start time.Time) []types.Workout {
+	return []types.Workout{
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: start,
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Squat",
+					Weight: 100,
+					Sets:   2,
+					Reps:   4,
+					Effort: 8,
+					PhysData: []types.Optional[types.PhysicsData]{
+						setFatiguePhysData(1, 0.9, 0.8, 0.7),
+						{},
+					},
+				},
+				{
+					Name:   "Bench",
+					Weight: 80,
+					Sets:   1,
+					Reps:   3,
+					Effort: 7,
+					PhysData: []types.Optional[types.PhysicsData]{
+						setFatiguePhysData(0.5, 0.6, 0.55),
+					},
+				},
+			},
+		},
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: start.AddDate(0, 0, 1),
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Squat",
+					Weight: 120,
+					Sets:   1,
+					Reps:   1,
+					Effort: 10,
+					PhysData: []types.Optional[types.PhysicsData]{
+						setFatiguePhysData(0.3),
+					},
+				},
+			},
+		},
+	}
+}
+
+func setFatigueReadWorkouts(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	workouts := setFatigueWorkouts(start)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
+	sbtest.Nil(t, err)
+
+	res, err := logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(res))
+	sbtest.Eq(t, 2, len(res[0].Exercises))
+
+	squat := res[0].Exercises[0].SetFatigue
+	sbtest.Eq(t, 2, len(squat))
+	sbtest.True(t, squat[0].Present)
+	sbtest.Eq(t, 1, squat[0].Value.BestRep)
+	sbtest.EqFloat(t, 30, squat[0].Value.VelocityLoss, 1e-9)
+	sbtest.Eq(t, 3, squat[0].Value.ThresholdRep)
+	sbtest.EqFloat(t, -0.1, squat[0].Value.FatigueSlope, 1e-9)
+	sbtest.False(t, squat[1].Present)
+
+	bench := res[0].Exercises[1].SetFatigue
+	sbtest.Eq(t, 1, len(bench))
+	sbtest.True(t, bench[0].Present)
+	sbtest.Eq(t, 2, bench[0].Value.BestRep)
+	sbtest.EqFloat(t, 0.6, float64(bench[0].Value.BestVel), 1e-9)
+	sbtest.EqFloat(t, 0.55, float64(bench[0].Value.LastVel), 1e-9)
+	sbtest.Eq(t, 0, bench[0].Value.ThresholdRep)
+
+	found, err := logic.FindWorkoutsInDateRange(
+		ctxt, "email@email.com", start, start.AddDate(0, 0, 2),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 2, len(found))
+	sbtest.Eq(t, 1, len(found[1].Exercises[0].SetFatigue))
+	sbtest.True(t, found[1].Exercises[0].SetFatigue[0].Present)
+	sbtest.EqFloat(
+		t, 0, found[1].Exercises[0].SetFatigue[0].Value.VelocityLoss, 1e-9,
+	)
+}
+
+func setFatigueDateRange(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = logic.CreateWorkouts(ctxt, setFatigueWorkouts(start)...)
+	sbtest.Nil(t, err)
+
+	res, err := logic.ReadSetFatigueInDateRange(
+		ctxt, "email@email.com", start, start.AddDate(0, 0, 2),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 3, len(res))
+
+	sbtest.Eq(t, "Squat", res[0].Exercise)
+	sbtest.Eq(t, 100, res[0].Weight)
+	sbtest.Eq(t, 4, res[0].Reps)
+	sbtest.Eq(t, 1, res[0].SetNum)
+	sbtest.EqFloat(t, 30, res[0].VelocityLoss, 1e-9)
+	sbtest.Eq(t, 3, res[0].ThresholdRep)
+
+	sbtest.Eq(t, "Bench", res[1].Exercise)
+	sbtest.Eq(t, 1, res[1].SetNum)
+	sbtest.Eq(t, 2, res[1].BestRep)
+
+	sbtest.Eq(t, "Squat", res[2].Exercise)
+	sbtest.Eq(t, 120, res[2].Weight)
+	sbtest.Eq(t, "email@email.com", res[2].ClientEmail)
+	sbtest.Eq(t, 1, res[2].Session)
+
+	res, err = logic.ReadSetFatigueInDateRange(
+		ctxt, "email@email.com", start.AddDate(0, 0, 1), start.AddDate(0, 0, 2),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(res))
+	sbtest.Eq(t, 120, res[0].Weight)
+
+	res, err = logic.ReadSetFatigueInDateRange(
+		ctxt, "bad@email.com", start, start.AddDate(0, 0, 2),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(res))
+}
+
+func setFatigueInvalidDateRange(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := logic.ReadSetFatigueInDateRange(
+		ctxt, "email@email.com", start, start.AddDate(0, 0, -1),
+	)
+	sbtest.ContainsError(t, types.CouldNotReadAllSetFatigueErr, err)
+	sbtest.ContainsError(t, types.CouldNotReadAllPhysicsDataErr, err)
+}
@@ -0,0 +1,455 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestTraining(t *testing.T) {
+	t.Run("aggregate", trainingAggregate)
+	t.Run("aggregateInvalidArgs", trainingAggregateInvalidArgs)
+	t.Run("acwr", trainingACWR)
+	t.Run("acwrDST", trainingACWRDST)
+	t.Run("monotonyStrain", trainingMonotonyStrain)
+	t.Run("workloadInvalidArgs", trainingWorkloadInvalidArgs)
+}
+
+func trainingWorkouts() []types.Workout {
+	exercise := func(
+		name string, weight types.Kilogram, sets float64, reps int32,
+		effort types.RPE,
+	) types.ExerciseData {
+		return types.ExerciseData{
+			Name: name, Weight: weight, Sets: sets, Reps: reps, Effort: effort,
+		}
+	}
+	workout := func(
+		date time.Time, exercises ...types.ExerciseData,
+	) types.Workout {
+		return types.Workout{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: date,
+			},
+			Exercises: exercises,
+		}
+	}
+
+	// 2025-01-06 and 2025-01-13 are both Mondays
+	return []types.Workout{
+		workout(
+			time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			exercise("Squat", 100, 5, 5, 8),
+			exercise("Bench", 50, 3, 10, 7),
+		),
+		workout(
+			time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC),
+			exercise("Squat", 110, 5, 5, 9),
+		),
+		workout(
+			time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+			exercise("Deadlift", 150, 1, 5, 9),
+		),
+	}
+}
+
+func trainingAggregatesEqual(
+	t *testing.T,
+	l []types.TrainingAggregate,
+	r []types.TrainingAggregate,
+) {
+	sbtest.Eq(t, len(l), len(r))
+	for i := range len(l) {
+		sbtest.True(t, util.DateEqual(l[i].BucketStart, r[i].BucketStart))
+		sbtest.Eq(t, l[i].Group, r[i].Group)
+		sbtest.Eq(t, l[i].NumEntries, r[i].NumEntries)
+		sbtest.EqFloat(
+			t, float64(l[i].TotalVolume), float64(r[i].TotalVolume), 1e-6,
+		)
+		sbtest.EqFloat(
+			t, float64(l[i].AvgVolume), float64(r[i].AvgVolume), 1e-6,
+		)
+		sbtest.EqFloat(
+			t, float64(l[i].TotalExertion), float64(r[i].TotalExertion), 1e-3,
+		)
+		sbtest.EqFloat(
+			t, float64(l[i].AvgExertion), float64(r[i].AvgExertion), 1e-3,
+		)
+		sbtest.EqFloat(
+			t, float64(l[i].TotalReps), float64(r[i].TotalReps), 1e-6,
+		)
+		sbtest.EqFloat(
+			t, float64(l[i].AvgTotalReps), float64(r[i].AvgTotalReps), 1e-6,
+		)
+	}
+}
+
+func trainingAggregate(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+	_, err = logic.CreateWorkouts(ctxt, trainingWorkouts()...)
+	sbtest.Nil(t, err)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	res, err := logic.AggregateTraining(
+		ctxt, "email@email.com", start, end, types.Daily, types.ByExercise,
+	)
+	sbtest.Nil(t, err)
+	trainingAggregatesEqual(t, []types.TrainingAggregate{
+		{
+			BucketStart: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			Group:       "Bench", NumEntries: 1,
+			TotalVolume: 1500, AvgVolume: 1500,
+			TotalExertion: 210, AvgExertion: 210,
+			TotalReps: 30, AvgTotalReps: 30,
+		},
+		{
+			BucketStart: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			Group:       "Squat", NumEntries: 1,
+			TotalVolume: 2500, AvgVolume: 2500,
+			TotalExertion: 200, AvgExertion: 200,
+			TotalReps: 25, AvgTotalReps: 25,
+		},
+		{
+			BucketStart: time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC),
+			Group:       "Squat", NumEntries: 1,
+			TotalVolume: 2750, AvgVolume: 2750,
+			TotalExertion: 225, AvgExertion: 225,
+			TotalReps: 25, AvgTotalReps: 25,
+		},
+		{
+			BucketStart: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+			Group:       "Deadlift", NumEntries: 1,
+			TotalVolume: 750, AvgVolume: 750,
+			TotalExertion: 45, AvgExertion: 45,
+			TotalReps: 5, AvgTotalReps: 5,
+		},
+	}, res)
+
+	res, err = logic.AggregateTraining(
+		ctxt, "email@email.com", start, end, types.Weekly, types.ByExercise,
+	)
+	sbtest.Nil(t, err)
+	trainingAggregatesEqual(t, []types.TrainingAggregate{
+		{
+			BucketStart: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			Group:       "Bench", NumEntries: 1,
+			TotalVolume: 1500, AvgVolume: 1500,
+			TotalExertion: 210, AvgExertion: 210,
+			TotalReps: 30, AvgTotalReps: 30,
+		},
+		{
+			BucketStart: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+			Group:       "Squat", NumEntries: 2,
+			TotalVolume: 5250, AvgVolume: 2625,
+			TotalExertion: 425, AvgExertion: 212.5,
+			TotalReps: 50, AvgTotalReps: 25,
+		},
+		{
+			BucketStart: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+			Group:       "Deadlift", NumEntries: 1,
+			TotalVolume: 750, AvgVolume: 750,
+			TotalExertion: 45, AvgExertion: 45,
+			TotalReps: 5, AvgTotalReps: 5,
+		},
+	}, res)
+
+	res, err = logic.AggregateTraining(
+		ctxt, "email@email.com", start, end, types.Weekly, types.ByExerciseFocus,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 3, len(res))
+	sbtest.Eq(t, types.Bench.String(), res[0].Group)
+	sbtest.Eq(t, types.Squat.String(), res[1].Group)
+	sbtest.Eq(t, types.Deadlift.String(), res[2].Group)
+
+	res, err = logic.AggregateTraining(
+		ctxt, "email@email.com", start, end, types.Monthly, types.ByExerciseKind,
+	)
+	sbtest.Nil(t, err)
+	trainingAggregatesEqual(t, []types.TrainingAggregate{
+		{
+			BucketStart: start,
+			Group:       types.MainCompound.String(), NumEntries: 4,
+			TotalVolume: 6500, AvgVolume: 1625,
+			TotalExertion: 680, AvgExertion: 170,
+			TotalReps: 85, AvgTotalReps: 21.25,
+		},
+	}, res)
+
+	res, err = logic.AggregateTraining(
+		ctxt, "email@email.com", start,
+		time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+		types.Monthly, types.ByExerciseKind,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(res))
+	sbtest.Eq(t, 3, res[0].NumEntries)
+
+	res, err = logic.AggregateTraining(
+		ctxt, "asdf@email.com", start, end, types.Monthly, types.ByExerciseKind,
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(res))
+}
+
+func trainingAggregateInvalidArgs(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := logic.AggregateTraining(
+		ctxt, "email@email.com", end, start, types.Daily, types.ByExercise,
+	)
+	sbtest.ContainsError(
+		t, types.CouldNotAggregateTrainingErr, err,
+		`Start date \(.*\) must be before end date \(.*\)`,
+	)
+
+	_, err = logic.AggregateTraining(
+		ctxt, "email@email.com", start, end,
+		types.TrainingGranularity(-1), types.ByExercise,
+	)
+	sbtest.ContainsError(t, types.InvalidTrainingGranularityErr, err)
+
+	_, err = logic.AggregateTraining(
+		ctxt, "email@email.com", start, end,
+		types.Daily, types.TrainingGroupBy(-1),
+	)
+	sbtest.ContainsError(t, types.InvalidTrainingGroupByErr, err)
+}
+
+func trainingWorkloadSetup(t *testing.T) (context.Context, func()) {
+	ctxt, cleanup := resetApp(t, context.Background())
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+	_, err = logic.CreateWorkouts(ctxt, trainingWorkouts()...)
+	sbtest.Nil(t, err)
+	return ctxt, cleanup
+}
+
+func trainingACWR(t *testing.T) {
+	ctxt, cleanup := trainingWorkloadSetup(t)
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	res, err := logic.CalcACWR(
+		ctxt, "email@email.com", start, end, types.RollingAverage,
+		types.WorkloadOpts{Metric: types.VolumeLoad},
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 9, len(res))
+	for i := range res {
+		sbtest.True(t, util.DateEqual(start.AddDate(0, 0, i), res[i].Date))
+	}
+	sbtest.EqFloat(t, 4000, res[0].Load, 1e-6)
+	sbtest.EqFloat(t, 4000.0/7, res[0].AcuteLoad, 1e-6)
+	sbtest.EqFloat(t, 4000.0/28, res[0].ChronicLoad, 1e-6)
+	sbtest.EqFloat(t, 4, res[0].Ratio, 1e-6)
+	sbtest.EqFloat(t, 0, res[1].Load, 1e-6)
+	sbtest.EqFloat(t, 2750, res[2].Load, 1e-6)
+	sbtest.EqFloat(t, 4, res[2].Ratio, 1e-6)
+	sbtest.EqFloat(t, 750, res[7].Load, 1e-6)
+	sbtest.EqFloat(t, 500, res[7].AcuteLoad, 1e-6)
+	sbtest.EqFloat(t, 7500.0/28, res[7].ChronicLoad, 1e-6)
+	sbtest.EqFloat(t, 28.0/15, res[7].Ratio, 1e-6)
+
+	// Training before the start date fills the windows
+	res, err = logic.CalcACWR(
+		ctxt, "email@email.com", time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC), types.RollingAverage,
+		types.WorkloadOpts{Metric: types.VolumeLoad},
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(res))
+	sbtest.EqFloat(t, 500, res[0].AcuteLoad, 1e-6)
+	sbtest.EqFloat(t, 28.0/15, res[0].Ratio, 1e-6)
+
+	focus := types.Squat
+	res, err = logic.CalcACWR(
+		ctxt, "email@email.com", start, end, types.RollingAverage,
+		types.WorkloadOpts{Metric: types.ExertionLoad, Focus: &focus},
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 9, len(res))
+	sbtest.EqFloat(t, 200, res[0].Load, 1e-3)
+	sbtest.EqFloat(t, 225, res[2].Load, 1e-3)
+	sbtest.EqFloat(t, 0, res[7].Load, 1e-3)
+	sbtest.EqFloat(t, 225.0/7, res[7].AcuteLoad, 1e-3)
+
+	res, err = logic.CalcACWR(
+		ctxt, "email@email.com", start, end, types.EWMA,
+		types.WorkloadOpts{Metric: types.VolumeLoad},
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 9, len(res))
+	sbtest.EqFloat(t, 1000, res[0].AcuteLoad, 1e-6)
+	sbtest.EqFloat(t, 8000.0/29, res[0].ChronicLoad, 1e-6)
+	sbtest.EqFloat(t, 29.0/8, res[0].Ratio, 1e-6)
+
+	res, err = logic.CalcACWR(
+		ctxt, "asdf@email.com", start, end, types.EWMA,
+		types.WorkloadOpts{Metric: types.VolumeLoad},
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 9, len(res))
+	sbtest.EqFloat(t, 0, res[0].Ratio, 1e-6)
+}
+
+func trainingACWRDST(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+	workout := func(
+		date time.Time, weight types.Kilogram, sets float64,
+	) types.Workout {
+		return types.Workout{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: date,
+			},
+			Exercises: []types.ExerciseData{{
+				Name: "Squat", Weight: weight, Sets: sets, Reps: 5, Effort: 8,
+			}},
+		}
+	}
+	_, err = logic.CreateWorkouts(
+		ctxt,
+		workout(time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC), 60, 5),
+		workout(time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), 100, 5),
+		workout(time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC), 100, 1),
+	)
+	sbtest.Nil(t, err)
+
+	// Daylight saving time starts on 2025-03-09 in New York, so that day only
+	// has 23 hours
+	loc, err := time.LoadLocation("America/New_York")
+	sbtest.Nil(t, err)
+	start := time.Date(2025, 3, 8, 0, 0, 0, 0, loc)
+	end := time.Date(2025, 3, 13, 0, 0, 0, 0, loc)
+
+	res, err := logic.CalcACWR(
+		ctxt, "email@email.com", start, end, types.RollingAverage,
+		types.WorkloadOpts{Metric: types.VolumeLoad},
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 5, len(res))
+	for i, l := range []float64{0, 0, 2500, 0, 500} {
+		sbtest.True(t, util.DateEqual(start.AddDate(0, 0, i), res[i].Date))
+		sbtest.EqFloat(t, l, res[i].Load, 1e-6)
+	}
+	// The lookback days before start are also counted across the transition
+	sbtest.EqFloat(t, 1500.0/7, res[0].AcuteLoad, 1e-6)
+	sbtest.EqFloat(t, 4000.0/7, res[2].AcuteLoad, 1e-6)
+	sbtest.EqFloat(t, 4500.0/7, res[4].AcuteLoad, 1e-6)
+}
+
+func trainingMonotonyStrain(t *testing.T) {
+	ctxt, cleanup := trainingWorkloadSetup(t)
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)
+
+	res, err := logic.CalcMonotonyStrain(
+		ctxt, "email@email.com", start, end,
+		types.WorkloadOpts{Metric: types.ExertionLoad},
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 2, len(res))
+	sbtest.True(t, util.DateEqual(start, res[0].Date))
+	sbtest.EqFloat(t, 0, res[0].Load, 1e-3)
+	sbtest.EqFloat(t, 635, res[0].WeeklyLoad, 1e-3)
+	sbtest.True(t, res[0].Monotony.Present)
+	sbtest.EqFloat(t, 0.597927, res[0].Monotony.Value, 1e-5)
+	sbtest.True(t, res[0].Strain.Present)
+	sbtest.EqFloat(t, 379.6835, res[0].Strain.Value, 1e-2)
+	sbtest.True(t, util.DateEqual(start.AddDate(0, 0, 1), res[1].Date))
+	sbtest.EqFloat(t, 45, res[1].Load, 1e-3)
+	sbtest.EqFloat(t, 270, res[1].WeeklyLoad, 1e-3)
+	sbtest.True(t, res[1].Monotony.Present)
+	sbtest.EqFloat(t, 0.496564, res[1].Monotony.Value, 1e-5)
+	sbtest.True(t, res[1].Strain.Present)
+	sbtest.EqFloat(t, 134.0722, res[1].Strain.Value, 1e-2)
+
+	focus := types.Deadlift
+	res, err = logic.CalcMonotonyStrain(
+		ctxt, "email@email.com", start, start.AddDate(0, 0, 1),
+		types.WorkloadOpts{Metric: types.VolumeLoad, Focus: &focus},
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, len(res))
+	// A week without any load has no variation so monotony is undefined
+	sbtest.EqFloat(t, 0, res[0].WeeklyLoad, 1e-6)
+	sbtest.False(t, res[0].Monotony.Present)
+	sbtest.False(t, res[0].Strain.Present)
+}
+
+func trainingWorkloadInvalidArgs(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := logic.CalcACWR(
+		ctxt, "email@email.com", end, start, types.RollingAverage,
+		types.WorkloadOpts{Metric: types.VolumeLoad},
+	)
+	sbtest.ContainsError(
+		t, types.CouldNotCalcACWRErr, err,
+		`Start date \(.*\) must be before end date \(.*\)`,
+	)
+
+	_, err = logic.CalcACWR(
+		ctxt, "email@email.com", start, end, types.ACWRMethod(-1),
+		types.WorkloadOpts{Metric: types.VolumeLoad},
+	)
+	sbtest.ContainsError(t, types.InvalidACWRMethodErr, err)
+
+	_, err = logic.CalcACWR(
+		ctxt, "email@email.com", start, end, types.EWMA,
+		types.WorkloadOpts{Metric: types.WorkloadMetric(-1)},
+	)
+	sbtest.ContainsError(t, types.InvalidWorkloadMetricErr, err)
+
+	focus := types.ExerciseFocus(-1)
+	_, err = logic.CalcMonotonyStrain(
+		ctxt, "email@email.com", start, end,
+		types.WorkloadOpts{Metric: types.VolumeLoad, Focus: &focus},
+	)
+	sbtest.ContainsError(t, types.CouldNotCalcMonotonyStrainErr, err)
+	sbtest.ContainsError(t, types.InvalidExerciseFocusErr, err)
+}
@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/internal/util"
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestWorkoutExport(t *testing.T) {
+	t.Run("roundTrip", workoutExportRoundTrip)
+	t.Run("dateRange", workoutExportDateRange)
+	t.Run("invalidArgs", workoutExportInvalidArgs)
+}
+
+var (
+	workoutExportStart = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	workoutExportEnd   = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func workoutExportUpload(
+	t *testing.T,
+	workoutDir string,
+	timeFormat string,
+) (context.Context, func()) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	err := logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:             "./testData/clientData",
+		ClientCreateType:      types.Create,
+		ExerciseDir:           "./testData/exerciseData",
+		ExerciseCreateType:    types.Create,
+		HyperparamsDir:        "./testData/hyperparamData",
+		HyperparamsCreateType: types.Create,
+		WorkoutDir:            workoutDir,
+		BarPathCalcHyperparams: &types.BarPathCalcHyperparams{
+			MinNumSamples: 5,
+			ApproxErr:     types.SecondOrder,
+			NoiseFilter:   1,
+		},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		Opts:                      sbcsv.Opts{TimeFormat: timeFormat},
+	})
+	sbtest.Nil(t, err)
+	return ctxt, cleanup
+}
+
+func workoutExportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	ctxt, cleanup := workoutExportUpload(t, "./testData/workoutData", "1/2/2006")
+	err := logic.ExportWorkouts(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd, dir,
+	)
+	sbtest.Nil(t, err)
+	expected, err := logic.FindWorkoutsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+	cleanup()
+
+	_, err = os.Stat(filepath.Join(
+		dir, types.ExportPhysDataDir, "two@gmail.com", "2023-02-21.1.0",
+		"Set2.csv",
+	))
+	sbtest.Nil(t, err)
+
+	ctxt, cleanup = workoutExportUpload(
+		t, filepath.Join(dir, types.ExportWorkoutDir), types.ExportDateFormat,
+	)
+	t.Cleanup(cleanup)
+	res, err := logic.FindWorkoutsInDateRange(
+		ctxt, "two@gmail.com", workoutExportStart, workoutExportEnd,
+	)
+	sbtest.Nil(t, err)
+
+	sbtest.Eq(t, 3, len(res))
+	exportedWorkoutsEqual(t, expected, res)
+}
+
+// Checks that the supplied workouts are equal, only comparing the time and
+// position of the physics data because the rest is recalculated when loading.
+func exportedWorkoutsEqual(
+	t *testing.T,
+	expected []types.Workout,
+	res []types.Workout,
+) {
+	sbtest.Eq(t, len(expected), len(res))
+	for i := range expected {
+		sbtest.Eq(t, expected[i].ClientEmail, res[i].ClientEmail)
+		sbtest.Eq(t, expected[i].Session, res[i].Session)
+		sbtest.True(
+			t, util.DateEqual(expected[i].DatePerformed, res[i].DatePerformed),
+		)
+		sbtest.Eq(t, len(expected[i].Exercises), len(res[i].Exercises))
+		for j, e := range expected[i].Exercises {
+			r := res[i].Exercises[j]
+			sbtest.Eq(t, e.Name, r.Name)
+			sbtest.EqFloat(t, float64(e.Weight), float64(r.Weight), 1e-9)
+			sbtest.EqFloat(t, e.Sets, r.Sets, 1e-9)
+			sbtest.Eq(t, e.Reps, r.Reps)
+			sbtest.EqFloat(t, float64(e.Effort), float64(r.Effort), 1e-9)
+			sbtest.Eq(t, len(e.PhysData), len(r.PhysData))
+			for k, p := range e.PhysData {
+				sbtest.Eq(t, p.Present, r.PhysData[k].Present)
+				sbtest.Eq(t, len(p.Value.Time), len(r.PhysData[k].Value.Time))
+				for l := range p.Value.Time {
+					rp := r.PhysData[k].Value
+					sbtest.EqFloat(
+						t, float64(p.Value.Time[l]), float64(rp.Time[l]), 1e-9,
+					)
+					sbtest.EqFloat(
+						t, float64(p.Value.Position[l].X),
+						float64(rp.Position[l].X), 1e-9,
+					)
+					sbtest.EqFloat(
+						t, float64(p.Value.Position[l].Y),
+						float64(rp.Position[l].Y), 1e-9,
+					)
+				}
+			}
+		}
+	}
+}
+
+func workoutExportDateRange(t *testing.T) {
+	dir := t.TempDir()
+
+	ctxt, cleanup := workoutExportUpload(t, "./testData/workoutData", "1/2/2006")
+	t.Cleanup(cleanup)
+
+	err := logic.ExportWorkouts(
+		ctxt, "two@gmail.com",
+		time.Date(2023, 2, 22, 0, 0, 0, 0, time.UTC), workoutExportEnd, dir,
+	)
+	sbtest.Nil(t, err)
+
+	// Only the workouts without physics data are in the range
+	data, err := os.ReadFile(
+		filepath.Join(dir, types.ExportWorkoutDir, "two@gmail.com.csv"),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(
+		t,
+		"DatePerformed,Session,Exercise,Weight,Sets,Reps,Effort,DataDir\n"+
+			"2023-02-23,1,Romanian Deadlift,190,5,3,7.5,\n"+
+			"2023-02-23,1,Saftey Bar Squat,210,4,6,8,\n"+
+			"2023-02-23,1,Barbell Rows,100,4,5,7,\n"+
+			"2023-02-27,1,Squat,305,2,1,8,\n"+
+			"2023-02-27,1,Squat,250,5,3,7,\n",
+		string(data),
+	)
+	_, err = os.Stat(filepath.Join(dir, types.ExportPhysDataDir))
+	sbtest.True(t, os.IsNotExist(err))
+
+	// A client with no workouts in the range has a header only file
+	err = logic.ExportWorkouts(
+		ctxt, "one@gmail.com", workoutExportStart, workoutExportEnd, dir,
+	)
+	sbtest.Nil(t, err)
+	data, err = os.ReadFile(
+		filepath.Join(dir, types.ExportWorkoutDir, "one@gmail.com.csv"),
+	)
+	sbtest.Nil(t, err)
+	sbtest.Eq(
+		t,
+		"DatePerformed,Session,Exercise,Weight,Sets,Reps,Effort,DataDir\n",
+		string(data),
+	)
+}
+
+func workoutExportInvalidArgs(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.ExportWorkouts(
+		ctxt, "email@email.com", workoutExportEnd, workoutExportStart,
+		t.TempDir(),
+	)
+	sbtest.ContainsError(t, types.CouldNotExportWorkoutsErr, err)
+	sbtest.ContainsError(t, types.CouldNotReadAllWorkoutsErr, err)
+}
@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"code.barbellmath.net/barbell-math/providentia/lib/logic"
+	"code.barbellmath.net/barbell-math/providentia/lib/types"
+	sbcsv "code.barbellmath.net/barbell-math/smoothbrain-csv"
+	sbtest "code.barbellmath.net/barbell-math/smoothbrain-test"
+)
+
+func TestWorkoutWatch(t *testing.T) {
+	t.Run("invalidOpts", workoutWatchInvalidOpts)
+	t.Run("doneAndFailed", workoutWatchDoneAndFailed)
+}
+
+func workoutWatchOpts(dir string) *types.WatchWorkoutDirOpts {
+	return &types.WatchWorkoutDirOpts{
+		Opts: sbcsv.Opts{TimeFormat: "1/2/2006"},
+		BarPathCalcHyperparams: &types.BarPathCalcHyperparams{
+			MinNumSamples: 5,
+			ApproxErr:     types.SecondOrder,
+			NoiseFilter:   1,
+		},
+		BarPathTrackerHyperparams: &types.BarPathTrackerHyperparams{},
+		Dir:                       dir,
+		PollInterval:              time.Second,
+		StableFor:                 10 * time.Second,
+	}
+}
+
+func workoutWatchInvalidOpts(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	opts := workoutWatchOpts(t.TempDir())
+	opts.PollInterval = 0
+	err := logic.WatchWorkoutDir(ctxt, opts)
+	sbtest.ContainsError(
+		t, types.CouldNotWatchWorkoutDirErr, err,
+		`PollInterval must be >0`,
+	)
+
+	opts = workoutWatchOpts(t.TempDir())
+	opts.StableFor = 0
+	err = logic.WatchWorkoutDir(ctxt, opts)
+	sbtest.ContainsError(
+		t, types.CouldNotWatchWorkoutDirErr, err,
+		`StableFor must be >0`,
+	)
+
+	opts = workoutWatchOpts(t.TempDir())
+	opts.BarPathCalcHyperparams = nil
+	err = logic.WatchWorkoutDir(ctxt, opts)
+	sbtest.ContainsError(t, types.CouldNotWatchWorkoutDirErr, err)
+
+	opts = workoutWatchOpts(filepath.Join(t.TempDir(), "missing"))
+	err = logic.WatchWorkoutDir(ctxt, opts)
+	sbtest.ContainsError(t, types.CouldNotWatchWorkoutDirErr, err)
+}
+
+// A [types.WatchClock] whose polls are driven by the test. The watcher polls
+// once when it starts and then once for every call to poll.
+type workoutWatchClock struct {
+	start time.Time
+	ticks chan time.Time
+}
+
+func newWorkoutWatchClock() *workoutWatchClock {
+	return &workoutWatchClock{
+		start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ticks: make(chan time.Time),
+	}
+}
+
+func (c *workoutWatchClock) Now() time.Time { return c.start }
+
+func (c *workoutWatchClock) Ticker(time.Duration) (<-chan time.Time, func()) {
+	return c.ticks, func() {}
+}
+
+// Starts a poll at the supplied offset from the start time. The ticks channel
+// is not buffered so this returns once the previous poll has finished.
+func (c *workoutWatchClock) poll(offset time.Duration) {
+	c.ticks <- c.start.Add(offset)
+}
+
+func workoutWatchDoneAndFailed(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.BulkUploadData(ctxt, &types.BulkUploadDataOpts{
+		ClientDir:          "./testData/clientData",
+		ClientCreateType:   types.Create,
+		ExerciseDir:        "./testData/exerciseData",
+		ExerciseCreateType: types.Create,
+	})
+	sbtest.Nil(t, err)
+
+	dir := t.TempDir()
+	data, err := os.ReadFile("./testData/workoutData/two@gmail.com.csv")
+	sbtest.Nil(t, err)
+	data = []byte(strings.ReplaceAll(string(data), "../physData", "physData"))
+	sbtest.Nil(t, os.MkdirAll(filepath.Join(dir, "session1", "physData"), 0755))
+	for _, f := range []string{"Set1.csv", "Set2.csv"} {
+		setData, err := os.ReadFile(filepath.Join("./testData/physData", f))
+		sbtest.Nil(t, err)
+		sbtest.Nil(t, os.WriteFile(
+			filepath.Join(dir, "session1", "physData", f), setData, 0644,
+		))
+	}
+	sbtest.Nil(t, os.WriteFile(
+		filepath.Join(dir, "session1", "two@gmail.com.csv"), data, 0644,
+	))
+	sbtest.Nil(t, os.MkdirAll(filepath.Join(dir, "bad"), 0755))
+	sbtest.Nil(t, os.WriteFile(
+		filepath.Join(dir, "bad", "one@gmail.com.csv"),
+		[]byte("Exercise,DatePerformed,Weight,Sets,Reps,Effort,Session,DataDir\nNotAnExercise,2/21/2023,295,2,1,8,1,\n"),
+		0644,
+	))
+
+	opts := workoutWatchOpts(dir)
+	clock := newWorkoutWatchClock()
+	opts.Clock = clock
+	watchCtxt, cancel := context.WithCancel(ctxt)
+	defer cancel()
+	errs := make(chan error)
+	go func() { errs <- logic.WatchWorkoutDir(watchCtxt, opts) }()
+
+	// A second workout file that references the same data dir is added after
+	// the first poll, so it is not stable when the others are uploaded
+	clock.poll(opts.StableFor / 2)
+	sbtest.Nil(t, os.WriteFile(
+		filepath.Join(dir, "session1", "one@gmail.com.csv"),
+		[]byte("Exercise,DatePerformed,Weight,Sets,Reps,Effort,Session,DataDir\nNotAnExercise,2/21/2023,295,2,1,8,1,physData\n"),
+		0644,
+	))
+	clock.poll(opts.StableFor)
+	// Polling again at the same time does not upload anything new, it only
+	// waits for the previous poll to finish
+	clock.poll(opts.StableFor)
+
+	glob := func(parts ...string) []string {
+		res, err := filepath.Glob(filepath.Join(append([]string{dir}, parts...)...))
+		sbtest.Nil(t, err)
+		return res
+	}
+	sbtest.Eq(t, 1, len(glob(
+		types.WatchDoneDir, "*", "session1", "two@gmail.com.csv",
+	)))
+	sbtest.Eq(t, 1, len(glob(
+		types.WatchFailedDir, "*", "bad", "one@gmail.com.csv",
+	)))
+	failedReport := glob(
+		types.WatchFailedDir, "*", "bad", "one@gmail.com"+types.WatchErrReportExt,
+	)
+	sbtest.Eq(t, 1, len(failedReport))
+	report, err := os.ReadFile(failedReport[0])
+	sbtest.Nil(t, err)
+	sbtest.True(t, len(report) > 0)
+
+	// The data dir is still referenced by the second workout file so it is
+	// not moved with the first
+	_, err = os.Stat(filepath.Join(dir, "session1", "physData", "Set1.csv"))
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 0, len(glob(
+		types.WatchDoneDir, "*", "session1", "physData",
+	)))
+
+	// Once the second workout file fails the data dir is moved with it
+	clock.poll(2 * opts.StableFor)
+	clock.poll(2 * opts.StableFor)
+	cancel()
+	sbtest.Nil(t, <-errs)
+
+	sbtest.Eq(t, 1, len(glob(
+		types.WatchFailedDir, "*", "session1", "one@gmail.com.csv",
+	)))
+	sbtest.Eq(t, 1, len(glob(
+		types.WatchFailedDir, "*", "session1", "physData", "Set1.csv",
+	)))
+	for _, p := range []string{
+		filepath.Join(dir, "session1", "two@gmail.com.csv"),
+		filepath.Join(dir, "session1", "one@gmail.com.csv"),
+		filepath.Join(dir, "session1", "physData"),
+		filepath.Join(dir, "bad", "one@gmail.com.csv"),
+	} {
+		_, err = os.Stat(p)
+		sbtest.True(t, os.IsNotExist(err))
+	}
+
+	numWorkouts, err := logic.ReadNumWorkoutsForClient(ctxt, "two@gmail.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numWorkouts, 3)
+	numWorkouts, err = logic.ReadNumWorkoutsForClient(ctxt, "one@gmail.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, numWorkouts, 0)
+}
@@ -2,9 +2,11 @@ package tests
 
 import (
 	"context"
+	"iter"
 	"testing"
 	"time"
 
+	"code.barbellmath.net/barbell-math/providentia/internal/dal/migrations"
 	"code.barbellmath.net/barbell-math/providentia/internal/util"
 	"code.barbellmath.net/barbell-math/providentia/lib/logic"
 	"code.barbellmath.net/barbell-math/providentia/lib/types"
@@ -26,27 +28,34 @@ var (
 		Work:         []types.Joule{},
 		Power:        []types.Watt{},
 
-		RepSplits: []types.Split{},
+		RepSplits: []types.Split{{StartIdx: 0, EndIdx: 4}},
 
-		MinVel: []types.PointInTime[types.Second, types.MeterPerSec]{},
-		MaxVel: []types.PointInTime[types.Second, types.MeterPerSec]{},
+		MinVel: []types.PointInTime[types.Second, types.MeterPerSec]{{Time: 0, Value: 1}},
+		MaxVel: []types.PointInTime[types.Second, types.MeterPerSec]{{Time: 1, Value: 2}},
 
-		MinAcc: []types.PointInTime[types.Second, types.MeterPerSec2]{},
-		MaxAcc: []types.PointInTime[types.Second, types.MeterPerSec2]{},
+		MinAcc: []types.PointInTime[types.Second, types.MeterPerSec2]{{Time: 0, Value: 3}},
+		MaxAcc: []types.PointInTime[types.Second, types.MeterPerSec2]{{Time: 1, Value: 4}},
 
-		MinForce: []types.PointInTime[types.Second, types.Newton]{},
-		MaxForce: []types.PointInTime[types.Second, types.Newton]{},
+		MinForce: []types.PointInTime[types.Second, types.Newton]{{Time: 0, Value: 5}},
+		MaxForce: []types.PointInTime[types.Second, types.Newton]{{Time: 1, Value: 6}},
 
-		MinImpulse: []types.PointInTime[types.Second, types.NewtonSec]{},
-		MaxImpulse: []types.PointInTime[types.Second, types.NewtonSec]{},
+		MinImpulse: []types.PointInTime[types.Second, types.NewtonSec]{{Time: 0, Value: 7}},
+		MaxImpulse: []types.PointInTime[types.Second, types.NewtonSec]{{Time: 1, Value: 8}},
 
-		AvgWork: []types.Joule{},
-		MinWork: []types.PointInTime[types.Second, types.Joule]{},
-		MaxWork: []types.PointInTime[types.Second, types.Joule]{},
+		AvgWork: []types.Joule{9},
+		MinWork: []types.PointInTime[types.Second, types.Joule]{{Time: 0, Value: 10}},
+		MaxWork: []types.PointInTime[types.Second, types.Joule]{{Time: 1, Value: 11}},
 
-		AvgPower: []types.Watt{},
-		MinPower: []types.PointInTime[types.Second, types.Watt]{},
-		MaxPower: []types.PointInTime[types.Second, types.Watt]{},
+		AvgPower: []types.Watt{12},
+		MinPower: []types.PointInTime[types.Second, types.Watt]{{Time: 0, Value: 13}},
+		MaxPower: []types.PointInTime[types.Second, types.Watt]{{Time: 1, Value: 14}},
+
+		MeanConcentricVel: []types.MeterPerSec{0.5},
+		MeanPropulsiveVel: []types.MeterPerSec{0.6},
+		TimeToPeakVel:     []types.Second{1},
+		ConcentricDur:     []types.Second{2},
+		EccentricDur:      []types.Second{1},
+		RangeOfMotion:     []types.Meter{3},
 	}
 
 	testPhysicsData2 = types.PhysicsData{
@@ -85,6 +94,13 @@ var (
 		AvgPower: []types.Watt{},
 		MinPower: []types.PointInTime[types.Second, types.Watt]{},
 		MaxPower: []types.PointInTime[types.Second, types.Watt]{},
+
+		MeanConcentricVel: []types.MeterPerSec{},
+		MeanPropulsiveVel: []types.MeterPerSec{},
+		TimeToPeakVel:     []types.Second{},
+		ConcentricDur:     []types.Second{},
+		EccentricDur:      []types.Second{},
+		RangeOfMotion:     []types.Meter{},
 	}
 )
 
@@ -191,6 +207,111 @@ func workoutsEqual(
 					l[i].Exercises[j].PhysData[k].Value.Power,
 					r[i].Exercises[j].PhysData[k].Value.Power,
 				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.RepSplits,
+					r[i].Exercises[j].PhysData[k].Value.RepSplits,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MinVel,
+					r[i].Exercises[j].PhysData[k].Value.MinVel,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MaxVel,
+					r[i].Exercises[j].PhysData[k].Value.MaxVel,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MinAcc,
+					r[i].Exercises[j].PhysData[k].Value.MinAcc,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MaxAcc,
+					r[i].Exercises[j].PhysData[k].Value.MaxAcc,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MinForce,
+					r[i].Exercises[j].PhysData[k].Value.MinForce,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MaxForce,
+					r[i].Exercises[j].PhysData[k].Value.MaxForce,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MinImpulse,
+					r[i].Exercises[j].PhysData[k].Value.MinImpulse,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MaxImpulse,
+					r[i].Exercises[j].PhysData[k].Value.MaxImpulse,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.AvgWork,
+					r[i].Exercises[j].PhysData[k].Value.AvgWork,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MinWork,
+					r[i].Exercises[j].PhysData[k].Value.MinWork,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MaxWork,
+					r[i].Exercises[j].PhysData[k].Value.MaxWork,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.AvgPower,
+					r[i].Exercises[j].PhysData[k].Value.AvgPower,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MinPower,
+					r[i].Exercises[j].PhysData[k].Value.MinPower,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MaxPower,
+					r[i].Exercises[j].PhysData[k].Value.MaxPower,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MeanConcentricVel,
+					r[i].Exercises[j].PhysData[k].Value.MeanConcentricVel,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.MeanPropulsiveVel,
+					r[i].Exercises[j].PhysData[k].Value.MeanPropulsiveVel,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.TimeToPeakVel,
+					r[i].Exercises[j].PhysData[k].Value.TimeToPeakVel,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.ConcentricDur,
+					r[i].Exercises[j].PhysData[k].Value.ConcentricDur,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.EccentricDur,
+					r[i].Exercises[j].PhysData[k].Value.EccentricDur,
+				)
+				sbtest.SlicesMatch(
+					t,
+					l[i].Exercises[j].PhysData[k].Value.RangeOfMotion,
+					r[i].Exercises[j].PhysData[k].Value.RangeOfMotion,
+				)
 			}
 		}
 	}
@@ -204,6 +325,11 @@ func TestWorkout(t *testing.T) {
 	t.Run("createFindBetweenDates", workoutCreateFindBetweenDates)
 	t.Run("createDeletePhysData", workoutCreateDeletePhysData)
 	t.Run("createDeleteBetweenDates", workoutCreateDeleteBetweenDates)
+	t.Run("createUpdateNoPhysData", workoutCreateUpdateNoPhysData)
+	t.Run("createUpdatePhysData", workoutCreateUpdatePhysData)
+	t.Run("createStreamById", workoutCreateStreamById)
+	t.Run("createStreamBetweenDates", workoutCreateStreamBetweenDates)
+	t.Run("createReadSummaries", workoutCreateReadSummaries)
 }
 
 func workoutCreateReadNoPhysData(t *testing.T) {
@@ -235,7 +361,7 @@ func workoutCreateReadNoPhysData(t *testing.T) {
 			},
 		},
 	}
-	err = logic.CreateWorkouts(ctxt, workouts...)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
 	sbtest.Nil(t, err)
 
 	res, err := logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
@@ -246,7 +372,7 @@ func workoutCreateReadNoPhysData(t *testing.T) {
 	sbtest.Nil(t, err)
 	sbtest.Eq(t, 1, n)
 
-	err = logic.CreateWorkouts(ctxt, workouts...)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
 	sbtest.ContainsError(t, types.CouldNotCreateAllWorkoutsErr, err)
 	sbtest.ContainsError(
 		t, types.CouldNotCreateAllTrainingLogsErr, err,
@@ -301,7 +427,7 @@ func workoutCreateReadPhysData(t *testing.T) {
 			},
 		},
 	}
-	err = logic.CreateWorkouts(ctxt, workouts...)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
 	sbtest.Nil(t, err)
 
 	res, err := logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
@@ -312,7 +438,7 @@ func workoutCreateReadPhysData(t *testing.T) {
 	sbtest.Nil(t, err)
 	sbtest.Eq(t, 1, n)
 
-	err = logic.CreateWorkouts(ctxt, workouts...)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
 	sbtest.ContainsError(t, types.CouldNotCreateAllWorkoutsErr, err)
 	sbtest.ContainsError(
 		t, types.CouldNotCreateAllTrainingLogsErr, err,
@@ -366,7 +492,7 @@ func workoutCreateFindNoPhysData(t *testing.T) {
 			},
 		},
 	}
-	err = logic.CreateWorkouts(ctxt, workouts[0].Value)
+	_, err = logic.CreateWorkouts(ctxt, workouts[0].Value)
 	sbtest.Nil(t, err)
 
 	res, err := logic.FindWorkoutsById(ctxt, workouts[0].Value.WorkoutId)
@@ -427,7 +553,7 @@ func workoutCreateFindPhysData(t *testing.T) {
 			},
 		},
 	}
-	err = logic.CreateWorkouts(ctxt, workouts[0].Value)
+	_, err = logic.CreateWorkouts(ctxt, workouts[0].Value)
 	sbtest.Nil(t, err)
 
 	res, err := logic.FindWorkoutsById(ctxt, workouts[0].Value.WorkoutId)
@@ -521,7 +647,7 @@ func workoutCreateFindBetweenDates(t *testing.T) {
 			},
 		},
 	}
-	err = logic.CreateWorkouts(ctxt, workouts...)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
 	sbtest.Nil(t, err)
 
 	res, err := logic.FindWorkoutsInDateRange(
@@ -602,7 +728,7 @@ func workoutCreateDeletePhysData(t *testing.T) {
 			},
 		},
 	}
-	err = logic.CreateWorkouts(ctxt, workouts...)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
 	sbtest.Nil(t, err)
 
 	n, err := logic.ReadNumWorkoutsForClient(ctxt, "email@email.com")
@@ -668,7 +794,7 @@ func workoutCreateDeleteBetweenDates(t *testing.T) {
 			},
 		},
 	}
-	err = logic.CreateWorkouts(ctxt, workouts...)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
 	sbtest.Nil(t, err)
 
 	n, err := logic.ReadNumWorkoutsForClient(ctxt, "email@email.com")
@@ -710,3 +836,558 @@ func workoutCreateDeleteBetweenDates(t *testing.T) {
 	sbtest.Nil(t, err)
 	sbtest.Eq(t, 0, n)
 }
+
+func workoutCreateUpdateNoPhysData(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	workouts := []types.Workout{
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: time.Now(),
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Squat",
+					Weight: 365,
+					Sets:   5,
+					Reps:   5,
+					Effort: 10,
+				}, {
+					Name:   "Bench",
+					Weight: 225,
+					Sets:   3,
+					Reps:   3,
+					Effort: 9,
+				},
+			},
+		},
+	}
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
+	sbtest.Nil(t, err)
+
+	workouts[0].Exercises[0].Weight = 345
+	workouts[0].Exercises[1].Name = "Deadlift"
+	workouts[0].Exercises[1].Reps = 2
+	workouts[0].Exercises[1].Effort = 8.5
+	err = logic.UpdateWorkouts(ctxt, workouts...)
+	sbtest.Nil(t, err)
+
+	res, err := logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	workoutsEqual(t, workouts, res)
+
+	n, err := logic.ReadNumWorkoutsForClient(ctxt, "email@email.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, n)
+
+	badWorkout := workouts[0]
+	badWorkout.Exercises = badWorkout.Exercises[:1]
+	err = logic.UpdateWorkouts(ctxt, badWorkout)
+	sbtest.ContainsError(
+		t, types.CouldNotUpdateAllWorkoutsErr, err,
+		`Workout with id '{ClientEmail:email@email.com Session:1 DatePerformed:.*}' has 1 exercises, expected 2 \(Exercises cannot be added or removed\)`,
+	)
+
+	badWorkout = workouts[0]
+	badWorkout.Exercises = []types.ExerciseData{
+		workouts[0].Exercises[0], workouts[0].Exercises[1],
+	}
+	badWorkout.Exercises[1].Effort = 11
+	err = logic.UpdateWorkouts(ctxt, badWorkout)
+	sbtest.ContainsError(t, types.CouldNotUpdateAllWorkoutsErr, err)
+	sbtest.ContainsError(
+		t, types.CouldNotUpdateAllTrainingLogsErr, err,
+		`violates check constraint "training_log_effort_check" \(SQLSTATE 23514\)`,
+	)
+
+	badWorkout = workouts[0]
+	badWorkout.Exercises = []types.ExerciseData{
+		workouts[0].Exercises[0], workouts[0].Exercises[1],
+	}
+	badWorkout.Exercises[0].PhysData = []types.Optional[types.PhysicsData]{
+		{}, {},
+	}
+	err = logic.UpdateWorkouts(ctxt, badWorkout)
+	sbtest.ContainsError(
+		t, types.CouldNotUpdateAllWorkoutsErr, err,
+		`Exercise 0 of workout with id '{ClientEmail:email@email.com Session:1 DatePerformed:.*}' has 2 physics data entries, expected 0 or 5 \(one per set\)`,
+	)
+
+	err = logic.UpdateWorkouts(ctxt, types.Workout{
+		WorkoutId: types.WorkoutId{
+			ClientEmail:   "asdf",
+			Session:       1,
+			DatePerformed: time.Now(),
+		},
+	})
+	sbtest.ContainsError(t, types.CouldNotUpdateAllWorkoutsErr, err)
+	sbtest.ContainsError(
+		t, types.CouldNotReadAllWorkoutsErr, err,
+		`Could not read entry with id '{ClientEmail:asdf Session:1 DatePerformed:.*}' \(Does id exist\?\)`,
+	)
+
+	res, err = logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	workoutsEqual(t, workouts, res)
+}
+
+func workoutCreateUpdatePhysData(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	calcParams := testingCalcHyperparams
+	calcParams.Version = 1
+	err = logic.CreateHyperparams(ctxt, calcParams)
+	sbtest.Nil(t, err)
+
+	rawData := logic.BarPathTimeSeriesData(types.RawTimeSeriesData{
+		TimeData: []types.Second{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13},
+		PositionData: []types.Vec2[types.Meter, types.Meter]{
+			{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2},
+			{X: 3, Y: 3},
+			{X: 2, Y: 2}, {X: 1, Y: 1}, {X: 0, Y: 0},
+			{X: 1, Y: 1}, {X: 2, Y: 2},
+			{X: 3, Y: 3},
+			{X: 2, Y: 2}, {X: 1, Y: 1}, {X: 0, Y: 0},
+		},
+	})
+	calcExerciseData := func(e types.ExerciseData) types.ExerciseData {
+		err := logic.CalcPhysicsData(
+			ctxt,
+			&calcParams,
+			&migrations.BarPathTrackerHyperparamsSetupData[0],
+			&e,
+			rawData, rawData,
+		)
+		sbtest.Nil(t, err)
+		return e
+	}
+
+	workouts := []types.Workout{
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: time.Now(),
+			},
+			Exercises: []types.ExerciseData{
+				calcExerciseData(types.ExerciseData{
+					Name:   "Squat",
+					Weight: 100,
+					Sets:   2,
+					Reps:   2,
+					Effort: 10,
+				}),
+			},
+		},
+	}
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
+	sbtest.Nil(t, err)
+
+	res, err := logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	workoutsEqual(t, workouts, res)
+	sbtest.Eq(t, 1, res[0].Exercises[0].PhysData[0].Value.BarPathCalcVersion)
+
+	// Changing the effort does not change any physics inputs, so the physics
+	// data should not be recalculated or rewritten even though the supplied
+	// derived physics data was removed.
+	res[0].Exercises[0].Effort = 9
+	res[0].Exercises[0].PhysData[0].Value.Velocity = nil
+	err = logic.UpdateWorkouts(ctxt, res...)
+	sbtest.Nil(t, err)
+	workouts[0].Exercises[0].Effort = 9
+
+	res, err = logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	workoutsEqual(t, workouts, res)
+
+	// Changing the weight changes the force, so the physics data should be
+	// recalculated.
+	res[0].Exercises[0].Weight = 200
+	err = logic.UpdateWorkouts(ctxt, res...)
+	sbtest.Nil(t, err)
+	workouts[0].Exercises[0] = calcExerciseData(types.ExerciseData{
+		Name:   "Squat",
+		Weight: 200,
+		Sets:   2,
+		Reps:   2,
+		Effort: 9,
+	})
+
+	res, err = logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	workoutsEqual(t, workouts, res)
+	sbtest.Eq(t, 1, res[0].Exercises[0].PhysData[1].Value.BarPathCalcVersion)
+
+	// Removing the physics data for a set should delete it.
+	res[0].Exercises[0].PhysData[1].Present = false
+	err = logic.UpdateWorkouts(ctxt, res...)
+	sbtest.Nil(t, err)
+	workouts[0].Exercises[0].PhysData[1] = types.Optional[types.PhysicsData]{}
+
+	res, err = logic.ReadWorkoutsById(ctxt, workouts[0].WorkoutId)
+	sbtest.Nil(t, err)
+	workoutsEqual(t, workouts, res)
+
+	// Physics data with no bar path input can not be recalculated.
+	res[0].Exercises[0].PhysData[1] = types.Optional[types.PhysicsData]{
+		Present: true,
+	}
+	err = logic.UpdateWorkouts(ctxt, res...)
+	sbtest.ContainsError(t, types.CouldNotUpdateAllWorkoutsErr, err)
+	sbtest.ContainsError(
+		t, types.PhysicsJobQueueErr, err,
+		`Set 2 of exercise 'Squat' has physics data but no bar path data`,
+	)
+
+	n, err := logic.ReadNumWorkoutsForClient(ctxt, "email@email.com")
+	sbtest.Nil(t, err)
+	sbtest.Eq(t, 1, n)
+}
+
+func streamWorkoutsTestData(startTime time.Time) []types.Workout {
+	return []types.Workout{
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: startTime,
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Squat",
+					Weight: 365,
+					Sets:   2,
+					Reps:   2,
+					Effort: 10,
+					PhysData: []types.Optional[types.PhysicsData]{
+						{Present: true, Value: testPhysicsData1},
+						{Present: true, Value: testPhysicsData2},
+					},
+				},
+				{
+					Name:   "Bench",
+					Weight: 225,
+					Sets:   1,
+					Reps:   1,
+					Effort: 10,
+				},
+			},
+		},
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       2,
+				DatePerformed: startTime,
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Bench",
+					Weight: 225,
+					Sets:   1,
+					Reps:   1,
+					Effort: 10,
+					PhysData: []types.Optional[types.PhysicsData]{
+						{Present: true, Value: testPhysicsData1},
+					},
+				},
+			},
+		},
+		{
+			WorkoutId: types.WorkoutId{
+				ClientEmail:   "email@email.com",
+				Session:       1,
+				DatePerformed: startTime.Add(24 * time.Hour),
+			},
+			Exercises: []types.ExerciseData{
+				{
+					Name:   "Deadlift",
+					Weight: 405,
+					Sets:   1,
+					Reps:   1,
+					Effort: 10,
+				},
+			},
+		},
+	}
+}
+
+func withoutPhysData(workouts []types.Workout) []types.Workout {
+	res := make([]types.Workout, len(workouts))
+	for i, w := range workouts {
+		res[i] = types.Workout{
+			WorkoutId: w.WorkoutId,
+			Exercises: make([]types.ExerciseData, len(w.Exercises)),
+		}
+		for j, e := range w.Exercises {
+			e.PhysData = nil
+			res[i].Exercises[j] = e
+		}
+	}
+	return res
+}
+
+func collectWorkouts(
+	t *testing.T,
+	seq iter.Seq2[types.Workout, error],
+) []types.Workout {
+	res := []types.Workout{}
+	for w, err := range seq {
+		sbtest.Nil(t, err)
+		res = append(res, w)
+	}
+	return res
+}
+
+func workoutCreateStreamById(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	startTime := time.Now().Truncate(24 * time.Hour)
+	workouts := streamWorkoutsTestData(startTime)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
+	sbtest.Nil(t, err)
+
+	ids := []types.WorkoutId{
+		workouts[2].WorkoutId, workouts[0].WorkoutId, workouts[1].WorkoutId,
+	}
+	res := collectWorkouts(t, logic.StreamWorkoutsById(
+		ctxt, types.StreamWorkoutsOpts{}, ids...,
+	))
+	workoutsEqual(
+		t, []types.Workout{workouts[2], workouts[0], workouts[1]}, res,
+	)
+
+	res = collectWorkouts(t, logic.StreamWorkoutsById(
+		ctxt, types.StreamWorkoutsOpts{SkipPhysData: true}, ids...,
+	))
+	workoutsEqual(
+		t,
+		withoutPhysData([]types.Workout{workouts[2], workouts[0], workouts[1]}),
+		res,
+	)
+	for _, w := range res {
+		for _, e := range w.Exercises {
+			sbtest.Eq(t, 0, len(e.PhysData))
+			sbtest.Eq(t, 0, len(e.SetFatigue))
+		}
+	}
+
+	cnt := 0
+	for _, err := range logic.StreamWorkoutsById(
+		ctxt, types.StreamWorkoutsOpts{}, ids...,
+	) {
+		sbtest.Nil(t, err)
+		cnt++
+		break
+	}
+	sbtest.Eq(t, 1, cnt)
+
+	cnt = 0
+	for _, err := range logic.StreamWorkoutsById(
+		ctxt, types.StreamWorkoutsOpts{},
+		workouts[0].WorkoutId,
+		types.WorkoutId{ClientEmail: "email@email.com", Session: 3},
+	) {
+		cnt++
+		if cnt == 2 {
+			sbtest.ContainsError(
+				t, types.CouldNotReadAllWorkoutsErr, err,
+				`Could not read entry with id .* \(Does id exist\?\)`,
+			)
+		} else {
+			sbtest.Nil(t, err)
+		}
+	}
+	sbtest.Eq(t, 2, cnt)
+}
+
+func workoutCreateStreamBetweenDates(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	startTime := time.Now().Truncate(24 * time.Hour)
+	workouts := streamWorkoutsTestData(startTime)
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
+	sbtest.Nil(t, err)
+
+	// Set the batch size smaller than the number of rows so multiple fetches
+	// from the cursor are needed.
+	state, ok := logic.StateFromContext(ctxt)
+	sbtest.True(t, ok)
+	state.Global.BatchSize = 2
+
+	res := collectWorkouts(t, logic.StreamWorkoutsInDateRange(
+		ctxt, "email@email.com",
+		startTime.Add(-1*time.Hour), startTime.Add(48*time.Hour),
+		types.StreamWorkoutsOpts{},
+	))
+	workoutsEqual(t, workouts, res)
+
+	res = collectWorkouts(t, logic.StreamWorkoutsInDateRange(
+		ctxt, "email@email.com",
+		startTime.Add(-1*time.Hour), startTime.Add(48*time.Hour),
+		types.StreamWorkoutsOpts{SkipPhysData: true},
+	))
+	workoutsEqual(t, withoutPhysData(workouts), res)
+
+	res = collectWorkouts(t, logic.StreamWorkoutsInDateRange(
+		ctxt, "email@email.com",
+		startTime.Add(23*time.Hour), startTime.Add(48*time.Hour),
+		types.StreamWorkoutsOpts{},
+	))
+	workoutsEqual(t, workouts[2:], res)
+
+	cnt := 0
+	for w, err := range logic.StreamWorkoutsInDateRange(
+		ctxt, "email@email.com",
+		startTime.Add(-1*time.Hour), startTime.Add(48*time.Hour),
+		types.StreamWorkoutsOpts{},
+	) {
+		sbtest.Nil(t, err)
+		workoutsEqual(t, workouts[:1], []types.Workout{w})
+		cnt++
+		break
+	}
+	sbtest.Eq(t, 1, cnt)
+
+	for _, err := range logic.StreamWorkoutsInDateRange(
+		ctxt, "email@email.com",
+		time.Now().Add(1*time.Hour), time.Now(),
+		types.StreamWorkoutsOpts{},
+	) {
+		sbtest.ContainsError(
+			t, types.CouldNotReadAllWorkoutsErr, err,
+			`Start date \(.*\) must be before end date \(.*\)`,
+		)
+	}
+}
+
+func workoutSummariesEqual(
+	t *testing.T,
+	l []types.Workout,
+	r []types.WorkoutSummary,
+) {
+	sbtest.Eq(t, len(l), len(r))
+
+	for i := range len(l) {
+		sbtest.Eq(t, l[i].WorkoutId.ClientEmail, r[i].WorkoutId.ClientEmail)
+		sbtest.Eq(t, l[i].WorkoutId.Session, r[i].WorkoutId.Session)
+		sbtest.True(t, util.DateEqual(
+			l[i].WorkoutId.DatePerformed, r[i].WorkoutId.DatePerformed,
+		))
+		sbtest.Eq(t, len(l[i].Exercises), len(r[i].Exercises))
+
+		for j := range len(l[i].Exercises) {
+			le, re := &l[i].Exercises[j], &r[i].Exercises[j]
+			sbtest.Eq(t, le.Name, re.Name)
+			sbtest.Eq(t, le.Weight, re.Weight)
+			sbtest.Eq(t, le.Sets, re.Sets)
+			sbtest.Eq(t, le.Reps, re.Reps)
+			sbtest.Eq(t, le.Effort, re.Effort)
+			sbtest.Eq(
+				t,
+				types.Kilogram(le.Sets*float64(le.Reps))*le.Weight,
+				re.AbstractData.Volume,
+			)
+			sbtest.Eq(t, le.Sets*float64(le.Reps), re.AbstractData.TotalReps)
+
+			sbtest.Eq(t, int(le.Sets), len(re.HasPhysData))
+			for k := range len(re.HasPhysData) {
+				sbtest.Eq(
+					t,
+					k < len(le.PhysData) && le.PhysData[k].Present,
+					re.HasPhysData[k],
+				)
+			}
+		}
+	}
+}
+
+func workoutCreateReadSummaries(t *testing.T) {
+	ctxt, cleanup := resetApp(t, context.Background())
+	t.Cleanup(cleanup)
+
+	err := logic.CreateClients(ctxt, types.Client{
+		FirstName: "FName",
+		LastName:  "LName",
+		Email:     "email@email.com",
+	})
+	sbtest.Nil(t, err)
+
+	startTime := time.Now().Truncate(24 * time.Hour)
+	workouts := streamWorkoutsTestData(startTime)
+	workouts[0].Exercises[0].PhysData[0].Present = false
+	_, err = logic.CreateWorkouts(ctxt, workouts...)
+	sbtest.Nil(t, err)
+
+	res, err := logic.ReadWorkoutSummariesById(
+		ctxt, workouts[2].WorkoutId, workouts[0].WorkoutId,
+	)
+	sbtest.Nil(t, err)
+	workoutSummariesEqual(
+		t, []types.Workout{workouts[2], workouts[0]}, res,
+	)
+
+	_, err = logic.ReadWorkoutSummariesById(
+		ctxt, types.WorkoutId{ClientEmail: "email@email.com", Session: 3},
+	)
+	sbtest.ContainsError(
+		t, types.CouldNotReadAllWorkoutsErr, err,
+		`Could not read entry with id .* \(Does id exist\?\)`,
+	)
+
+	res, err = logic.FindWorkoutSummariesInDateRange(
+		ctxt, "email@email.com",
+		startTime.Add(-1*time.Hour), startTime.Add(48*time.Hour),
+	)
+	sbtest.Nil(t, err)
+	workoutSummariesEqual(t, workouts, res)
+
+	res, err = logic.FindWorkoutSummariesInDateRange(
+		ctxt, "email@email.com",
+		startTime.Add(-1*time.Hour), startTime.Add(23*time.Hour),
+	)
+	sbtest.Nil(t, err)
+	workoutSummariesEqual(t, workouts[:2], res)
+
+	_, err = logic.FindWorkoutSummariesInDateRange(
+		ctxt, "email@email.com", time.Now().Add(1*time.Hour), time.Now(),
+	)
+	sbtest.ContainsError(
+		t, types.CouldNotReadAllWorkoutsErr, err,
+		`Start date \(.*\) must be before end date \(.*\)`,
+	)
+}